@@ -1,85 +1,166 @@
 package main
 
 import (
+	"crypto/tls"
+	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
+	"sort"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/pranahonk/sabda-scraper-go/internal/handlers"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
 	"github.com/pranahonk/sabda-scraper-go/internal/services"
 	"github.com/pranahonk/sabda-scraper-go/pkg/config"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+// Build metadata, set at build time with
+// -ldflags "-X main.version=... -X main.commit=... -X main.buildTime=..."
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildTime = "unknown"
 )
 
 func main() {
 	// Load configuration
 	cfg := config.Load()
 
-	log.Printf("Starting SABDA Scraper API on port %s", cfg.Server.Port)
+	log.Printf("Starting SABDA Scraper API %s (commit %s, built %s) on port %s", version, commit, buildTime, cfg.Server.Port)
 	log.Printf("Debug mode: %v", cfg.Server.Debug)
 	log.Printf("Cache TTL: %v", cfg.Cache.TTL)
 	log.Printf("Rate limit: %d requests/minute", cfg.Rate.MaxRequestsPerMinute)
 
 	// Initialize services
-	cacheService := services.NewCacheService(cfg.Cache.TTL, cfg.Cache.MaxSize)
-	rateLimitService := services.NewRateLimitService(cfg.Rate.MaxRequestsPerMinute, cfg.Rate.WindowDuration)
+	cacheService := services.NewCacheService(cfg.Cache.TTL, cfg.Cache.NegativeTTL, cfg.Cache.MaxSize, cfg.Cache.CleanupInterval, cfg.Cache.CleanupJitter, cfg.Cache.RefreshAheadWindow, cfg.Cache.RefreshAheadMinHits, cfg.Cache.SkipStaleWrites)
+	rateLimitService := newRateLimiter(cfg.Rate, cfg.Rate.MaxRequestsPerMinute)
+	batchRateLimitService := newRateLimiter(cfg.Rate, cfg.Rate.BatchTokenRequestsPerMinute)
+	warmRateLimitService := newRateLimiter(cfg.Rate, cfg.Rate.WarmRequestsPerMinute)
+	auditLogger, err := services.NewAuditLogger(cfg.Audit.Destination)
+	if err != nil {
+		log.Fatalf("Failed to open audit log: %v", err)
+	}
 	authService := services.NewAuthService(
 		cfg.JWT.SecretKey,
 		cfg.JWT.ExpirationDelta,
-		map[string]string{
-			"flutter": cfg.API.FlutterKey,
-			"mobile":  cfg.API.MobileKey,
-		},
+		clientKeys(cfg.API),
+		scopedKeys(cfg.API),
+		auditLogger,
 	)
-	scraperService := services.NewScraperService(cfg.Server.Debug, cacheService)
+	var historyStore *services.HistoryStore
+	if cfg.Analytics.Store != "" {
+		store, err := services.NewHistoryStore(cfg.Analytics.Store, cfg.Analytics.BufferSize)
+		if err != nil {
+			log.Fatalf("Failed to open scrape history: %v", err)
+		}
+		historyStore = store
+		log.Printf("Recording scrape history to %s", cfg.Analytics.Store)
+	}
+	var scriptureService *services.ScriptureService
+	if cfg.Scripture.APIURL != "" {
+		scriptureService = services.NewScriptureService(cfg.Scripture.APIURL, cfg.Scripture.TextField, cfg.Scripture.Timeout, cfg.Scripture.CacheTTL, cfg.Cache.CleanupInterval, cfg.Cache.CleanupJitter)
+		log.Printf("Fetching missing scripture text from %s", cfg.Scripture.APIURL)
+	}
+	scraperService := services.NewScraperService(cfg.Server.Debug, cfg.Scraper, cacheService, historyStore, services.NewRecentScrapes(cfg.Diagnostics.HistorySize), scriptureService)
+	if err := services.RegisterScraperMetrics(prometheus.DefaultRegisterer, scraperService); err != nil {
+		log.Fatalf("Failed to register metrics: %v", err)
+	}
 
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService, rateLimitService)
-	sabdaHandler := handlers.NewSABDAHandler(scraperService)
+	authHandler := handlers.NewAuthHandler(authService, rateLimitService, batchRateLimitService, auditLogger)
+	statsService := services.NewStatsService(cfg.Stats.TopN, cfg.Stats.Stopwords)
+	var selfTestService *services.SelfTestService
+	if cfg.SelfTest.Interval > 0 {
+		selfTestService = services.NewSelfTestService(scraperService, scraper.Target{
+			Publication: scraper.DefaultPublication,
+			Year:        cfg.SelfTest.Year,
+			Date:        cfg.SelfTest.MonthDay,
+		}, cfg.SelfTest.MinQuality, cfg.SelfTest.Interval, cfg.SelfTest.Jitter, cfg.Scraper.BackgroundTimeout)
+		log.Printf("Extraction self-test of %s every %v", cfg.SelfTest.Date, cfg.SelfTest.Interval)
+	}
+	sabdaHandler := handlers.NewSABDAHandler(scraperService, statsService, selfTestService, cfg.Server, cfg.Scraper, models.BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildTime: buildTime,
+	})
+	diagnosticsHandler := handlers.NewDiagnosticsHandler(scraperService, cacheService, statsService)
+	maintenanceService := services.NewMaintenanceService(cfg.Server.Maintenance, cfg.Server.MaintenanceRetryAfter)
+	reload := newReloader(cfg, authService, rateLimitService, batchRateLimitService, warmRateLimitService)
+	adminHandler := handlers.NewAdminHandler(scraperService, maintenanceService, *cfg, reload)
+	var warmService *services.WarmService
+	if cfg.Cache.WarmConcurrency > 0 {
+		warmService = services.NewWarmService(scraperService, cfg.Cache.WarmConcurrency, cfg.Scraper.BackgroundTimeout, cfg.Cache.WarmJobRetention, cfg.Cache.CleanupInterval, cfg.Cache.CleanupJitter)
+	}
+	cacheHandler := handlers.NewCacheHandler(scraperService, warmService, warmRateLimitService)
 
 	// Create Fiber app
+	// Timestamps in responses are written in the configured format as they are encoded
+	models.SetTimestampFormat(cfg.Server.TimeFormat)
+
 	app := fiber.New(fiber.Config{
-		ReadTimeout:    cfg.Server.Timeout,
-		WriteTimeout:   cfg.Server.Timeout,
-		IdleTimeout:    cfg.Server.IdleTimeout,
-		StrictRouting:  true,
-		CaseSensitive:  true,
-		ServerHeader:   "SABDA-Scraper-Go",
-		AppName:        "SABDA Scraper API v2.0",
-		ErrorHandler:   customErrorHandler,
+		ReadTimeout:   cfg.Server.Timeout,
+		WriteTimeout:  cfg.Server.Timeout,
+		IdleTimeout:   cfg.Server.IdleTimeout,
+		StrictRouting: true,
+		CaseSensitive: true,
+		ServerHeader:  "SABDA-Scraper-Go",
+		AppName:       "SABDA Scraper API " + version,
+		ErrorHandler:  customErrorHandler,
 	})
 
 	// Middleware
 	app.Use(recover.New())
-	
+	app.Use(requestid.New(requestid.Config{
+		Header: cfg.Server.RequestIDHeader,
+	}))
+	app.Use(handlers.MaskClientIP(services.NewIPMasker(cfg.Privacy.MaskClientIP, cfg.Privacy.IPHashSalt)))
+	app.Use(handlers.Localize(cfg.Server.DefaultLanguage))
+
 	if cfg.Server.Debug {
 		app.Use(logger.New(logger.Config{
-			Format: "${time} ${method} ${path} ${status} ${latency}\n",
+			Format: "${time} ${locals:requestid} ${method} ${path} ${status} ${latency}\n",
 		}))
 	}
 
 	// CORS middleware
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: joinStrings(cfg.CORS.AllowedOrigins, ","),
-		AllowMethods: joinStrings(cfg.CORS.AllowedMethods, ","),
-		AllowHeaders: joinStrings(cfg.CORS.AllowedHeaders, ","),
-	}))
+	app.Use(handlers.NewCORSMiddleware(cfg.CORS.Policies))
 
 	// Routes
-	setupRoutes(app, authHandler, sabdaHandler)
+	setupRoutes(app, authHandler, sabdaHandler, diagnosticsHandler, adminHandler, cacheHandler, maintenanceService, cfg.Server)
 
 	// Graceful shutdown
-	go func() {
-		if err := app.Listen(cfg.Server.Host + ":" + cfg.Server.Port); err != nil {
-			log.Printf("Server failed to start: %v", err)
+	addr := cfg.Server.Host + ":" + cfg.Server.Port
+	if cfg.Server.TLSCertFile != "" {
+		listener, err := listenTLS(addr, cfg.Server)
+		if err != nil {
+			log.Fatalf("Failed to serve TLS: %v", err)
 		}
-	}()
+		go func() {
+			if err := app.Listener(listener); err != nil {
+				log.Printf("Server failed to start: %v", err)
+			}
+		}()
+	} else {
+		go func() {
+			if err := app.Listen(addr); err != nil {
+				log.Printf("Server failed to start: %v", err)
+			}
+		}()
+	}
 
 	// Wait for interrupt signal
 	c := make(chan os.Signal, 1)
@@ -87,25 +168,123 @@ func main() {
 	<-c
 
 	log.Println("Shutting down server...")
-	
+
 	// Graceful shutdown with timeout
 	if err := app.ShutdownWithTimeout(30 * time.Second); err != nil {
 		log.Printf("Server shutdown error: %v", err)
 	}
+	historyStore.Close()
+	auditLogger.Close()
 
 	log.Println("Server stopped")
 }
 
-func setupRoutes(app *fiber.App, authHandler *handlers.AuthHandler, sabdaHandler *handlers.SABDAHandler) {
+// listenTLS opens the TLS listener for addr with the configured certificate, minimum
+// version and cipher suites
+func listenTLS(addr string, serverCfg models.ServerConfig) (net.Listener, error) {
+	tlsConfig, err := config.TLSConfig(serverCfg)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.LoadX509KeyPair(serverCfg.TLSCertFile, serverCfg.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate: %w", err)
+	}
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	return tls.Listen("tcp", addr, tlsConfig)
+}
+
+// newRateLimiter creates a rate limiter allowing maxRequestsPerMinute per client
+func newRateLimiter(cfg models.RateConfig, maxRequestsPerMinute int) services.RateLimiter {
+	return services.NewRateLimitService(maxRequestsPerMinute, cfg.MaxClients, cfg.WindowDuration, cfg.CleanupInterval, cfg.CleanupJitter)
+}
+
+// newReloader returns the admin reload function. Reloads apply API keys and rate limits in
+// place; everything else keeps its startup value. It returns the configuration now in effect
+// and the names of the settings the reload changed.
+func newReloader(cfg *models.Config, authService *services.AuthService, rateLimitService, batchRateLimitService, warmRateLimitService services.RateLimiter) func() (*models.Config, []string, error) {
+	var mutex sync.Mutex
+	current := cfg
+	return func() (*models.Config, []string, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		updated, applied, err := config.Reload(current)
+		if err != nil {
+			return nil, nil, err
+		}
+		authService.UpdateKeys(clientKeys(updated.API), scopedKeys(updated.API))
+		rateLimitService.UpdateLimit(updated.Rate.MaxRequestsPerMinute)
+		batchRateLimitService.UpdateLimit(updated.Rate.BatchTokenRequestsPerMinute)
+		warmRateLimitService.UpdateLimit(updated.Rate.WarmRequestsPerMinute)
+		current = updated
+		log.Printf("Configuration reloaded; applied: %v", applied)
+		return updated, applied, nil
+	}
+}
+
+// clientKeys returns the API keys that issue client-scoped tokens
+func clientKeys(cfg models.APIConfig) map[string]string {
+	return map[string]string{
+		"flutter": cfg.FlutterKey,
+		"mobile":  cfg.MobileKey,
+	}
+}
+
+// scopedKeys returns the API keys that issue tokens with an elevated scope
+func scopedKeys(cfg models.APIConfig) map[string]string {
+	return map[string]string{
+		services.ScopeAdmin:  cfg.AdminKey,
+		services.ScopeWarmer: cfg.WarmerKey,
+	}
+}
+
+func setupRoutes(app *fiber.App, authHandler *handlers.AuthHandler, sabdaHandler *handlers.SABDAHandler, diagnosticsHandler *handlers.DiagnosticsHandler, adminHandler *handlers.AdminHandler, cacheHandler *handlers.CacheHandler, maintenanceService *services.MaintenanceService, serverCfg models.ServerConfig) {
 	// API routes
 	api := app.Group("/api")
 
+	// JSON POST endpoints reject other content types with 415 in strict mode
+	requireJSON := handlers.RequireJSON(serverCfg.StrictContentType)
+
 	// Public routes (must be defined before protected routes)
 	api.Get("/health", sabdaHandler.HealthCheck)
-	api.Post("/auth/token", authHandler.GetToken)
+	api.Get("/health/live", sabdaHandler.HealthLive)
+	api.Get("/health/ready", sabdaHandler.HealthReady)
+	api.Get("/version", sabdaHandler.GetVersion)
+	api.Post("/auth/token", requireJSON, authHandler.GetToken)
+	api.Post("/auth/tokens", requireJSON, authHandler.AuthMiddleware(), authHandler.RequireScope(services.ScopeAdmin), authHandler.GetTokens)
 
 	// Protected routes
-	api.Get("/sabda", authHandler.AuthMiddleware(), sabdaHandler.GetContent)
+	maintenanceGuard := handlers.MaintenanceGuard(maintenanceService)
+	// Content responses list the request headers that select them for CDNs in front of the API
+	contentVary := handlers.Vary(handlers.ContentVary(serverCfg.VaryAuthorization)...)
+	api.Get("/sabda", contentVary, maintenanceGuard, authHandler.AuthMiddleware(), sabdaHandler.GetContent)
+	api.Get("/sabda/range", contentVary, maintenanceGuard, authHandler.AuthMiddleware(), sabdaHandler.GetRange)
+	api.Get("/sabda/context", contentVary, maintenanceGuard, authHandler.AuthMiddleware(), sabdaHandler.GetContext)
+	// Diagnostics and metrics expose scrape history, quota use and internals, so only admins see them
+	api.Get("/diagnostics", authHandler.AuthMiddleware(), authHandler.RequireScope(services.ScopeAdmin), diagnosticsHandler.GetDiagnostics)
+	api.Get("/stats/corpus", authHandler.AuthMiddleware(), diagnosticsHandler.GetCorpusStats)
+
+	// Admin routes
+	admin := api.Group("/admin", authHandler.AuthMiddleware(), authHandler.RequireScope(services.ScopeAdmin), authHandler.AuditAdminAction())
+	admin.Get("/raw", adminHandler.GetRawPage)
+	admin.Get("/config", adminHandler.GetConfig)
+	admin.Get("/quality", adminHandler.GetQualityReport)
+	admin.Get("/warm/:id", cacheHandler.GetWarmJob)
+	admin.Post("/maintenance", requireJSON, adminHandler.SetMaintenance)
+	admin.Post("/reload", adminHandler.ReloadConfig)
+	admin.Post("/keys/check", requireJSON, authHandler.CheckKey)
+
+	api.Post("/cache/warm", requireJSON, authHandler.AuthMiddleware(), authHandler.RequireScope(services.ScopeAdmin, services.ScopeWarmer), cacheHandler.WarmCache)
+
+	// Prometheus metrics
+	app.Get("/metrics", authHandler.AuthMiddleware(), authHandler.RequireScope(services.ScopeAdmin), adaptor.HTTPHandler(promhttp.Handler()))
+
+	// Browser requests for the favicon and static assets stay out of the API routes
+	app.Get("/favicon.ico", handlers.Favicon(serverCfg.StaticDir))
+	if serverCfg.StaticDir != "" {
+		app.Static(handlers.StaticPrefix, serverCfg.StaticDir)
+	}
 
 	// Home route (public)
 	app.Get("/", sabdaHandler.Home)
@@ -118,27 +297,42 @@ func customErrorHandler(c *fiber.Ctx, err error) error {
 		code = e.Code
 	}
 
+	// The router reports 405 for known paths; tell the client which methods would work
+	if code == fiber.StatusMethodNotAllowed {
+		allowed := allowedMethods(c.App(), c.Path())
+		c.Set(fiber.HeaderAllow, strings.Join(allowed, ", "))
+		return c.Status(code).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Method " + c.Method() + " not allowed; use " + strings.Join(allowed, ", "),
+			"metadata": map[string]interface{}{
+				"error_type":      "MethodNotAllowed",
+				"allowed_methods": allowed,
+				"timestamp":       models.Now(),
+			},
+		})
+	}
+
 	return c.Status(code).JSON(fiber.Map{
 		"status":  "error",
 		"message": err.Error(),
 		"metadata": map[string]interface{}{
 			"error_type": "ServerError",
-			"timestamp":  time.Now(),
+			"timestamp":  models.Now(),
 		},
 	})
 }
 
-func joinStrings(strs []string, separator string) string {
-	if len(strs) == 0 {
-		return ""
-	}
-	if len(strs) == 1 {
-		return strs[0]
-	}
-	
-	result := strs[0]
-	for i := 1; i < len(strs); i++ {
-		result += separator + strs[i]
+// allowedMethods lists the methods of the routes whose pattern matches path, so parameterised
+// routes such as /api/admin/warm/:id are found too. Middleware mounted with Use is excluded.
+func allowedMethods(app *fiber.App, path string) []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, route := range app.GetRoutes(true) {
+		if !seen[route.Method] && fiber.RoutePatternMatch(path, route.Path, app.Config()) {
+			seen[route.Method] = true
+			methods = append(methods, route.Method)
+		}
 	}
-	return result
-}
\ No newline at end of file
+	sort.Strings(methods)
+	return methods
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/handlers"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+	"github.com/pranahonk/sabda-scraper-go/pkg/config"
+)
+
+func TestMethodNotAllowedListsAllowedMethods(t *testing.T) {
+	app := fiber.New(fiber.Config{StrictRouting: true, CaseSensitive: true, ErrorHandler: customErrorHandler})
+	noop := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+	app.Get("/api/admin/warm/:id", noop)
+	app.Get("/api/sabda", noop)
+	app.Post("/api/auth/token", noop)
+
+	tests := []struct {
+		method, path, allow string
+	}{
+		{"DELETE", "/api/admin/warm/3f2a", "GET, HEAD"},
+		{"POST", "/api/sabda", "GET, HEAD"},
+		{"GET", "/api/auth/token", "POST"},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(tt.method, tt.path, nil))
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", tt.method, tt.path, err)
+		}
+		if resp.StatusCode != fiber.StatusMethodNotAllowed {
+			t.Errorf("%s %s: status %d, want 405", tt.method, tt.path, resp.StatusCode)
+		}
+		if got := resp.Header.Get(fiber.HeaderAllow); got != tt.allow {
+			t.Errorf("%s %s: Allow = %q, want %q", tt.method, tt.path, got, tt.allow)
+		}
+	}
+}
+
+func TestReloadAppliesKeysImmediately(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("SECRET_KEY", "test-secret")
+	writeConfig := func(content string) {
+		if err := os.WriteFile("config.yaml", []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig("api:\n  flutter_key: old-key\n  mobile_key: mobile-key\nrate:\n  max_requests_per_minute: 60\ncache:\n  max_size: 100\n")
+
+	cfg := config.Load()
+	authService := services.NewAuthService(cfg.JWT.SecretKey, cfg.JWT.ExpirationDelta, clientKeys(cfg.API), scopedKeys(cfg.API), nil)
+	rateLimitService := newRateLimiter(cfg.Rate, cfg.Rate.MaxRequestsPerMinute)
+	reload := newReloader(cfg, authService, rateLimitService, newRateLimiter(cfg.Rate, cfg.Rate.BatchTokenRequestsPerMinute), newRateLimiter(cfg.Rate, cfg.Rate.WarmRequestsPerMinute))
+	oldToken, _, err := authService.GenerateToken("old-key")
+	if err != nil {
+		t.Fatalf("GenerateToken(old-key) error = %v", err)
+	}
+
+	// Replace the flutter key and the rate limit; the cache size needs a restart
+	writeConfig("api:\n  flutter_key: new-key\n  mobile_key: mobile-key\nrate:\n  max_requests_per_minute: 30\ncache:\n  max_size: 5\n")
+	effective, applied, err := reload()
+	if err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+
+	if _, _, err := authService.GenerateToken("new-key"); err != nil {
+		t.Errorf("GenerateToken(new-key) after reload error = %v", err)
+	}
+	if _, _, err := authService.GenerateToken("old-key"); err == nil {
+		t.Errorf("GenerateToken(old-key) succeeded after the key was removed")
+	}
+	if _, err := authService.VerifyToken(oldToken); err == nil {
+		t.Errorf("VerifyToken() accepted a token of the removed key")
+	}
+	if got := rateLimitService.Limit(); got != 30 {
+		t.Errorf("rate limit = %d, want 30", got)
+	}
+
+	if want := []string{"api.flutter_key", "rate.max_requests_per_minute"}; !slices.Equal(applied, want) {
+		t.Errorf("applied = %q, want %q", applied, want)
+	}
+	if effective.API.FlutterKey != "new-key" || effective.Rate.MaxRequestsPerMinute != 30 {
+		t.Errorf("effective config lacks the reloaded settings: %+v, %+v", effective.API, effective.Rate)
+	}
+	if effective.Cache.MaxSize != 100 {
+		t.Errorf("effective cache.max_size = %d, want the startup value 100", effective.Cache.MaxSize)
+	}
+}
+
+// newRoutedApp returns an app with every route set up over real handlers and an empty cache,
+// and the maintenance service it checks
+func newRoutedApp(serverCfg models.ServerConfig) (*fiber.App, *services.MaintenanceService) {
+	cache := services.NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+	scraperService := services.NewScraperService(false, models.ScraperConfig{}, cache, nil, nil, nil)
+	statsService := services.NewStatsService(3, nil)
+	limiter := services.NewRateLimitService(1000, 0, time.Minute, 0, 0)
+	auth := services.NewAuthService("secret", time.Hour, map[string]string{"flutter": "client-key"}, map[string]string{}, nil)
+	maintenance := services.NewMaintenanceService(false, 2*time.Minute)
+
+	app := fiber.New(fiber.Config{StrictRouting: true, CaseSensitive: true, ErrorHandler: customErrorHandler})
+	setupRoutes(app,
+		handlers.NewAuthHandler(auth, limiter, limiter, nil),
+		handlers.NewSABDAHandler(scraperService, statsService, nil, serverCfg, models.ScraperConfig{}, models.BuildInfo{}),
+		handlers.NewDiagnosticsHandler(scraperService, cache, statsService),
+		handlers.NewAdminHandler(scraperService, maintenance, models.Config{}, nil),
+		handlers.NewCacheHandler(scraperService, nil, limiter),
+		maintenance, serverCfg)
+	return app, maintenance
+}
+
+func TestMaintenanceBlocksContentButNotLiveness(t *testing.T) {
+	app, maintenance := newRoutedApp(models.ServerConfig{})
+
+	status := func(path string) (int, string) {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		return resp.StatusCode, resp.Header.Get(fiber.HeaderRetryAfter)
+	}
+
+	maintenance.Set(true, "sabda.org is down")
+	for _, path := range []string{"/api/sabda?year=2025&date=0902", "/api/sabda/range?year=2025&start=0901&end=0902"} {
+		if code, retryAfter := status(path); code != fiber.StatusServiceUnavailable || retryAfter != "120" {
+			t.Errorf("GET %s in maintenance: status %d, Retry-After %q; want 503, 120", path, code, retryAfter)
+		}
+	}
+	if code, _ := status("/api/health/live"); code != fiber.StatusOK {
+		t.Errorf("GET /api/health/live in maintenance: status %d, want 200", code)
+	}
+
+	// Out of maintenance the content endpoint is reached again and asks for a token
+	maintenance.Set(false, "")
+	if code, _ := status("/api/sabda?year=2025&date=0902"); code != fiber.StatusUnauthorized {
+		t.Errorf("GET /api/sabda after maintenance: status %d, want 401", code)
+	}
+}
+
+func TestFaviconAndStaticAssets(t *testing.T) {
+	get := func(app *fiber.App, path string) (int, string, string) {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, resp.Header.Get(fiber.HeaderCacheControl), string(body)
+	}
+
+	app, _ := newRoutedApp(models.ServerConfig{})
+	if code, cacheControl, body := get(app, "/favicon.ico"); code != fiber.StatusNoContent || cacheControl == "" || body != "" {
+		t.Errorf("favicon without a static dir: status %d, Cache-Control %q, body %q; want a cacheable 204", code, cacheControl, body)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "favicon.ico"), []byte("icon-bytes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte("<h1>SABDA API</h1>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	app, _ = newRoutedApp(models.ServerConfig{StaticDir: dir})
+	if code, _, body := get(app, "/favicon.ico"); code != fiber.StatusOK || body != "icon-bytes" {
+		t.Errorf("favicon from the static dir: status %d, body %q; want 200 with the icon", code, body)
+	}
+	if code, _, body := get(app, "/static/index.html"); code != fiber.StatusOK || body != "<h1>SABDA API</h1>" {
+		t.Errorf("GET /static/index.html: status %d, body %q; want the landing page", code, body)
+	}
+}
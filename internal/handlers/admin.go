@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+	"github.com/pranahonk/sabda-scraper-go/pkg/config"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+// AdminHandler handles operator-only endpoints
+type AdminHandler struct {
+	scraperService     *services.ScraperService
+	maintenanceService *services.MaintenanceService
+	config             atomic.Pointer[models.Config]
+	reload             func() (*models.Config, []string, error)
+	maxRangeDays       int
+	scrapeTimeout      time.Duration
+}
+
+// qualityReportWorkers is how many dates of a quality report are scraped at once; upstream
+// concurrency is further bounded by the scrape queue
+const qualityReportWorkers = 4
+
+// redactedValue replaces secret material in the config dump
+const redactedValue = "***"
+
+// NewAdminHandler creates a new admin handler. cfg is the loaded configuration reported,
+// with secrets redacted, by GetConfig. reload re-reads and applies the configuration,
+// returning the configuration now in effect and the names of the settings it changed.
+func NewAdminHandler(scraperService *services.ScraperService, maintenanceService *services.MaintenanceService, cfg models.Config, reload func() (*models.Config, []string, error)) *AdminHandler {
+	handler := &AdminHandler{
+		scraperService:     scraperService,
+		maintenanceService: maintenanceService,
+		reload:             reload,
+		maxRangeDays:       cfg.Scraper.MaxRangeDays,
+		scrapeTimeout:      cfg.Scraper.BackgroundTimeout,
+	}
+	handler.setConfig(cfg)
+	return handler
+}
+
+// GetConfig returns the effective configuration the server loaded, with secrets redacted
+func (h *AdminHandler) GetConfig(c *fiber.Ctx) error {
+	return c.JSON(models.APIResponse{
+		Status:  "success",
+		Message: "Configuration retrieved successfully",
+		Data:    h.config.Load(),
+		Metadata: map[string]interface{}{
+			"timestamp": models.Now(),
+		},
+	})
+}
+
+// ReloadConfig re-reads the config file and applies API keys and rate limits without a
+// restart, reporting the configuration in effect and the settings that changed
+func (h *AdminHandler) ReloadConfig(c *fiber.Ctx) error {
+	cfg, applied, err := h.reload()
+	if errors.Is(err, config.ErrImmutableSetting) {
+		return c.Status(409).JSON(models.APIResponse{
+			Status:  "error",
+			Message: err.Error(),
+			Metadata: map[string]interface{}{
+				"error_type": "ImmutableConfigError",
+				"timestamp":  models.Now(),
+			},
+		})
+	}
+	if err != nil {
+		log.Printf("Config reload error: %v", err)
+		return c.Status(422).JSON(models.APIResponse{
+			Status:  "error",
+			Message: "Failed to reload configuration: " + err.Error(),
+			Metadata: map[string]interface{}{
+				"error_type": "ConfigError",
+				"timestamp":  models.Now(),
+			},
+		})
+	}
+
+	h.setConfig(*cfg)
+	log.Printf("Configuration reloaded by IP: %s", displayIP(c))
+
+	return c.JSON(models.APIResponse{
+		Status:  "success",
+		Message: "Configuration reloaded",
+		Data:    h.config.Load(),
+		Metadata: map[string]interface{}{
+			"applied":   applied,
+			"timestamp": models.Now(),
+		},
+	})
+}
+
+func (h *AdminHandler) setConfig(cfg models.Config) {
+	redacted := redactedConfig(cfg)
+	h.config.Store(&redacted)
+}
+
+// redactedConfig returns a copy of cfg with the JWT secret, API keys and IP hash salt masked. Empty values
+// stay empty so an unset key remains distinguishable from a configured one.
+func redactedConfig(cfg models.Config) models.Config {
+	for _, secret := range []*string{
+		&cfg.JWT.SecretKey,
+		&cfg.API.FlutterKey,
+		&cfg.API.MobileKey,
+		&cfg.API.AdminKey,
+		&cfg.API.WarmerKey,
+		&cfg.Privacy.IPHashSalt,
+	} {
+		if *secret != "" {
+			*secret = redactedValue
+		}
+	}
+	return cfg
+}
+
+// GetQualityReport scrapes an e-SH date range through the cache and reports extraction
+// quality per date without the content, for spotting dates the extractors handle poorly
+func (h *AdminHandler) GetQualityReport(c *fiber.Ctx) error {
+	errs := validationErrors{}
+	validatePublicationEnabled(errs, h.scraperService.EnabledPublications(), scraper.DefaultPublication)
+	year, dates := parseDateRange(c, errs, h.maxRangeDays)
+	if len(errs) > 0 {
+		return errs.send(c)
+	}
+
+	ctx := requestContext(c)
+	entries := make([]models.QualityReportEntry, len(dates))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < min(qualityReportWorkers, len(dates)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				entries[i] = h.qualityReportEntry(ctx, year, dates[i])
+			}
+		}()
+	}
+	for i := range dates {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return c.JSON(models.APIResponse{
+		Status:  "success",
+		Message: "Quality report generated successfully",
+		Data:    entries,
+		Metadata: map[string]interface{}{
+			"year":      year,
+			"start":     c.Query("start"),
+			"end":       c.Query("end"),
+			"count":     len(entries),
+			"timestamp": models.Now(),
+		},
+	})
+}
+
+func (h *AdminHandler) qualityReportEntry(ctx context.Context, year int, date string) models.QualityReportEntry {
+	entry := models.QualityReportEntry{Date: date}
+	content, cached, errMsg := scrapeRangeDate(ctx, h.scraperService, year, date, h.scrapeTimeout)
+	if errMsg != "" {
+		entry.Error = errMsg
+		return entry
+	}
+
+	entry.WordCount = content.WordCount
+	entry.ParagraphCount = content.ParagraphCount
+	entry.HasScriptureReference = content.ScriptureReference != ""
+	entry.ExtractionMethod = content.ExtractionMethod
+	entry.QualityScore = scraper.QualityScore(content)
+	entry.Cached = cached
+	return entry
+}
+
+// SetMaintenance turns maintenance mode on or off at runtime
+func (h *AdminHandler) SetMaintenance(c *fiber.Ctx) error {
+	var req models.MaintenanceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return validationError("body", msgInvalidBody).send(c)
+	}
+	if req.Enabled == nil {
+		return validationError("enabled", msgRequiredBool).send(c)
+	}
+
+	status := h.maintenanceService.Set(*req.Enabled, req.Message)
+	log.Printf("Maintenance mode set to %v by IP: %s", status.Enabled, displayIP(c))
+
+	return c.JSON(models.APIResponse{
+		Status:  "success",
+		Message: "Maintenance mode updated",
+		Data:    status,
+		Metadata: map[string]interface{}{
+			"timestamp": models.Now(),
+		},
+	})
+}
+
+// GetRawPage returns the upstream HTML for a date exactly as fetched, for diagnosing markup changes.
+// The URL actually used and its upstream status are reported in X-Source-URL and X-Upstream-Status.
+func (h *AdminHandler) GetRawPage(c *fiber.Ctx) error {
+	errs := validationErrors{}
+	target := parseTarget(c, errs, h.scraperService.EnabledPublications())
+	if len(errs) > 0 {
+		return errs.send(c)
+	}
+
+	page, err := h.scraperService.FetchRaw(requestContext(c), target)
+	if errors.Is(err, services.ErrQuotaExceeded) {
+		return c.Status(503).JSON(models.APIResponse{
+			Status:  "error",
+			Message: "Daily scrape quota exhausted",
+			Metadata: map[string]interface{}{
+				"error_type": "QuotaExceededError",
+				"resets_at":  h.scraperService.QuotaStatus().ResetsAt,
+			},
+		})
+	}
+	if errors.Is(err, services.ErrScrapeBusy) {
+		c.Set(fiber.HeaderRetryAfter, "1")
+		return c.Status(503).JSON(models.APIResponse{
+			Status:  "error",
+			Message: "All scrape slots are busy; please retry shortly",
+			Metadata: map[string]interface{}{
+				"error_type": "ScrapeBusyError",
+			},
+		})
+	}
+	if err != nil {
+		log.Printf("Raw fetch error: %v", err)
+		return c.Status(502).JSON(models.APIResponse{
+			Status:  "error",
+			Message: "Failed to fetch upstream page",
+			Metadata: map[string]interface{}{
+				"error_type": "UpstreamError",
+				"timestamp":  models.Now(),
+			},
+		})
+	}
+
+	c.Set("X-Source-URL", page.URL)
+	c.Set("X-Upstream-Status", strconv.Itoa(page.StatusCode))
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Send(page.Body)
+}
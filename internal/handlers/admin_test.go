@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+func TestGetConfigRedactsSecrets(t *testing.T) {
+	var cfg models.Config
+	cfg.Server.Port = "8080"
+	cfg.JWT.SecretKey = "jwt-secret-value"
+	cfg.API.FlutterKey = "flutter-key-value"
+	cfg.API.MobileKey = "mobile-key-value"
+	cfg.API.AdminKey = "admin-key-value"
+	cfg.Privacy.IPHashSalt = "salt-value"
+
+	h := NewAdminHandler(nil, nil, cfg, nil)
+	app := fiber.New()
+	app.Get("/api/admin/config", h.GetConfig)
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/admin/config", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"jwt-secret-value", "flutter-key-value", "mobile-key-value", "admin-key-value", "salt-value"} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("response leaks %q", secret)
+		}
+	}
+
+	var body struct {
+		Data models.Config `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	got := body.Data
+	for name, value := range map[string]string{
+		"jwt.secret_key":       got.JWT.SecretKey,
+		"api.flutter_key":      got.API.FlutterKey,
+		"api.admin_key":        got.API.AdminKey,
+		"privacy.ip_hash_salt": got.Privacy.IPHashSalt,
+	} {
+		if value != redactedValue {
+			t.Errorf("%s = %q, want %q", name, value, redactedValue)
+		}
+	}
+	// An unset key stays distinguishable from a configured one
+	if got.API.WarmerKey != "" {
+		t.Errorf("api.warmer_key = %q, want empty", got.API.WarmerKey)
+	}
+	if got.Server.Port != "8080" {
+		t.Errorf("server.port = %q, want the unredacted 8080", got.Server.Port)
+	}
+	if cfg.JWT.SecretKey != "jwt-secret-value" {
+		t.Errorf("redaction modified the caller's config")
+	}
+}
@@ -1,26 +1,38 @@
 package handlers
 
 import (
+	"context"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/pranahonk/sabda-scraper-go/internal/models"
 	"github.com/pranahonk/sabda-scraper-go/internal/services"
 )
 
 // AuthHandler handles authentication-related endpoints
 type AuthHandler struct {
-	authService     *services.AuthService
-	rateLimitService *services.RateLimitService
+	authService           *services.AuthService
+	rateLimitService      services.RateLimiter
+	batchRateLimitService services.RateLimiter
+	audit                 *services.AuditLogger
 }
 
-// NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService *services.AuthService, rateLimitService *services.RateLimitService) *AuthHandler {
+// maxBatchTokens caps the number of keys accepted by one batch token request
+const maxBatchTokens = 50
+
+// NewAuthHandler creates a new auth handler. batchRateLimitService applies the tighter
+// per-client limit for batch token requests. Authentication events are recorded to audit,
+// which may be nil.
+func NewAuthHandler(authService *services.AuthService, rateLimitService, batchRateLimitService services.RateLimiter, audit *services.AuditLogger) *AuthHandler {
 	return &AuthHandler{
-		authService:     authService,
-		rateLimitService: rateLimitService,
+		authService:           authService,
+		rateLimitService:      rateLimitService,
+		batchRateLimitService: batchRateLimitService,
+		audit:                 audit,
 	}
 }
 
@@ -30,41 +42,25 @@ func (h *AuthHandler) GetToken(c *fiber.Ctx) error {
 
 	// Check rate limit
 	if !h.rateLimitService.IsAllowed(clientIP) {
-		log.Printf("Rate limit exceeded for token request from IP: %s", clientIP)
-		return c.Status(429).JSON(models.APIResponse{
-			Status:  "error",
-			Message: "Too many token requests. Please try again later.",
-			Metadata: map[string]interface{}{
-				"error_type": "RateLimitError",
-			},
-		})
+		log.Printf("Rate limit exceeded for token request from IP: %s", displayIP(c))
+		h.recordRequest(c, services.AuditRateLimited, services.AuditBlocked, "", "token request rate limit")
+		return sendRateLimited(c, h.rateLimitService, clientIP, "Too many token requests. Please try again later.")
 	}
 
 	var req models.AuthRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(models.APIResponse{
-			Status:  "error",
-			Message: "Invalid request body",
-			Metadata: map[string]interface{}{
-				"error_type": "ValidationError",
-			},
-		})
+		return validationError("body", msgInvalidBody).send(c)
 	}
 
 	if req.APIKey == "" {
-		return c.Status(400).JSON(models.APIResponse{
-			Status:  "error",
-			Message: "API key is required in request body",
-			Metadata: map[string]interface{}{
-				"error_type": "AuthenticationError",
-			},
-		})
+		return validationError("api_key", msgRequired).send(c)
 	}
 
 	// Generate token
 	token, expiresAt, err := h.authService.GenerateToken(req.APIKey)
 	if err != nil {
-		log.Printf("Invalid API key attempt from IP: %s", clientIP)
+		log.Printf("Invalid API key attempt from IP: %s", displayIP(c))
+		h.recordRequest(c, services.AuditTokenDenied, services.AuditFailure, "", "invalid API key")
 		return c.Status(401).JSON(models.APIResponse{
 			Status:  "error",
 			Message: "Invalid API key",
@@ -73,6 +69,7 @@ func (h *AuthHandler) GetToken(c *fiber.Ctx) error {
 			},
 		})
 	}
+	h.recordIssued(c, req.APIKey)
 
 	return c.JSON(models.APIResponse{
 		Status:  "success",
@@ -83,8 +80,88 @@ func (h *AuthHandler) GetToken(c *fiber.Ctx) error {
 			ExpiresIn: int64(time.Until(expiresAt).Seconds()),
 		},
 		Metadata: models.AuthMetadata{
-			Timestamp: time.Now(),
-			ExpiresAt: expiresAt,
+			Timestamp: models.Now(),
+			ExpiresAt: models.NewTimestamp(expiresAt),
+		},
+	})
+}
+
+// GetTokens mints tokens for several API keys in one request, for admin provisioning scripts.
+// Each key gets either a token or an error; invalid keys do not fail the batch.
+func (h *AuthHandler) GetTokens(c *fiber.Ctx) error {
+	clientIP := getClientIP(c)
+
+	if !h.batchRateLimitService.IsAllowed(clientIP) {
+		log.Printf("Batch token rate limit exceeded for IP: %s", displayIP(c))
+		h.recordRequest(c, services.AuditRateLimited, services.AuditBlocked, "", "batch token rate limit")
+		return sendRateLimited(c, h.batchRateLimitService, clientIP, "Too many batch token requests. Please try again later.")
+	}
+
+	var req models.BatchAuthRequest
+	if err := c.BodyParser(&req); err != nil {
+		return validationError("body", msgInvalidBody).send(c)
+	}
+	if len(req.APIKeys) == 0 {
+		return validationError("api_keys", msgRequiredList).send(c)
+	}
+	if len(req.APIKeys) > maxBatchTokens {
+		return validationError("api_keys", msgTooManyKeys, maxBatchTokens).send(c)
+	}
+
+	results := make([]models.BatchAuthResult, 0, len(req.APIKeys))
+	issued := 0
+	for i, apiKey := range req.APIKeys {
+		result := models.BatchAuthResult{Index: i}
+		token, expiresAt, err := h.authService.GenerateToken(apiKey)
+		if err != nil {
+			result.Error = "Invalid API key"
+			h.recordRequest(c, services.AuditTokenDenied, services.AuditFailure, "", "invalid API key at batch index "+strconv.Itoa(i))
+		} else {
+			h.recordIssued(c, apiKey)
+			result.Token = token
+			result.TokenType = "Bearer"
+			result.ExpiresIn = int64(time.Until(expiresAt).Seconds())
+			issued++
+		}
+		results = append(results, result)
+	}
+
+	log.Printf("Batch token request from IP: %s issued %d of %d tokens", displayIP(c), issued, len(req.APIKeys))
+	return c.JSON(models.APIResponse{
+		Status:  "success",
+		Message: "Batch tokens processed",
+		Data:    results,
+		Metadata: map[string]interface{}{
+			"requested": len(req.APIKeys),
+			"issued":    issued,
+			"timestamp": models.Now(),
+		},
+	})
+}
+
+// CheckKey reports whether an API key is accepted and, if so, its label and scope, so support
+// can diagnose a partner's auth problems without minting a token. The key is never logged or echoed.
+func (h *AuthHandler) CheckKey(c *fiber.Ctx) error {
+	var req models.AuthRequest
+	if err := c.BodyParser(&req); err != nil {
+		return validationError("body", msgInvalidBody).send(c)
+	}
+	if req.APIKey == "" {
+		return validationError("api_key", msgRequired).send(c)
+	}
+
+	result := models.APIKeyCheckResult{Valid: h.authService.IsValidAPIKey(req.APIKey)}
+	if result.Valid {
+		result.Label, result.Scope = h.authService.KeyInfo(req.APIKey)
+	}
+	log.Printf("API key check from IP: %s valid=%t label=%q", displayIP(c), result.Valid, result.Label)
+
+	return c.JSON(models.APIResponse{
+		Status:  "success",
+		Message: "API key checked",
+		Data:    result,
+		Metadata: map[string]interface{}{
+			"timestamp": models.Now(),
 		},
 	})
 }
@@ -96,19 +173,15 @@ func (h *AuthHandler) AuthMiddleware() fiber.Handler {
 
 		// Check rate limit
 		if !h.rateLimitService.IsAllowed(clientIP) {
-			log.Printf("Rate limit exceeded for IP: %s", clientIP)
-			return c.Status(429).JSON(models.APIResponse{
-				Status:  "error",
-				Message: "Rate limit exceeded. Please try again later.",
-				Metadata: map[string]interface{}{
-					"error_type": "RateLimitError",
-				},
-			})
+			log.Printf("Rate limit exceeded for IP: %s", displayIP(c))
+			h.recordRequest(c, services.AuditRateLimited, services.AuditBlocked, "", "request rate limit")
+			return sendRateLimited(c, h.rateLimitService, clientIP, "Rate limit exceeded. Please try again later.")
 		}
 
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
-			log.Printf("Missing auth header from IP: %s", clientIP)
+			log.Printf("Missing auth header from IP: %s", displayIP(c))
+			h.recordRequest(c, services.AuditTokenVerifyFailed, services.AuditFailure, "", "missing authorization header")
 			return c.Status(401).JSON(models.APIResponse{
 				Status:  "error",
 				Message: "Authorization header is required",
@@ -127,7 +200,8 @@ func (h *AuthHandler) AuthMiddleware() fiber.Handler {
 		}
 
 		if token == "" {
-			log.Printf("Invalid auth header format from IP: %s", clientIP)
+			log.Printf("Invalid auth header format from IP: %s", displayIP(c))
+			h.recordRequest(c, services.AuditTokenVerifyFailed, services.AuditFailure, "", "invalid authorization header format")
 			return c.Status(401).JSON(models.APIResponse{
 				Status:  "error",
 				Message: "Invalid authorization header format. Use 'Bearer <token>'",
@@ -140,7 +214,8 @@ func (h *AuthHandler) AuthMiddleware() fiber.Handler {
 		// Verify token
 		claims, err := h.authService.VerifyToken(token)
 		if err != nil {
-			log.Printf("Token verification failed from IP: %s, error: %v", clientIP, err)
+			log.Printf("Token verification failed from IP: %s, error: %v", displayIP(c), err)
+			h.recordRequest(c, services.AuditTokenVerifyFailed, services.AuditFailure, "", err.Error())
 			return c.Status(401).JSON(models.APIResponse{
 				Status:  "error",
 				Message: "Invalid or expired token",
@@ -152,24 +227,112 @@ func (h *AuthHandler) AuthMiddleware() fiber.Handler {
 
 		// Store claims in context
 		c.Locals("claims", claims)
-		c.Locals("client_ip", clientIP)
+		c.Locals("client_ip", displayIP(c))
 
 		return c.Next()
 	}
 }
 
+// RequireScope rejects requests whose token carries none of the given scopes. It must run after AuthMiddleware.
+func (h *AuthHandler) RequireScope(scopes ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, _ := c.Locals("claims").(*jwt.MapClaims)
+		for _, scope := range scopes {
+			if services.HasScope(claims, scope) {
+				return c.Next()
+			}
+		}
+
+		log.Printf("Scoped endpoint %s refused for IP: %s", c.Path(), displayIP(c))
+		h.recordRequest(c, services.AuditScopeDenied, services.AuditBlocked, h.authService.TokenLabel(claims), "requires scope "+strings.Join(scopes, " or "))
+		return c.Status(403).JSON(models.APIResponse{
+			Status:  "error",
+			Message: "This endpoint requires a token with one of these scopes: " + strings.Join(scopes, ", "),
+			Metadata: map[string]interface{}{
+				"error_type": "AuthorizationError",
+			},
+		})
+	}
+}
+
+// AuditAdminAction records every request it wraps as an admin action with the response status.
+// It must run after AuthMiddleware.
+func (h *AuthHandler) AuditAdminAction() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		claims, _ := c.Locals("claims").(*jwt.MapClaims)
+		status := c.Response().StatusCode()
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			status = fiberErr.Code
+		}
+		outcome := services.AuditSuccess
+		if err != nil || status >= 400 {
+			outcome = services.AuditFailure
+		}
+		event := h.requestEvent(c, services.AuditAdminAction, outcome, h.authService.TokenLabel(claims), "")
+		event.Status = status
+		h.audit.Record(event)
+		return err
+	}
+}
+
+// recordIssued records a token issued for apiKey, identified by its label
+func (h *AuthHandler) recordIssued(c *fiber.Ctx, apiKey string) {
+	label, scope := h.authService.KeyInfo(apiKey)
+	event := h.requestEvent(c, services.AuditTokenIssued, services.AuditSuccess, label, "")
+	event.Scope = scope
+	h.audit.Record(event)
+}
+
+// recordRequest records an audit event for the current request
+func (h *AuthHandler) recordRequest(c *fiber.Ctx, name, outcome, label, reason string) {
+	h.audit.Record(h.requestEvent(c, name, outcome, label, reason))
+}
+
+func (h *AuthHandler) requestEvent(c *fiber.Ctx, name, outcome, label, reason string) services.AuditEvent {
+	return services.AuditEvent{
+		Event:    name,
+		Outcome:  outcome,
+		ClientIP: displayIP(c),
+		Label:    label,
+		Method:   c.Method(),
+		Path:     c.Path(),
+		Reason:   reason,
+	}
+}
+
+// isAdmin reports whether the request was authenticated with an admin-scoped token
+func isAdmin(c *fiber.Ctx) bool {
+	claims, _ := c.Locals("claims").(*jwt.MapClaims)
+	return services.HasScope(claims, services.ScopeAdmin)
+}
+
+// clientScrapeContext is requestContext with the request's cache misses counted against the
+// client's in-flight scrape limit. Clients are told apart by the API key their token was
+// issued for, falling back to the client IP.
+func clientScrapeContext(c *fiber.Ctx) context.Context {
+	client := "ip:" + getClientIP(c)
+	if claims, ok := c.Locals("claims").(*jwt.MapClaims); ok {
+		if keyHash, _ := (*claims)["api_key"].(string); keyHash != "" {
+			client = "key:" + keyHash
+		}
+	}
+	return services.WithScrapeClient(requestContext(c), client)
+}
+
 func getClientIP(c *fiber.Ctx) string {
 	// Check X-Forwarded-For header first (for proxies)
 	if xff := c.Get("X-Forwarded-For"); xff != "" {
 		ips := strings.Split(xff, ",")
 		return strings.TrimSpace(ips[0])
 	}
-	
+
 	// Check X-Real-IP header
 	if xri := c.Get("X-Real-IP"); xri != "" {
 		return xri
 	}
-	
+
 	// Fall back to remote IP
 	return c.IP()
-}
\ No newline at end of file
+}
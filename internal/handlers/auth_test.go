@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+)
+
+func TestRequireScopeAdmitsOnlyAdminTokens(t *testing.T) {
+	auth := services.NewAuthService("secret", time.Hour, map[string]string{"flutter": "client-key"}, map[string]string{services.ScopeAdmin: "admin-key"}, nil)
+	limiter := services.NewRateLimitService(1000, 0, time.Minute, 0, 0)
+	h := NewAuthHandler(auth, limiter, limiter, nil)
+
+	app := fiber.New()
+	app.Get("/api/diagnostics", h.AuthMiddleware(), h.RequireScope(services.ScopeAdmin), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	tests := []struct {
+		name, key string
+		want      int
+	}{
+		{"admin", "admin-key", fiber.StatusOK},
+		{"client", "client-key", fiber.StatusForbidden},
+		{"anonymous", "", fiber.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/diagnostics", nil)
+		if tt.key != "" {
+			token, _, err := auth.GenerateToken(tt.key)
+			if err != nil {
+				t.Fatalf("%s: GenerateToken() error = %v", tt.name, err)
+			}
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tt.name, err)
+		}
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s token: status %d, want %d", tt.name, resp.StatusCode, tt.want)
+		}
+	}
+}
+
+func TestCheckKeyNeverIssuesOrEchoesKeys(t *testing.T) {
+	auth := services.NewAuthService("secret", time.Hour, map[string]string{"flutter": "client-key"}, map[string]string{services.ScopeAdmin: "admin-key"}, nil)
+	limiter := services.NewRateLimitService(1000, 0, time.Minute, 0, 0)
+	h := NewAuthHandler(auth, limiter, limiter, nil)
+	app := fiber.New()
+	app.Post("/api/admin/keys/check", h.CheckKey)
+
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	tests := []struct {
+		name, body string
+		status     int
+		want       models.APIKeyCheckResult
+	}{
+		{"client key", `{"api_key": "client-key"}`, 200, models.APIKeyCheckResult{Valid: true, Label: "flutter", Scope: services.ScopeClient}},
+		{"admin key", `{"api_key": "admin-key"}`, 200, models.APIKeyCheckResult{Valid: true, Label: services.ScopeAdmin, Scope: services.ScopeAdmin}},
+		{"unknown key", `{"api_key": "stolen-key"}`, 200, models.APIKeyCheckResult{}},
+		{"missing key", `{}`, 400, models.APIKeyCheckResult{}},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/api/admin/keys/check", strings.NewReader(tt.body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tt.name, err)
+		}
+		raw, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, resp.StatusCode, tt.status)
+			continue
+		}
+		for _, key := range []string{"client-key", "admin-key", "stolen-key"} {
+			if strings.Contains(string(raw), key) {
+				t.Errorf("%s: response echoes %q", tt.name, key)
+			}
+		}
+		if strings.Contains(string(raw), "token") {
+			t.Errorf("%s: response carries a token: %s", tt.name, raw)
+		}
+		if tt.status != 200 {
+			continue
+		}
+		var body struct {
+			Data models.APIKeyCheckResult `json:"data"`
+		}
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Fatalf("%s: decode failed: %v", tt.name, err)
+		}
+		if body.Data != tt.want {
+			t.Errorf("%s: result = %+v, want %+v", tt.name, body.Data, tt.want)
+		}
+	}
+	for _, key := range []string{"client-key", "admin-key", "stolen-key"} {
+		if strings.Contains(logged.String(), key) {
+			t.Errorf("log contains %q", key)
+		}
+	}
+}
+
+func TestAuthEventsAreAudited(t *testing.T) {
+	var audited bytes.Buffer
+	audit := services.NewAuditWriter(&audited)
+	auth := services.NewAuthService("secret", time.Hour, map[string]string{"flutter": "client-key"}, nil, audit)
+	limiter := services.NewRateLimitService(1000, 0, time.Minute, 0, 0)
+	h := NewAuthHandler(auth, limiter, limiter, audit)
+	app := fiber.New()
+	app.Post("/api/auth/token", h.GetToken)
+	app.Get("/api/sabda", h.AuthMiddleware(), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req := httptest.NewRequest("POST", "/api/auth/token", strings.NewReader(`{"api_key": "client-key"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("token request failed: %v", err)
+	}
+	var issued struct {
+		Data models.AuthResponse `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&issued); err != nil || issued.Data.Token == "" {
+		t.Fatalf("token response: %v, token %q", err, issued.Data.Token)
+	}
+
+	req = httptest.NewRequest("GET", "/api/sabda", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+issued.Data.Token+"tampered")
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("verification request failed: %v", err)
+	}
+
+	var events []services.AuditEvent
+	for _, line := range strings.Split(strings.TrimSpace(audited.String()), "\n") {
+		var event services.AuditEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("audit line %q is not JSON: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	want := []struct{ event, outcome, label string }{
+		{services.AuditTokenIssued, services.AuditSuccess, "flutter"},
+		{services.AuditTokenVerifyFailed, services.AuditFailure, ""},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("audit events = %+v, want %d", events, len(want))
+	}
+	for i, event := range events {
+		if event.Event != want[i].event || event.Outcome != want[i].outcome || event.Label != want[i].label || event.Level != "audit" {
+			t.Errorf("event %d = %+v, want %s %s %q", i, event, want[i].event, want[i].outcome, want[i].label)
+		}
+	}
+	for _, secret := range []string{"client-key", issued.Data.Token} {
+		if strings.Contains(audited.String(), secret) {
+			t.Errorf("audit log contains %q", secret)
+		}
+	}
+}
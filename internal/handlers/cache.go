@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+// maxWarmRangeDays bounds a range warm to one year of dates
+const maxWarmRangeDays = 366
+
+// CacheHandler handles cache management endpoints
+type CacheHandler struct {
+	scraperService   *services.ScraperService
+	warmService      *services.WarmService
+	rateLimitService services.RateLimiter
+}
+
+// NewCacheHandler creates a new cache handler. rateLimitService limits warm requests per client.
+func NewCacheHandler(scraperService *services.ScraperService, warmService *services.WarmService, rateLimitService services.RateLimiter) *CacheHandler {
+	return &CacheHandler{
+		scraperService:   scraperService,
+		warmService:      warmService,
+		rateLimitService: rateLimitService,
+	}
+}
+
+// WarmCache starts a fresh scrape of an issue, or of a range of dates, in the background so it
+// is cached before users request it, and returns 202 Accepted with the job ID immediately
+func (h *CacheHandler) WarmCache(c *fiber.Ctx) error {
+	clientIP := getClientIP(c)
+	if !h.rateLimitService.IsAllowed(clientIP) {
+		log.Printf("Cache warm rate limit exceeded for IP: %s", displayIP(c))
+		return sendRateLimited(c, h.rateLimitService, clientIP, "Too many cache warm requests. Please try again later.")
+	}
+
+	var req models.CacheWarmRequest
+	if err := c.BodyParser(&req); err != nil {
+		return validationError("body", msgInvalidBody).send(c)
+	}
+
+	yearStr := ""
+	if req.Year != 0 {
+		yearStr = strconv.Itoa(req.Year)
+	}
+	errs := validationErrors{}
+	targets := h.warmTargets(errs, req, yearStr)
+	if len(errs) > 0 {
+		return errs.send(c)
+	}
+
+	job, err := h.warmService.Start(requestContext(c), targets)
+	if errors.Is(err, services.ErrWarmDisabled) {
+		return c.Status(503).JSON(models.APIResponse{
+			Status:  "error",
+			Message: "Cache warming is disabled on this deployment",
+			Metadata: map[string]interface{}{
+				"error_type": "DisabledError",
+			},
+		})
+	}
+	if errors.Is(err, services.ErrWarmBusy) {
+		return c.Status(503).JSON(models.APIResponse{
+			Status:  "error",
+			Message: "Too many cache warms in progress. Please try again shortly.",
+			Metadata: map[string]interface{}{
+				"error_type": "BusyError",
+			},
+		})
+	}
+
+	data := map[string]interface{}{
+		"job_id":     job.ID,
+		"total":      job.Total,
+		"status_url": "/api/admin/warm/" + job.ID,
+	}
+	if len(targets) == 1 {
+		data["cache_key"] = targets[0].CacheKey()
+	}
+	return c.Status(202).JSON(models.APIResponse{
+		Status:  "success",
+		Message: "Cache warm accepted",
+		Data:    data,
+		Metadata: map[string]interface{}{
+			"timestamp": models.Now(),
+		},
+	})
+}
+
+// warmTargets validates a warm request into the issues to scrape: the single issue it names,
+// or every date from Start to End for a date-indexed publication
+func (h *CacheHandler) warmTargets(errs validationErrors, req models.CacheWarmRequest, yearStr string) []scraper.Target {
+	enabled := h.scraperService.EnabledPublications()
+	if req.Start == "" && req.End == "" {
+		return []scraper.Target{buildTarget(errs, enabled, req.Publication, yearStr, req.Date, req.Edition)}
+	}
+
+	if req.Date != "" {
+		errs.add("date", msgDateWithRange)
+	}
+	if publication, ok := scraper.LookupPublication(req.Publication); ok && publication.Indexing != scraper.IndexByDate {
+		errs.add("start", msgRangeUnsupported)
+		return nil
+	}
+	// The start date stands in for the date so publication and year are validated as usual
+	first := buildTarget(errs, enabled, req.Publication, yearStr, req.Start, req.Edition)
+	if len(errs) > 0 {
+		return nil
+	}
+
+	var targets []scraper.Target
+	for _, date := range validateDateRange(errs, first.Year, req.Start, req.End, maxWarmRangeDays) {
+		targets = append(targets, scraper.Target{Publication: first.Publication, Year: first.Year, Date: date})
+	}
+	return targets
+}
+
+// GetWarmJob reports the progress of a cache warm job started by WarmCache
+func (h *CacheHandler) GetWarmJob(c *fiber.Ctx) error {
+	job, ok := h.warmService.Status(c.Params("id"))
+	if !ok {
+		return c.Status(404).JSON(models.APIResponse{
+			Status:  "error",
+			Message: "Warm job not found; finished jobs are kept for a limited time",
+			Metadata: map[string]interface{}{
+				"error_type": "NotFoundError",
+			},
+		})
+	}
+
+	return c.JSON(models.APIResponse{
+		Status:  "success",
+		Message: "Warm job retrieved successfully",
+		Data:    job,
+		Metadata: map[string]interface{}{
+			"timestamp": models.Now(),
+		},
+	})
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+// RequireJSON rejects requests whose Content-Type is not application/json with 415 when strict
+// is set. Without strict mode the body parser keeps accepting any content type it understands.
+func RequireJSON(strict bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !strict {
+			return c.Next()
+		}
+
+		contentType := strings.ToLower(strings.TrimSpace(strings.Split(c.Get(fiber.HeaderContentType), ";")[0]))
+		if contentType == fiber.MIMEApplicationJSON {
+			return c.Next()
+		}
+
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(models.APIResponse{
+			Status:  "error",
+			Message: "Content-Type must be application/json",
+			Metadata: map[string]interface{}{
+				"error_type":   "UnsupportedMediaTypeError",
+				"content_type": c.Get(fiber.HeaderContentType),
+			},
+		})
+	}
+}
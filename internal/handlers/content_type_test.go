@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequireJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		strict      bool
+		contentType string
+		body        string
+		want        int
+	}{
+		{"strict form", true, fiber.MIMEApplicationForm, "api_key=client-key", fiber.StatusUnsupportedMediaType},
+		{"strict missing", true, "", `{"api_key": "client-key"}`, fiber.StatusUnsupportedMediaType},
+		{"strict json", true, "application/json; charset=utf-8", `{"api_key": "client-key"}`, fiber.StatusOK},
+		{"lenient form", false, fiber.MIMEApplicationForm, "api_key=client-key", fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		app := fiber.New()
+		app.Post("/api/auth/token", RequireJSON(tt.strict), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+		req := httptest.NewRequest("POST", "/api/auth/token", strings.NewReader(tt.body))
+		if tt.contentType != "" {
+			req.Header.Set(fiber.HeaderContentType, tt.contentType)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tt.name, err)
+		}
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, resp.StatusCode, tt.want)
+		}
+	}
+}
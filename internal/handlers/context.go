@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+// readingContext is a day of the reading plan with its neighbors. A day that could not be
+// retrieved, e.g. one not yet published, is null.
+type readingContext struct {
+	Previous *tocEntry `json:"previous"`
+	Current  *tocEntry `json:"current"`
+	Next     *tocEntry `json:"next"`
+}
+
+// GetContext returns the scripture reference and title of an e-SH date and of the days before
+// and after it, across month and year boundaries, each through the cache
+func (h *SABDAHandler) GetContext(c *fiber.Ctx) error {
+	errs := validationErrors{}
+	validatePublicationEnabled(errs, h.scraperService.EnabledPublications(), scraper.DefaultPublication)
+	year := validateYear(errs, "year", c.Query("year"))
+	date := validateMMDD(errs, "date", c.Query("date"))
+	day, ok := parseRangeDate(year, date)
+	if !ok {
+		errs.add("date", msgRangeDateInvalid, "0902")
+	}
+	if len(errs) > 0 {
+		return errs.send(c)
+	}
+
+	ctx := clientScrapeContext(c)
+	days := make([]*tocEntry, 3)
+	var wg sync.WaitGroup
+	for i := range days {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			neighbor := day.AddDate(0, 0, i-1)
+			neighborDate := neighbor.Format("0102")
+			content, _, errMsg := scrapeRangeDate(ctx, h.scraperService, neighbor.Year(), neighborDate, h.scrapeTimeout)
+			if errMsg != "" {
+				return
+			}
+			entry := newTOCEntry(neighbor.Year(), neighborDate, content)
+			days[i] = &entry
+		}(i)
+	}
+	wg.Wait()
+
+	return c.JSON(models.APIResponse{
+		Status:  "success",
+		Message: "Reading context retrieved successfully",
+		Data:    readingContext{Previous: days[0], Current: days[1], Next: days[2]},
+		Metadata: map[string]interface{}{
+			"year":      year,
+			"date":      date,
+			"timestamp": models.Now(),
+		},
+	})
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+func TestGetContextAcrossBoundaries(t *testing.T) {
+	cache := services.NewCacheService(time.Hour, time.Hour, 20, 0, 0, 0, 0, false)
+	cached := map[scraper.Target]string{
+		{Year: 2025, Date: "0831"}: "Mazmur 23:1-6",
+		{Year: 2025, Date: "0901"}: "Lukas 13:10-17",
+		{Year: 2025, Date: "0902"}: "Lukas 13:18-21",
+		{Year: 2025, Date: "0903"}: "Lukas 13:22-30",
+		{Year: 2025, Date: "0101"}: "Kejadian 1:1-5",
+		{Year: 2025, Date: "0102"}: "Kejadian 1:6-13",
+	}
+	for target, reference := range cached {
+		target.Publication = scraper.DefaultPublication
+		cache.Set(target.CacheKey(), models.DevotionalContent{DevotionalTitle: "Renungan " + target.Date, ScriptureReference: reference}, time.Now())
+	}
+	for _, target := range []scraper.Target{{Year: 2024, Date: "1231"}, {Year: 2025, Date: "0830"}} {
+		target.Publication = scraper.DefaultPublication
+		cache.SetNegative(target.CacheKey())
+	}
+	scraperService := services.NewScraperService(false, models.ScraperConfig{}, cache, nil, nil, nil)
+	h := NewSABDAHandler(scraperService, services.NewStatsService(3, nil), nil, models.ServerConfig{}, models.ScraperConfig{InteractiveTimeout: time.Second}, models.BuildInfo{})
+	app := fiber.New()
+	app.Get("/api/sabda/context", h.GetContext)
+
+	get := func(query string) (int, readingContext) {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/sabda/context?"+query, nil))
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", query, err)
+		}
+		var body struct {
+			Data readingContext `json:"data"`
+		}
+		if resp.StatusCode == fiber.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("%s: decode failed: %v", query, err)
+			}
+		}
+		return resp.StatusCode, body.Data
+	}
+	day := func(entry *tocEntry) string {
+		if entry == nil {
+			return "null"
+		}
+		return entry.Date + " " + entry.ScriptureReference
+	}
+
+	tests := []struct {
+		name                    string
+		query                   string
+		previous, current, next string
+	}{
+		{"normal day", "year=2025&date=0902", "0901 Lukas 13:10-17", "0902 Lukas 13:18-21", "0903 Lukas 13:22-30"},
+		{"month boundary", "year=2025&date=0831", "null", "0831 Mazmur 23:1-6", "0901 Lukas 13:10-17"},
+		{"year boundary", "year=2025&date=0101", "null", "0101 Kejadian 1:1-5", "0102 Kejadian 1:6-13"},
+	}
+	for _, tt := range tests {
+		status, context := get(tt.query)
+		if status != fiber.StatusOK {
+			t.Errorf("%s: status %d, want 200", tt.name, status)
+			continue
+		}
+		if got := [3]string{day(context.Previous), day(context.Current), day(context.Next)}; got != [3]string{tt.previous, tt.current, tt.next} {
+			t.Errorf("%s: context = %q, want %q", tt.name, got, [3]string{tt.previous, tt.current, tt.next})
+		}
+	}
+	if status, _ := get("year=2025&date=0231"); status != fiber.StatusBadRequest {
+		t.Errorf("impossible date: status %d, want 400", status)
+	}
+}
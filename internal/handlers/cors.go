@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+// corsPolicy pairs a policy's origin patterns with the cors middleware that applies it
+type corsPolicy struct {
+	origins []string
+	handler fiber.Handler
+}
+
+// NewCORSMiddleware applies the first policy whose origin patterns match the request Origin.
+// Patterns are exact origins, "*", or subdomain wildcards such as "https://*.example.com".
+// Requests matching no policy get no CORS headers. A single policy behaves like plain cors.New.
+func NewCORSMiddleware(policies []models.CORSPolicy) fiber.Handler {
+	compiled := make([]corsPolicy, 0, len(policies))
+	for _, policy := range policies {
+		origins := make([]string, 0, len(policy.AllowedOrigins))
+		for _, origin := range policy.AllowedOrigins {
+			if origin = strings.ToLower(strings.TrimSpace(origin)); origin != "" {
+				origins = append(origins, origin)
+			}
+		}
+		compiled = append(compiled, corsPolicy{
+			origins: origins,
+			handler: cors.New(cors.Config{
+				AllowOrigins:     strings.Join(origins, ","),
+				AllowMethods:     strings.Join(policy.AllowedMethods, ","),
+				AllowHeaders:     strings.Join(policy.AllowedHeaders, ","),
+				AllowCredentials: policy.AllowCredentials,
+			}),
+		})
+	}
+
+	if len(compiled) == 1 {
+		return compiled[0].handler
+	}
+
+	return func(c *fiber.Ctx) error {
+		origin := strings.ToLower(c.Get(fiber.HeaderOrigin))
+		if origin != "" {
+			for _, policy := range compiled {
+				if policy.matches(origin) {
+					return policy.handler(c)
+				}
+			}
+		}
+		c.Vary(fiber.HeaderOrigin)
+		return c.Next()
+	}
+}
+
+func (p corsPolicy) matches(origin string) bool {
+	for _, pattern := range p.origins {
+		if pattern == "*" || pattern == origin {
+			return true
+		}
+		if i := strings.Index(pattern, "://*."); i != -1 {
+			scheme, suffix := pattern[:i+3], pattern[i+4:]
+			if strings.HasPrefix(origin, scheme) && strings.HasSuffix(origin, suffix) && len(origin) > len(scheme)+len(suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
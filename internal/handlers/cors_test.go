@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+func TestCORSPoliciesPerOrigin(t *testing.T) {
+	app := fiber.New()
+	app.Use(NewCORSMiddleware([]models.CORSPolicy{
+		{AllowedOrigins: []string{"https://*.widgets.example"}, AllowedMethods: []string{"GET"}},
+		{AllowedOrigins: []string{"https://admin.example.com"}, AllowedMethods: []string{"GET", "POST"}, AllowCredentials: true},
+	}))
+	app.Get("/api/sabda", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	tests := []struct {
+		origin      string
+		allowOrigin string
+		credentials string
+	}{
+		{"https://church.widgets.example", "https://church.widgets.example", ""},
+		{"https://admin.example.com", "https://admin.example.com", "true"},
+		{"https://elsewhere.example", "", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/sabda", nil)
+		req.Header.Set(fiber.HeaderOrigin, tt.origin)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tt.origin, err)
+		}
+		if got := resp.Header.Get(fiber.HeaderAccessControlAllowOrigin); got != tt.allowOrigin {
+			t.Errorf("%s: Access-Control-Allow-Origin = %q, want %q", tt.origin, got, tt.allowOrigin)
+		}
+		if got := resp.Header.Get(fiber.HeaderAccessControlAllowCredentials); got != tt.credentials {
+			t.Errorf("%s: Access-Control-Allow-Credentials = %q, want %q", tt.origin, got, tt.credentials)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+// DiagnosticsHandler exposes operational counters for the running service
+type DiagnosticsHandler struct {
+	scraperService *services.ScraperService
+	cacheService   *services.CacheService
+	statsService   *services.StatsService
+}
+
+// NewDiagnosticsHandler creates a new diagnostics handler
+func NewDiagnosticsHandler(scraperService *services.ScraperService, cacheService *services.CacheService, statsService *services.StatsService) *DiagnosticsHandler {
+	return &DiagnosticsHandler{
+		scraperService: scraperService,
+		cacheService:   cacheService,
+		statsService:   statsService,
+	}
+}
+
+// GetDiagnostics returns scrape outcome and extraction method counters, quota usage, queue
+// depth, cache state and the most recent upstream scrapes
+func (h *DiagnosticsHandler) GetDiagnostics(c *fiber.Ctx) error {
+	methods := h.scraperService.ExtractionMethodCounts()
+	recent, capacity := h.scraperService.RecentScrapes()
+	return c.JSON(models.APIResponse{
+		Status:  "success",
+		Message: "Diagnostics retrieved successfully",
+		Data: map[string]interface{}{
+			"scrape_outcomes":     h.scraperService.OutcomeCounts(),
+			"extraction_methods":  methods,
+			"text_splitter_share": textSplitterShare(methods),
+			"cache_size":          h.cacheService.Size(),
+			"scrape_quota":        h.scraperService.QuotaStatus(),
+			"scrape_queue":        h.scraperService.QueueStatus(),
+			"recent_scrapes":      recent,
+			"history_capacity":    capacity,
+		},
+		Metadata: map[string]interface{}{
+			"timestamp": models.Now(),
+		},
+	})
+}
+
+// GetCorpusStats returns aggregate stats over the devotionals currently cached. Nothing is
+// scraped, so the cost is bounded by the cache size.
+func (h *DiagnosticsHandler) GetCorpusStats(c *fiber.Ctx) error {
+	return c.JSON(models.APIResponse{
+		Status:  "success",
+		Message: "Corpus stats retrieved successfully",
+		Data:    h.statsService.Corpus(h.cacheService.Snapshot()),
+		Metadata: map[string]interface{}{
+			"timestamp": models.Now(),
+		},
+	})
+}
+
+// textSplitterShare returns the fraction of fresh scrapes whose paragraphs came from the text
+// splitter, 0 before any scrape
+func textSplitterShare(methods map[string]int64) float64 {
+	var total int64
+	for _, count := range methods {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(methods[scraper.ParagraphsFromText]) / float64(total)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"log"
+	"strings"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+const formatJSON = "json"
+
+// supportedFormats lists every output format /api/sabda knows how to render
+var supportedFormats = []string{formatJSON, formatSSML, formatJSONLD}
+
+// newFormatSet builds the set of enabled formats, defaulting to all supported formats
+func newFormatSet(enabled []string) map[string]bool {
+	formats := make(map[string]bool)
+	if len(enabled) == 0 {
+		for _, format := range supportedFormats {
+			formats[format] = true
+		}
+		return formats
+	}
+
+	for _, format := range enabled {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if !isSupportedFormat(format) {
+			log.Printf("Ignoring unknown output format in server.enabled_formats: %q", format)
+			continue
+		}
+		formats[format] = true
+	}
+	return formats
+}
+
+func isSupportedFormat(format string) bool {
+	for _, supported := range supportedFormats {
+		if format == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFormatEnabled rejects a supported output format that this deployment has disabled
+func (h *SABDAHandler) checkFormatEnabled(format string) *models.APIResponse {
+	if h.enabledFormats[format] {
+		return nil
+	}
+	return &models.APIResponse{
+		Status:  "error",
+		Message: "Output format '" + format + "' is disabled on this deployment",
+		Metadata: map[string]interface{}{
+			"error_type":      "NotAcceptableError",
+			"enabled_formats": h.enabledFormatList(),
+		},
+	}
+}
+
+func (h *SABDAHandler) enabledFormatList() []string {
+	var formats []string
+	for _, format := range supportedFormats {
+		if h.enabledFormats[format] {
+			formats = append(formats, format)
+		}
+	}
+	return formats
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+)
+
+// upstreamStub answers every sabda.org request with testdata/esh.html and counts the
+// requests per URL path
+type upstreamStub struct {
+	mutex    sync.Mutex
+	requests map[string]int
+}
+
+func (u *upstreamStub) RoundTrip(r *http.Request) (*http.Response, error) {
+	u.mutex.Lock()
+	u.requests[r.URL.Path]++
+	u.mutex.Unlock()
+	body, err := os.ReadFile("testdata/esh.html")
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": {"text/html"}}, Body: io.NopCloser(strings.NewReader(string(body))), Request: r}, nil
+}
+
+// count returns the requests made for paths containing fragment
+func (u *upstreamStub) count(fragment string) int {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	total := 0
+	for path, n := range u.requests {
+		if strings.Contains(path, fragment) {
+			total += n
+		}
+	}
+	return total
+}
+
+// newStubbedHandler returns a SABDA handler whose upstream requests go to a new stub, and the
+// cache it reads
+func newStubbedHandler(t *testing.T, cfg models.ScraperConfig) (*SABDAHandler, *upstreamStub, *services.CacheService) {
+	t.Helper()
+	stub := &upstreamStub{requests: make(map[string]int)}
+	previous := http.DefaultTransport
+	http.DefaultTransport = stub
+	t.Cleanup(func() { http.DefaultTransport = previous })
+
+	cfg.DisablePrintFallback = true
+	cfg.ShareInFlightScrapes = true
+	if cfg.MaxRangeDays == 0 {
+		cfg.MaxRangeDays = 31
+	}
+	if cfg.InteractiveTimeout == 0 {
+		cfg.InteractiveTimeout = time.Minute
+	}
+	cache := services.NewCacheService(time.Hour, time.Hour, 100, 0, 0, 0, 0, true)
+	scraperService := services.NewScraperService(false, cfg, cache, nil, nil, nil)
+	h := NewSABDAHandler(scraperService, services.NewStatsService(3, nil), nil, models.ServerConfig{IncludeMetadata: true}, cfg, models.BuildInfo{})
+	return h, stub, cache
+}
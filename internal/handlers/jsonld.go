@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+const formatJSONLD = "jsonld"
+
+// SABDA as the schema.org publisher of every devotional
+const (
+	jsonLDPublisherName = "Yayasan Lembaga SABDA"
+	jsonLDPublisherURL  = "https://www.sabda.org"
+)
+
+// jsonLDArticle is a devotional as a schema.org Article
+type jsonLDArticle struct {
+	Context          string       `json:"@context"`
+	Type             string       `json:"@type"`
+	Headline         string       `json:"headline"`
+	ArticleBody      string       `json:"articleBody"`
+	About            *jsonLDThing `json:"about,omitempty"`
+	DatePublished    string       `json:"datePublished,omitempty"`
+	Publisher        jsonLDThing  `json:"publisher"`
+	Author           *jsonLDThing `json:"author,omitempty"`
+	IsPartOf         *jsonLDThing `json:"isPartOf,omitempty"`
+	URL              string       `json:"url,omitempty"`
+	MainEntityOfPage string       `json:"mainEntityOfPage,omitempty"`
+	Image            []string     `json:"image,omitempty"`
+	Description      string       `json:"description,omitempty"`
+	WordCount        int          `json:"wordCount,omitempty"`
+	InLanguage       string       `json:"inLanguage"`
+}
+
+// jsonLDThing is a typed, named schema.org node
+type jsonLDThing struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// renderJSONLD renders content scraped for target as a schema.org Article JSON-LD document.
+// The article body is every paragraph, separated by blank lines. encoding/json escapes <, >
+// and & so the document can be embedded in a <script> element as is.
+func renderJSONLD(content *models.DevotionalContent, target scraper.Target) ([]byte, error) {
+	publication, _ := scraper.LookupPublication(target.Publication)
+	body := strings.Join(content.DevotionalContent, "\n\n")
+
+	article := jsonLDArticle{
+		Context:          "https://schema.org",
+		Type:             "Article",
+		Headline:         firstNonEmpty(content.DevotionalTitle, content.Title, publication.Name),
+		ArticleBody:      body,
+		Publisher:        jsonLDThing{Type: "Organization", Name: jsonLDPublisherName, URL: jsonLDPublisherURL},
+		URL:              content.SourceURL,
+		MainEntityOfPage: content.SourceURL,
+		Image:            content.Images,
+		Description:      content.Excerpt,
+		WordCount:        len(strings.Fields(body)),
+		InLanguage:       "id",
+	}
+	if content.ScriptureReference != "" {
+		article.About = &jsonLDThing{Type: "CreativeWork", Name: content.ScriptureReference}
+	}
+	if content.Author != "" {
+		article.Author = &jsonLDThing{Type: "Person", Name: content.Author}
+	}
+	if publication.Name != "" {
+		article.IsPartOf = &jsonLDThing{Type: "Periodical", Name: publication.Name}
+	}
+	if date, ok := scraper.NormalizeDate(target.Date); ok && target.Year > 0 {
+		article.DatePublished = fmt.Sprintf("%04d-%s-%s", target.Year, date[:2], date[2:])
+	}
+
+	return json.Marshal(article)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
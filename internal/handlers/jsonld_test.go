@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+func TestRenderJSONLDArticle(t *testing.T) {
+	content := testContent("Allah bekerja melalui hal kecil.", "Tuhan, ajarlah kami setia. Amin.")
+	content.DevotionalTitle = `Hal Kecil </script> & Besar`
+	content.ScriptureReference = "Lukas 13:18-21"
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+
+	document, err := renderJSONLD(content, target)
+	if err != nil {
+		t.Fatalf("renderJSONLD() error = %v", err)
+	}
+	if strings.Contains(string(document), "</script>") || strings.Contains(string(document), "&") {
+		t.Errorf("document carries unescaped markup: %s", document)
+	}
+	var article map[string]interface{}
+	if err := json.Unmarshal(document, &article); err != nil {
+		t.Fatalf("document is not JSON: %v", err)
+	}
+	for field, want := range map[string]interface{}{
+		"@context":      "https://schema.org",
+		"@type":         "Article",
+		"headline":      content.DevotionalTitle,
+		"articleBody":   "Allah bekerja melalui hal kecil.\n\nTuhan, ajarlah kami setia. Amin.",
+		"datePublished": "2025-09-02",
+	} {
+		if article[field] != want {
+			t.Errorf("%s = %v, want %v", field, article[field], want)
+		}
+	}
+	if about, _ := article["about"].(map[string]interface{}); about["name"] != "Lukas 13:18-21" {
+		t.Errorf("about = %v, want the scripture reference", article["about"])
+	}
+	if publisher, _ := article["publisher"].(map[string]interface{}); publisher["@type"] != "Organization" || publisher["name"] != jsonLDPublisherName {
+		t.Errorf("publisher = %v, want SABDA", article["publisher"])
+	}
+}
+
+func TestGetContentServesJSONLD(t *testing.T) {
+	cache := services.NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+	scraperService := services.NewScraperService(false, models.ScraperConfig{}, cache, nil, nil, nil)
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	cache.Set(target.CacheKey(), *testContent("Allah mengasihi dunia.", "Tuhan, ajarlah kami setia. Amin."), time.Now())
+
+	h := NewSABDAHandler(scraperService, services.NewStatsService(3, nil), nil, models.ServerConfig{IncludeMetadata: true}, models.ScraperConfig{InteractiveTimeout: time.Second}, models.BuildInfo{})
+	app := fiber.New()
+	app.Get("/api/sabda", h.GetContent)
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/sabda?year=2025&date=0902&output=jsonld&max_paragraphs=1", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if got := resp.Header.Get(fiber.HeaderContentType); !strings.HasPrefix(got, "application/ld+json") {
+		t.Errorf("Content-Type = %q, want application/ld+json", got)
+	}
+	var article map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&article); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if article["@type"] != "Article" || article["articleBody"] != "Allah mengasihi dunia." {
+		t.Errorf("article = %v, want an Article cut to max_paragraphs", article)
+	}
+}
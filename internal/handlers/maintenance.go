@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+)
+
+// MaintenanceGuard answers requests with 503 and Retry-After while maintenance mode is on
+func MaintenanceGuard(maintenanceService *services.MaintenanceService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		status := maintenanceService.Status()
+		if !status.Enabled {
+			return c.Next()
+		}
+
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(status.RetryAfter.Seconds())))
+		return c.Status(503).JSON(models.APIResponse{
+			Status:  "error",
+			Message: status.Message,
+			Metadata: map[string]interface{}{
+				"error_type":        "MaintenanceError",
+				"maintenance_since": status.Since,
+			},
+		})
+	}
+}
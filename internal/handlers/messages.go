@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Languages validation messages are available in
+const (
+	langIndonesian = "id"
+	langEnglish    = "en"
+)
+
+// languageKey is the Locals key holding the language chosen for the request's messages
+const languageKey = "language"
+
+// messageCode identifies a validation message independently of its language
+type messageCode string
+
+// Validation message codes, reported per field in metadata.error_codes
+const (
+	msgValidationFailed      messageCode = "validation_failed"
+	msgInvalidBody           messageCode = "invalid_body"
+	msgRequired              messageCode = "required"
+	msgRequiredList          messageCode = "required_list"
+	msgRequiredBool          messageCode = "required_bool"
+	msgTooManyKeys           messageCode = "too_many_keys"
+	msgUnsupportedFormat     messageCode = "unsupported_format"
+	msgInvalidDateFormat     messageCode = "invalid_date_format"
+	msgUnknownPublication    messageCode = "unknown_publication"
+	msgPublicationDisabled   messageCode = "publication_disabled"
+	msgNoPublicationsEnabled messageCode = "no_publications_enabled"
+	msgEditionUnsupported    messageCode = "edition_unsupported"
+	msgEditionRequired       messageCode = "edition_required"
+	msgEditionInvalid        messageCode = "edition_invalid"
+	msgYearRequired          messageCode = "year_required"
+	msgNotInteger            messageCode = "not_integer"
+	msgYearOutOfRange        messageCode = "year_out_of_range"
+	msgDateRequired          messageCode = "date_required"
+	msgDateMalformed         messageCode = "date_malformed"
+	msgDateOutOfRange        messageCode = "date_out_of_range"
+	msgNotPositiveInteger    messageCode = "not_positive_integer"
+	msgRangeDateInvalid      messageCode = "range_date_invalid"
+	msgRangeEndBeforeStart   messageCode = "range_end_before_start"
+	msgRangeTooLong          messageCode = "range_too_long"
+	msgDateWithRange         messageCode = "date_with_range"
+	msgRangeUnsupported      messageCode = "range_unsupported"
+)
+
+// messageCatalog holds the fmt templates of every message code in each supported language
+var messageCatalog = map[string]map[messageCode]string{
+	langIndonesian: {
+		msgValidationFailed:      "Validasi gagal: %s",
+		msgInvalidBody:           "isi permintaan tidak valid",
+		msgRequired:              "wajib diisi",
+		msgRequiredList:          "wajib diisi, berupa daftar yang tidak kosong",
+		msgRequiredBool:          "wajib diisi (true atau false)",
+		msgTooManyKeys:           "paling banyak %d kunci per permintaan",
+		msgUnsupportedFormat:     "tidak didukung, format yang didukung: %s",
+		msgInvalidDateFormat:     "harus %s atau %s",
+		msgUnknownPublication:    "tidak dikenal, publikasi yang didukung: %s",
+		msgPublicationDisabled:   "%s tidak diaktifkan di server ini, publikasi yang aktif: %s",
+		msgNoPublicationsEnabled: "%s tidak diaktifkan di server ini, tidak ada publikasi yang aktif",
+		msgEditionUnsupported:    "tidak didukung, %s diindeks menurut tahun dan tanggal",
+		msgEditionRequired:       "wajib diisi untuk %s (mis. ?publication=%s&edition=150)",
+		msgEditionInvalid:        "tidak valid untuk %s, pola yang diharapkan %s",
+		msgYearRequired:          "wajib diisi (mis. 2025)",
+		msgNotInteger:            "harus berupa bilangan bulat",
+		msgYearOutOfRange:        "harus antara 2000 dan %d",
+		msgDateRequired:          "wajib diisi dalam format MMDD (mis. 0902)",
+		msgDateMalformed:         "format tidak valid, gunakan MMDD (mis. 0902 untuk 2 September)",
+		msgDateOutOfRange:        "tanggal tidak valid, bulan harus 01-12, hari harus 01-31",
+		msgNotPositiveInteger:    "harus berupa bilangan bulat positif",
+		msgRangeDateInvalid:      "harus tanggal yang valid dalam format MMDD (mis. %s)",
+		msgRangeEndBeforeStart:   "tidak boleh sebelum start",
+		msgRangeTooLong:          "rentang tidak boleh lebih dari %d hari",
+		msgDateWithRange:         "tidak boleh dipakai bersama start dan end",
+		msgRangeUnsupported:      "rentang tanggal hanya didukung untuk publikasi yang diindeks menurut tanggal",
+	},
+	langEnglish: {
+		msgValidationFailed:      "Validation failed: %s",
+		msgInvalidBody:           "invalid request body",
+		msgRequired:              "required",
+		msgRequiredList:          "required, a non-empty list",
+		msgRequiredBool:          "required (true or false)",
+		msgTooManyKeys:           "at most %d keys per request",
+		msgUnsupportedFormat:     "unsupported, supported formats: %s",
+		msgInvalidDateFormat:     "must be %s or %s",
+		msgUnknownPublication:    "unknown, supported publications: %s",
+		msgPublicationDisabled:   "%s is not enabled on this server, enabled publications: %s",
+		msgNoPublicationsEnabled: "%s is not enabled on this server, no publications are enabled",
+		msgEditionUnsupported:    "not supported, %s is indexed by year and date",
+		msgEditionRequired:       "required for %s (e.g., ?publication=%s&edition=150)",
+		msgEditionInvalid:        "invalid for %s, expected pattern %s",
+		msgYearRequired:          "required (e.g., 2025)",
+		msgNotInteger:            "must be a valid integer",
+		msgYearOutOfRange:        "must be between 2000 and %d",
+		msgDateRequired:          "required in MMDD format (e.g., 0902)",
+		msgDateMalformed:         "invalid format, expected MMDD (e.g., 0902 for September 2nd)",
+		msgDateOutOfRange:        "invalid date, month must be 01-12, day must be 01-31",
+		msgNotPositiveInteger:    "must be a positive integer",
+		msgRangeDateInvalid:      "must be a valid date in MMDD format (e.g., %s)",
+		msgRangeEndBeforeStart:   "must not be before start",
+		msgRangeTooLong:          "range must not exceed %d days",
+		msgDateWithRange:         "not allowed together with start and end",
+		msgRangeUnsupported:      "date ranges are only supported for date-indexed publications",
+	},
+}
+
+// localize renders the message for code in lang, falling back to Indonesian for languages
+// without a catalog
+func localize(lang string, code messageCode, args ...interface{}) string {
+	messages, ok := messageCatalog[lang]
+	if !ok {
+		messages = messageCatalog[langIndonesian]
+	}
+	return fmt.Sprintf(messages[code], args...)
+}
+
+// Localize picks the language of the request's validation messages: the lang parameter, else
+// the highest rated Accept-Language range with a catalog, else defaultLang
+func Localize(defaultLang string) fiber.Handler {
+	defaultLang = strings.ToLower(strings.TrimSpace(defaultLang))
+	if _, ok := messageCatalog[defaultLang]; !ok {
+		log.Printf("Unknown server.default_language %q; using %s", defaultLang, langIndonesian)
+		defaultLang = langIndonesian
+	}
+
+	return func(c *fiber.Ctx) error {
+		c.Locals(languageKey, negotiateLanguage(c.Query("lang"), c.Get(fiber.HeaderAcceptLanguage), defaultLang))
+		return c.Next()
+	}
+}
+
+// negotiateLanguage resolves the message language from the lang parameter and Accept-Language
+// header. Region subtags are ignored, so id-ID selects Indonesian.
+func negotiateLanguage(param, acceptLanguage, defaultLang string) string {
+	if lang := primaryLanguage(param); messageCatalog[lang] != nil {
+		return lang
+	}
+
+	chosen, chosenQ := defaultLang, 0.0
+	for _, r := range parseAccept(acceptLanguage) {
+		if lang := primaryLanguage(r.mediaType); messageCatalog[lang] != nil && r.q > chosenQ {
+			chosen, chosenQ = lang, r.q
+		}
+	}
+	return chosen
+}
+
+// primaryLanguage returns the primary subtag of a language tag, lowercased
+func primaryLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// requestLanguage returns the language Localize chose for c, or Indonesian without it
+func requestLanguage(c *fiber.Ctx) string {
+	if lang, ok := c.Locals(languageKey).(string); ok {
+		return lang
+	}
+	return langIndonesian
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+)
+
+func TestValidationMessagesAreLocalized(t *testing.T) {
+	cache := services.NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+	scraperService := services.NewScraperService(false, models.ScraperConfig{}, cache, nil, nil, nil)
+	sabda := NewSABDAHandler(scraperService, services.NewStatsService(3, nil), nil, models.ServerConfig{}, models.ScraperConfig{}, models.BuildInfo{})
+
+	tests := []struct {
+		name           string
+		defaultLang    string
+		query          string
+		acceptLanguage string
+		lang           string
+	}{
+		{"default", "id", "", "", langIndonesian},
+		{"lang parameter", "id", "&lang=en", "", langEnglish},
+		{"Accept-Language region", "id", "", "en-US", langEnglish},
+		{"Accept-Language q-values", "en", "", "en;q=0.3, id;q=0.8", langIndonesian},
+		{"lang beats the header", "id", "&lang=id", "en", langIndonesian},
+		{"unknown default", "fr", "", "", langIndonesian},
+	}
+	for _, tt := range tests {
+		app := fiber.New()
+		app.Use(Localize(tt.defaultLang))
+		app.Get("/api/sabda", sabda.GetContent)
+		req := httptest.NewRequest("GET", "/api/sabda?year=2025&date=1340"+tt.query, nil)
+		if tt.acceptLanguage != "" {
+			req.Header.Set(fiber.HeaderAcceptLanguage, tt.acceptLanguage)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tt.name, err)
+		}
+		var body struct {
+			Errors map[string]string `json:"errors"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: decode failed: %v", tt.name, err)
+		}
+		if want := localize(tt.lang, msgDateOutOfRange); body.Errors["date"] != want {
+			t.Errorf("%s: date error = %q, want %q", tt.name, body.Errors["date"], want)
+		}
+		if got := resp.Header.Get(fiber.HeaderContentLanguage); got != tt.lang {
+			t.Errorf("%s: Content-Language = %q, want %q", tt.name, got, tt.lang)
+		}
+	}
+}
+
+func TestMessageCatalogsCoverTheSameCodes(t *testing.T) {
+	for code := range messageCatalog[langIndonesian] {
+		if messageCatalog[langEnglish][code] == "" {
+			t.Errorf("%s has no English message", code)
+		}
+	}
+	for code := range messageCatalog[langEnglish] {
+		if messageCatalog[langIndonesian][code] == "" {
+			t.Errorf("%s has no Indonesian message", code)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"log"
+	"strconv"
+	"strings"
+)
+
+// formatMediaTypes maps each output format to the media type clients ask for it by
+var formatMediaTypes = map[string]string{
+	formatJSON:   "application/json",
+	formatSSML:   "application/ssml+xml",
+	formatJSONLD: "application/ld+json",
+}
+
+// acceptRange is one media range of an Accept header with its quality
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept returns the media ranges of an Accept header. Ranges with a malformed q are
+// skipped; other parameters are ignored.
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		if mediaType == "" {
+			continue
+		}
+		q, ok := 1.0, true
+		for _, param := range fields[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(strings.TrimSpace(name), "q") {
+				var err error
+				q, err = strconv.ParseFloat(strings.TrimSpace(value), 64)
+				ok = err == nil && q >= 0 && q <= 1
+			}
+		}
+		if ok {
+			ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+		}
+	}
+	return ranges
+}
+
+// acceptQuality returns the quality ranges gives mediaType, taken from the most specific
+// range matching it: an exact type, then type/*, then */*. It is 0 when none matches.
+func acceptQuality(ranges []acceptRange, mediaType string) float64 {
+	mainType, _, _ := strings.Cut(mediaType, "/")
+	best, specificity := 0.0, 0
+	for _, r := range ranges {
+		level := 0
+		switch r.mediaType {
+		case mediaType:
+			level = 3
+		case mainType + "/*":
+			level = 2
+		case "*/*":
+			level = 1
+		}
+		if level > specificity {
+			best, specificity = r.q, level
+		}
+	}
+	return best
+}
+
+// negotiateFormat picks the enabled output format the Accept header rates highest. Formats of
+// equal quality are decided by order. JSON is the fallback when the header is empty or accepts
+// none of them, or the first enabled format in order when JSON is disabled.
+func negotiateFormat(accept string, order []string, enabled map[string]bool) string {
+	chosen := formatJSON
+	if !enabled[formatJSON] {
+		for _, format := range order {
+			if enabled[format] {
+				chosen = format
+				break
+			}
+		}
+	}
+
+	ranges := parseAccept(accept)
+	chosenQ := 0.0
+	for _, format := range order {
+		if !enabled[format] {
+			continue
+		}
+		if q := acceptQuality(ranges, formatMediaTypes[format]); q > chosenQ {
+			chosen, chosenQ = format, q
+		}
+	}
+	return chosen
+}
+
+// newNegotiationOrder returns the tie-break order of negotiated formats: the configured formats
+// first, then any supported ones left out, in supportedFormats order
+func newNegotiationOrder(configured []string) []string {
+	var order []string
+	seen := make(map[string]bool)
+	for _, format := range configured {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if !isSupportedFormat(format) {
+			log.Printf("Ignoring unknown output format in server.negotiation_order: %q", format)
+			continue
+		}
+		if !seen[format] {
+			seen[format] = true
+			order = append(order, format)
+		}
+	}
+	for _, format := range supportedFormats {
+		if !seen[format] {
+			order = append(order, format)
+		}
+	}
+	return order
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import "testing"
+
+func TestNegotiateFormat(t *testing.T) {
+	all := map[string]bool{formatJSON: true, formatSSML: true, formatJSONLD: true}
+	order := newNegotiationOrder(nil)
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{"empty", "", formatJSON},
+		{"exact type", "application/ssml+xml", formatSSML},
+		{"highest q wins", "application/json;q=0.5, application/ld+json;q=0.9", formatJSONLD},
+		{"later range with higher q", "application/ssml+xml;q=0.4, application/json;q=0.8", formatJSON},
+		{"specific range beats wildcard", "application/*;q=0.3, application/ssml+xml;q=0.6", formatSSML},
+		{"q=0 excludes", "application/json;q=0, */*;q=0.1", formatSSML},
+		{"malformed q ignored", "application/ld+json;q=abc, application/ssml+xml;q=0.2", formatSSML},
+		{"wildcard tie follows order", "*/*", formatJSON},
+		{"browser falls back to JSON", "text/html, application/xhtml+xml;q=0.9, application/xml;q=0.8", formatJSON},
+	}
+	for _, tt := range tests {
+		if got := negotiateFormat(tt.accept, order, all); got != tt.want {
+			t.Errorf("%s: negotiateFormat(%q) = %q, want %q", tt.name, tt.accept, got, tt.want)
+		}
+	}
+
+	if got := negotiateFormat("*/*", newNegotiationOrder([]string{formatJSONLD}), all); got != formatJSONLD {
+		t.Errorf("wildcard with jsonld first in order = %q, want %q", got, formatJSONLD)
+	}
+	if got := negotiateFormat("application/ssml+xml", order, map[string]bool{formatJSON: true}); got != formatJSON {
+		t.Errorf("disabled ssml = %q, want the JSON fallback", got)
+	}
+}
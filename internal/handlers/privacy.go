@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+)
+
+// maskedIPKey is the Locals key holding the client IP as it may be logged or returned
+const maskedIPKey = "masked_client_ip"
+
+// MaskClientIP stores the client IP masked by masker for the rest of the request. Rate
+// limiting keeps using the full IP; logs, audit events and responses use the masked one.
+func MaskClientIP(masker *services.IPMasker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(maskedIPKey, masker.Mask(getClientIP(c)))
+		return c.Next()
+	}
+}
+
+// displayIP returns the client IP for logs and responses, masked when MaskClientIP ran
+func displayIP(c *fiber.Ctx) string {
+	if ip, ok := c.Locals(maskedIPKey).(string); ok {
+		return ip
+	}
+	return getClientIP(c)
+}
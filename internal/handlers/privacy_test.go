@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+func TestMaskClientIPInLogsAndMetadata(t *testing.T) {
+	cache := services.NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+	scraperService := services.NewScraperService(false, models.ScraperConfig{}, cache, nil, nil, nil)
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	cache.Set(target.CacheKey(), *testContent("Allah mengasihi dunia."), time.Now())
+	sabda := NewSABDAHandler(scraperService, services.NewStatsService(3, nil), nil, models.ServerConfig{IncludeMetadata: true}, models.ScraperConfig{InteractiveTimeout: time.Second}, models.BuildInfo{})
+	auth := services.NewAuthService("secret", time.Hour, map[string]string{"flutter": "client-key"}, nil, nil)
+	limiter := services.NewRateLimitService(1000, 0, time.Minute, 0, 0)
+	authHandler := NewAuthHandler(auth, limiter, limiter, nil)
+
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	tests := []struct {
+		name   string
+		masker *services.IPMasker
+		want   string
+	}{
+		{"off", services.NewIPMasker(false, ""), "203.0.113.77"},
+		{"truncated", services.NewIPMasker(true, ""), "203.0.113.0"},
+	}
+	for _, tt := range tests {
+		logged.Reset()
+		app := fiber.New()
+		app.Use(MaskClientIP(tt.masker))
+		app.Get("/api/sabda", sabda.GetContent)
+		app.Post("/api/auth/token", authHandler.GetToken)
+
+		req := httptest.NewRequest("GET", "/api/sabda?year=2025&date=0902", nil)
+		req.Header.Set("X-Forwarded-For", "203.0.113.77")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tt.name, err)
+		}
+		var body struct {
+			Metadata models.ScrapingMetadata `json:"metadata"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: decode failed: %v", tt.name, err)
+		}
+		if body.Metadata.ClientIP != tt.want {
+			t.Errorf("%s: metadata client_ip = %q, want %q", tt.name, body.Metadata.ClientIP, tt.want)
+		}
+
+		req = httptest.NewRequest("POST", "/api/auth/token", strings.NewReader(`{"api_key": "wrong-key"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set("X-Forwarded-For", "203.0.113.77")
+		if _, err := app.Test(req); err != nil {
+			t.Fatalf("%s: token request failed: %v", tt.name, err)
+		}
+		if !strings.Contains(logged.String(), "Invalid API key attempt from IP: "+tt.want+"\n") {
+			t.Errorf("%s: log %q lacks the invalid key attempt from %s", tt.name, logged.String(), tt.want)
+		}
+		if tt.want != "203.0.113.77" && strings.Contains(logged.String(), "203.0.113.77") {
+			t.Errorf("%s: log contains the full client IP", tt.name)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+// rangeEntry is the per-date result of a range request
+type rangeEntry struct {
+	Type  string                    `json:"type"`
+	Year  int                       `json:"year"`
+	Date  string                    `json:"date"`
+	Data  *models.DevotionalContent `json:"data,omitempty"`
+	Error string                    `json:"error,omitempty"`
+}
+
+// tocEntry lists one scraped date in a range's table of contents
+type tocEntry struct {
+	Year               int    `json:"year"`
+	Date               string `json:"date"`
+	Title              string `json:"title"`
+	ScriptureReference string `json:"scripture_reference,omitempty"`
+}
+
+// tocLine is the closing line of a streamed range carrying its table of contents
+type tocLine struct {
+	Type            string     `json:"type"`
+	TableOfContents []tocEntry `json:"table_of_contents"`
+}
+
+// rangeResponse is the JSON response of a range request. It is an APIResponse with the table
+// of contents ahead of the content, so clients can build an index without scanning the data.
+type rangeResponse struct {
+	Status          string                 `json:"status"`
+	Message         string                 `json:"message"`
+	TableOfContents []tocEntry             `json:"table_of_contents,omitempty"`
+	Data            []rangeEntry           `json:"data"`
+	Metadata        map[string]interface{} `json:"metadata"`
+}
+
+// GetRange scrapes devotional content for every date between start and end (inclusive).
+// With stream=true or an application/x-ndjson Accept header the entries are streamed as
+// newline-delimited JSON as each date finishes; otherwise a single JSON array is returned.
+// Unless toc=false, a table of contents of the scraped dates is added to the response, or
+// sent as the last line of a stream since titles are only known once each date is scraped.
+func (h *SABDAHandler) GetRange(c *fiber.Ctx) error {
+	startStr := c.Query("start")
+	endStr := c.Query("end")
+
+	errs := validationErrors{}
+	validatePublicationEnabled(errs, h.scraperService.EnabledPublications(), scraper.DefaultPublication)
+	year, dates := parseDateRange(c, errs, h.maxRangeDays)
+	if len(errs) > 0 {
+		return errs.send(c)
+	}
+
+	ctx := clientScrapeContext(c)
+	withTOC := c.QueryBool("toc", true)
+
+	if c.QueryBool("stream") || strings.Contains(c.Get("Accept"), "application/x-ndjson") {
+		c.Set("Content-Type", "application/x-ndjson")
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			encoder := json.NewEncoder(w)
+			toc := make([]tocEntry, 0, len(dates))
+			for _, date := range dates {
+				entry := h.scrapeRangeEntry(ctx, year, date)
+				if err := encoder.Encode(entry); err != nil {
+					log.Printf("Range stream aborted at %d/%s: %v", year, date, err)
+					return
+				}
+				if err := w.Flush(); err != nil {
+					log.Printf("Range stream client disconnected at %d/%s: %v", year, date, err)
+					return
+				}
+				toc = appendTOC(toc, entry)
+			}
+			if withTOC {
+				encoder.Encode(tocLine{Type: "table_of_contents", TableOfContents: toc})
+				w.Flush()
+			}
+		})
+		return nil
+	}
+
+	entries := make([]rangeEntry, 0, len(dates))
+	for _, date := range dates {
+		entries = append(entries, h.scrapeRangeEntry(ctx, year, date))
+	}
+
+	response := rangeResponse{
+		Status:  "success",
+		Message: "Range scraped successfully",
+		Data:    entries,
+		Metadata: map[string]interface{}{
+			"year":      year,
+			"start":     startStr,
+			"end":       endStr,
+			"count":     len(entries),
+			"timestamp": models.Now(),
+		},
+	}
+	if withTOC {
+		for _, entry := range entries {
+			response.TableOfContents = appendTOC(response.TableOfContents, entry)
+		}
+	}
+	return c.JSON(response)
+}
+
+// appendTOC adds entry to the table of contents if its date was scraped
+func appendTOC(toc []tocEntry, entry rangeEntry) []tocEntry {
+	if entry.Data == nil {
+		return toc
+	}
+	return append(toc, newTOCEntry(entry.Year, entry.Date, entry.Data))
+}
+
+// newTOCEntry lists content scraped for year and date. The devotional title is listed, falling
+// back to the page title for issues without one.
+func newTOCEntry(year int, date string, content *models.DevotionalContent) tocEntry {
+	title := content.DevotionalTitle
+	if title == "" {
+		title = content.Title
+	}
+	return tocEntry{
+		Year:               year,
+		Date:               date,
+		Title:              title,
+		ScriptureReference: content.ScriptureReference,
+	}
+}
+
+func (h *SABDAHandler) scrapeRangeEntry(ctx context.Context, year int, date string) rangeEntry {
+	entry := rangeEntry{Year: year, Date: date}
+
+	content, _, errMsg := scrapeRangeDate(ctx, h.scraperService, year, date, h.scrapeTimeout)
+	if errMsg != "" {
+		entry.Type = "error"
+		entry.Error = errMsg
+		return entry
+	}
+
+	entry.Type = "content"
+	entry.Data = withoutHTML(content)
+	return entry
+}
+
+// scrapeRangeDate scrapes one e-SH date of a range through the cache, allowing it up to
+// timeout. On failure it returns a client-facing message instead of the content.
+func scrapeRangeDate(ctx context.Context, scraperService *services.ScraperService, year int, date string, timeout time.Duration) (*models.DevotionalContent, bool, string) {
+	ctx, cancel := services.WithScrapeTimeout(ctx, timeout)
+	defer cancel()
+	result, err := scraperService.ScrapeContent(ctx, scraper.Target{Publication: scraper.DefaultPublication, Year: year, Date: date}, services.ScrapeOptions{})
+	if errors.Is(err, context.DeadlineExceeded) {
+		log.Printf("Range scraping timed out for %d/%s: %v", year, date, err)
+		return nil, false, "sabda.org did not respond in time for this date"
+	}
+	if errors.Is(err, scraper.ErrNotFound) {
+		return nil, false, "No devotional published for this date"
+	}
+	if errors.Is(err, scraper.ErrParseFailure) {
+		return nil, false, "SABDA served a page for this date but no devotional could be extracted"
+	}
+	if errors.Is(err, services.ErrUpstream) {
+		log.Printf("Range scraping error for %d/%s: %v", year, date, err)
+		return nil, false, "sabda.org could not be reached for this date"
+	}
+	if errors.Is(err, services.ErrQuotaExceeded) {
+		return nil, false, "Daily scrape quota exhausted; this date is not cached"
+	}
+	if errors.Is(err, services.ErrScrapeBusy) {
+		return nil, false, "All scrape slots are busy; retry this date shortly"
+	}
+	if errors.Is(err, services.ErrClientBusy) {
+		return nil, false, "Too many concurrent scrapes from this client; retry this date once they finish"
+	}
+	if err != nil {
+		log.Printf("Range scraping error for %d/%s: %v", year, date, err)
+		return nil, false, "Failed to retrieve content for this date"
+	}
+
+	content, ok := result.Data.(*models.DevotionalContent)
+	if !ok {
+		return nil, false, result.Message
+	}
+	metadata, _ := result.Metadata.(models.ScrapingMetadata)
+	return content, metadata.Cached, ""
+}
+
+// parseDateRange validates the year, start and end query parameters and returns the MMDD
+// dates from start to end inclusive, recording problems in errs
+func parseDateRange(c *fiber.Ctx, errs validationErrors, maxDays int) (int, []string) {
+	year := validateYear(errs, "year", c.Query("year"))
+	if len(errs) > 0 {
+		return 0, nil
+	}
+	return year, validateDateRange(errs, year, c.Query("start"), c.Query("end"), maxDays)
+}
+
+// validateDateRange returns the MMDD dates from startStr to endStr inclusive within year,
+// recording problems in errs
+func validateDateRange(errs validationErrors, year int, startStr, endStr string, maxDays int) []string {
+	start, startOK := parseRangeDate(year, startStr)
+	if !startOK {
+		errs.add("start", msgRangeDateInvalid, "0901")
+	}
+	end, endOK := parseRangeDate(year, endStr)
+	if !endOK {
+		errs.add("end", msgRangeDateInvalid, "0930")
+	}
+	if startOK && endOK && end.Before(start) {
+		errs.add("end", msgRangeEndBeforeStart)
+	}
+	if startOK && endOK && int(end.Sub(start).Hours()/24)+1 > maxDays {
+		errs.add("end", msgRangeTooLong, maxDays)
+	}
+	if len(errs) > 0 {
+		return nil
+	}
+
+	var dates []string
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d.Format("0102"))
+	}
+	return dates
+}
+
+// parseRangeDate parses an MMDD string into a date in the given year, rejecting impossible dates
+func parseRangeDate(year int, mmdd string) (time.Time, bool) {
+	mmdd, ok := scraper.NormalizeDate(mmdd)
+	if !ok {
+		return time.Time{}, false
+	}
+	month, monthErr := strconv.Atoi(mmdd[:2])
+	day, dayErr := strconv.Atoi(mmdd[2:])
+	if monthErr != nil || dayErr != nil {
+		return time.Time{}, false
+	}
+
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Month() != time.Month(month) || date.Day() != day {
+		return time.Time{}, false
+	}
+	return date, true
+}
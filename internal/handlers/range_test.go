@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+func TestOverlappingRangesShareUpstreamFetches(t *testing.T) {
+	h, stub, _ := newStubbedHandler(t, models.ScraperConfig{})
+	app := fiber.New()
+	app.Get("/api/sabda/range", h.GetRange)
+
+	var wg sync.WaitGroup
+	for _, query := range []string{"year=2025&start=0901&end=0902", "year=2025&start=0902&end=0903"} {
+		wg.Add(1)
+		go func(query string) {
+			defer wg.Done()
+			resp, err := app.Test(httptest.NewRequest("GET", "/api/sabda/range?"+query, nil), -1)
+			if err != nil {
+				t.Errorf("range %s failed: %v", query, err)
+				return
+			}
+			if resp.StatusCode != 200 {
+				t.Errorf("range %s: status %d", query, resp.StatusCode)
+			}
+		}(query)
+	}
+	wg.Wait()
+
+	for _, date := range []string{"/09/01", "/09/02", "/09/03"} {
+		if got := stub.count(date); got != 1 {
+			t.Errorf("upstream fetches of %s = %d, want 1", date, got)
+		}
+	}
+}
+
+func TestRangeTableOfContentsMatchesContent(t *testing.T) {
+	h, _, cache := newStubbedHandler(t, models.ScraperConfig{})
+	for date, title := range map[string]string{"0901": "Hidup dalam Anugerah", "0903": "Berjalan dalam Terang"} {
+		content := testContent("Renungan hari ini.")
+		content.DevotionalTitle = title
+		content.ScriptureReference = "Roma 5:1-11"
+		cache.Set(scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: date}.CacheKey(), *content, time.Now())
+	}
+	cache.SetNegative(scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}.CacheKey())
+	app := fiber.New()
+	app.Get("/api/sabda/range", h.GetRange)
+
+	// JSON response
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/sabda/range?year=2025&start=0901&end=0903", nil))
+	if err != nil {
+		t.Fatalf("range request failed: %v", err)
+	}
+	var response rangeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("decoding range response: %v", err)
+	}
+	var scraped []rangeEntry
+	for _, entry := range response.Data {
+		if entry.Data != nil {
+			scraped = append(scraped, entry)
+		}
+	}
+	assertTOCMatches(t, response.TableOfContents, scraped)
+
+	// NDJSON stream: the last line carries the same table of contents
+	resp, err = app.Test(httptest.NewRequest("GET", "/api/sabda/range?year=2025&start=0901&end=0903&stream=true", nil))
+	if err != nil {
+		t.Fatalf("streamed range request failed: %v", err)
+	}
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 4 {
+		t.Fatalf("stream has %d lines, want 3 dates and the table of contents", len(lines))
+	}
+	var toc tocLine
+	if err := json.Unmarshal([]byte(lines[3]), &toc); err != nil || toc.Type != "table_of_contents" {
+		t.Fatalf("last stream line %q is not a table of contents", lines[3])
+	}
+	assertTOCMatches(t, toc.TableOfContents, scraped)
+}
+
+// assertTOCMatches fails t unless toc lists exactly the scraped entries, in order
+func assertTOCMatches(t *testing.T, toc []tocEntry, scraped []rangeEntry) {
+	t.Helper()
+	if len(toc) != len(scraped) || len(toc) != 2 {
+		t.Fatalf("table of contents has %d entries for %d scraped dates, want 2", len(toc), len(scraped))
+	}
+	for i, entry := range toc {
+		content := scraped[i]
+		if entry.Year != content.Year || entry.Date != content.Date || entry.Title != content.Data.DevotionalTitle || entry.ScriptureReference != content.Data.ScriptureReference {
+			t.Errorf("table of contents entry %d = %+v, want %d/%s %q %q", i, entry, content.Year, content.Date, content.Data.DevotionalTitle, content.Data.ScriptureReference)
+		}
+	}
+}
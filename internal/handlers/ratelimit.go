@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+)
+
+// sendRateLimited answers a request limiter refused with 429. The wait until the next allowed
+// request is sent as Retry-After and, with the limit, in the metadata for clients that do not
+// read headers.
+func sendRateLimited(c *fiber.Ctx, limiter services.RateLimiter, clientIP, message string) error {
+	retryAfter := int(limiter.RetryAfter(clientIP).Seconds()) + 1
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+	return c.Status(429).JSON(models.APIResponse{
+		Status:  "error",
+		Message: message,
+		Metadata: map[string]interface{}{
+			"error_type":          "RateLimitError",
+			"retry_after_seconds": retryAfter,
+			"limit":               limiter.Limit(),
+		},
+	})
+}
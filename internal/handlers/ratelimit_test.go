@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+)
+
+func TestRateLimitedBodyCarriesRetryAfter(t *testing.T) {
+	auth := services.NewAuthService("secret", time.Hour, map[string]string{"flutter": "client-key"}, nil, nil)
+	limiter := services.NewRateLimitService(2, 0, time.Minute, 0, 0)
+	h := NewAuthHandler(auth, limiter, limiter, nil)
+	app := fiber.New()
+	app.Post("/api/auth/token", h.GetToken)
+
+	for i := 1; i <= 3; i++ {
+		req := httptest.NewRequest("POST", "/api/auth/token", strings.NewReader(`{"api_key": "client-key"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+		if i < 3 {
+			if resp.StatusCode != 200 {
+				t.Fatalf("request %d: status %d, want 200 within the limit", i, resp.StatusCode)
+			}
+			continue
+		}
+
+		if resp.StatusCode != 429 {
+			t.Fatalf("request 3: status %d, want 429", resp.StatusCode)
+		}
+		var body struct {
+			Metadata struct {
+				ErrorType         string `json:"error_type"`
+				RetryAfterSeconds int    `json:"retry_after_seconds"`
+				Limit             int    `json:"limit"`
+			} `json:"metadata"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decode failed: %v", err)
+		}
+		if body.Metadata.ErrorType != "RateLimitError" || body.Metadata.RetryAfterSeconds <= 0 || body.Metadata.Limit != 2 {
+			t.Errorf("metadata = %+v, want RateLimitError with a positive retry_after_seconds and limit 2", body.Metadata)
+		}
+		if header := resp.Header.Get(fiber.HeaderRetryAfter); header != strconv.Itoa(body.Metadata.RetryAfterSeconds) {
+			t.Errorf("Retry-After = %q, want the body's %d", header, body.Metadata.RetryAfterSeconds)
+		}
+	}
+}
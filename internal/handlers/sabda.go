@@ -1,125 +1,174 @@
 package handlers
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"log"
-	"regexp"
+	"net/http"
+	"runtime"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/pranahonk/sabda-scraper-go/internal/models"
 	"github.com/pranahonk/sabda-scraper-go/internal/services"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
 )
 
 // SABDAHandler handles SABDA scraping endpoints
 type SABDAHandler struct {
-	scraperService *services.ScraperService
+	scraperService   *services.ScraperService
+	statsService     *services.StatsService
+	selfTest         *services.SelfTestService
+	enabledFormats   map[string]bool
+	negotiationOrder []string
+	maxRangeDays     int
+	scrapeTimeout    time.Duration
+	rejectNoCache    bool
+	minMaxAge        time.Duration
+	includeMeta      bool
+	reportFilters    bool
+	maxResponseBytes int
+	streamThreshold  int
+	canonicalRefs    bool
+	build            models.BuildInfo
+	startedAt        time.Time
 }
 
-// NewSABDAHandler creates a new SABDA handler
-func NewSABDAHandler(scraperService *services.ScraperService) *SABDAHandler {
+// NewSABDAHandler creates a new SABDA handler. build identifies the running binary in the
+// version endpoint and the API documentation.
+func NewSABDAHandler(scraperService *services.ScraperService, statsService *services.StatsService, selfTest *services.SelfTestService, serverCfg models.ServerConfig, scraperCfg models.ScraperConfig, build models.BuildInfo) *SABDAHandler {
 	return &SABDAHandler{
-		scraperService: scraperService,
+		build:            build,
+		startedAt:        time.Now(),
+		scraperService:   scraperService,
+		statsService:     statsService,
+		selfTest:         selfTest,
+		enabledFormats:   newFormatSet(serverCfg.EnabledFormats),
+		negotiationOrder: newNegotiationOrder(serverCfg.NegotiationOrder),
+		includeMeta:      serverCfg.IncludeMetadata,
+		reportFilters:    serverCfg.ReportFilters,
+		maxResponseBytes: serverCfg.MaxResponseBytes,
+		streamThreshold:  serverCfg.StreamThresholdBytes,
+		maxRangeDays:     scraperCfg.MaxRangeDays,
+		scrapeTimeout:    scraperCfg.InteractiveTimeout,
+		rejectNoCache:    !strings.EqualFold(scraperCfg.NonAdminNoCache, "ignore"),
+		minMaxAge:        scraperCfg.MinClientMaxAge,
+		canonicalRefs:    scraperCfg.CanonicalRefs,
 	}
 }
 
 // GetContent scrapes SABDA devotional content
 func (h *SABDAHandler) GetContent(c *fiber.Ctx) error {
-	// Get query parameters
-	yearStr := c.Query("year")
-	date := c.Query("date")
+	// Errors must not be cached downstream; cacheable outcomes override this below
+	c.Set(fiber.HeaderCacheControl, "no-store")
 
-	// Enhanced parameter validation
-	var validationErrors []string
+	errs := validationErrors{}
+	target := parseTarget(c, errs, h.scraperService.EnabledPublications())
 
-	if yearStr == "" {
-		validationErrors = append(validationErrors, "Year parameter is required (e.g., ?year=2025)")
+	// An explicit output parameter wins over the Accept header
+	format := strings.ToLower(c.Query("output"))
+	if format == "" {
+		format = negotiateFormat(c.Get(fiber.HeaderAccept), h.negotiationOrder, h.enabledFormats)
+	} else if !isSupportedFormat(format) {
+		errs.add("output", msgUnsupportedFormat, joinStrings(supportedFormats, ", "))
 	}
 
-	if date == "" {
-		validationErrors = append(validationErrors, "Date parameter is required in MMDD format (e.g., ?date=0902)")
+	// Optional paragraph limit for previews
+	maxParagraphs := validatePositiveInt(errs, "max_paragraphs", c.Query("max_paragraphs"))
+
+	// Optional per-request freshness demand in seconds
+	maxAge := time.Duration(validatePositiveInt(errs, "max_age", c.Query("max_age"))) * time.Second
+
+	if len(errs) > 0 {
+		return errs.send(c)
 	}
 
-	if len(validationErrors) > 0 {
-		return c.Status(400).JSON(models.APIResponse{
-			Status:  "error",
-			Message: joinStrings(validationErrors, "; "),
-			Metadata: map[string]interface{}{
-				"error_type": "ValidationError",
-			},
-		})
+	// Check requested output format against the deployment allowlist
+	if resp := h.checkFormatEnabled(format); resp != nil {
+		return c.Status(406).JSON(resp)
 	}
 
-	// Parse year
-	year, err := strconv.Atoi(yearStr)
-	if err != nil {
-		return c.Status(400).JSON(models.APIResponse{
-			Status:  "error",
-			Message: "Year must be a valid integer",
-			Metadata: map[string]interface{}{
-				"error_type":    "ValidationError",
-				"provided_year": yearStr,
-			},
-		})
+	includeHTML := c.QueryBool("include_html")
+	headOnly := c.QueryBool("head_only")
+	includeStats := c.QueryBool("stats")
+	stripRefs := c.QueryBool("strip_refs")
+
+	// Forced refreshes hit sabda.org directly, so only admin tokens may bypass the cache
+	opts := services.ScrapeOptions{}
+	if c.QueryBool("no_cache") {
+		if isAdmin(c) {
+			opts.BypassCache = true
+		} else if h.rejectNoCache {
+			return c.Status(403).JSON(models.APIResponse{
+				Status:  "error",
+				Message: "no_cache requires an admin token",
+				Metadata: map[string]interface{}{
+					"error_type": "AuthorizationError",
+				},
+			})
+		}
 	}
 
-	// Validate year range
-	currentYear := time.Now().Year()
-	if year < 2000 || year > currentYear+1 {
-		return c.Status(400).JSON(models.APIResponse{
-			Status:  "error",
-			Message: "Year must be between 2000 and " + strconv.Itoa(currentYear+1),
-			Metadata: map[string]interface{}{
-				"error_type":    "ValidationError",
-				"provided_year": year,
-			},
-		})
+	// Non-admin freshness demands are floored so they cannot be used to force constant re-scrapes
+	if maxAge > 0 && !isAdmin(c) && maxAge < h.minMaxAge {
+		maxAge = h.minMaxAge
 	}
+	opts.MaxAge = maxAge
 
-	// Enhanced date format validation
-	dateRegex := regexp.MustCompile(`^\d{4}$`)
-	if !dateRegex.MatchString(date) {
-		return c.Status(400).JSON(models.APIResponse{
+	// Scrape content, failing fast rather than holding the client while sabda.org is slow
+	ctx, cancel := services.WithScrapeTimeout(clientScrapeContext(c), h.scrapeTimeout)
+	defer cancel()
+	result, err := h.scraperService.ScrapeContent(ctx, target, opts)
+	if errors.Is(err, context.DeadlineExceeded) {
+		log.Printf("Scrape timed out: %v", err)
+		return c.Status(504).JSON(models.APIResponse{
 			Status:  "error",
-			Message: "Date must be in MMDD format (e.g., 0902 for September 2nd)",
+			Message: "sabda.org did not respond in time; please retry shortly",
 			Metadata: map[string]interface{}{
-				"error_type":    "ValidationError",
-				"provided_date": date,
+				"error_type": "TimeoutError",
+				"reason":     services.ReasonUpstreamError,
 			},
 		})
 	}
-
-	// Validate date range (month 01-12, day 01-31)
-	if len(date) == 4 {
-		monthStr := date[:2]
-		dayStr := date[2:]
-		
-		month, monthErr := strconv.Atoi(monthStr)
-		day, dayErr := strconv.Atoi(dayStr)
-		
-		if monthErr != nil || dayErr != nil || month < 1 || month > 12 || day < 1 || day > 31 {
-			return c.Status(400).JSON(models.APIResponse{
-				Status:  "error",
-				Message: "Invalid date. Month must be 01-12, day must be 01-31",
-				Metadata: map[string]interface{}{
-					"error_type":    "ValidationError",
-					"provided_date": date,
-				},
-			})
+	if errors.Is(err, scraper.ErrNotFound) {
+		if negativeTTL := h.scraperService.NegativeCacheTTL(); negativeTTL > 0 {
+			c.Set(fiber.HeaderCacheControl, "public, max-age="+strconv.Itoa(int(negativeTTL.Seconds())))
 		}
+		return c.Status(404).JSON(result)
+	}
+	if errors.Is(err, scraper.ErrParseFailure) {
+		log.Printf("Parse failure: %v", err)
+		return c.Status(502).JSON(result)
+	}
+	if errors.Is(err, services.ErrUpstream) {
+		log.Printf("Upstream error: %v", err)
+		return c.Status(503).JSON(result)
+	}
+	if errors.Is(err, services.ErrQuotaExceeded) {
+		retryAfter := time.Until(h.scraperService.QuotaStatus().ResetsAt.Time)
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(retryAfter.Seconds())+1))
+		return c.Status(503).JSON(result)
+	}
+	if errors.Is(err, services.ErrScrapeBusy) {
+		c.Set(fiber.HeaderRetryAfter, "1")
+		return c.Status(503).JSON(result)
+	}
+	if errors.Is(err, services.ErrClientBusy) {
+		return c.Status(429).JSON(result)
 	}
-
-	// Scrape content
-	result, err := h.scraperService.ScrapeContent(year, date)
 	if err != nil {
 		log.Printf("Scraping error: %v", err)
 		return c.Status(500).JSON(models.APIResponse{
 			Status:  "error",
 			Message: "Internal server error occurred",
 			Metadata: map[string]interface{}{
-				"error_type":        "ServerException",
-				"client_ip":         c.Locals("client_ip"),
-				"timestamp":         time.Now(),
+				"error_type": "ServerException",
+				"client_ip":  c.Locals("client_ip"),
+				"timestamp":  models.Now(),
 			},
 		})
 	}
@@ -128,16 +177,114 @@ func (h *SABDAHandler) GetContent(c *fiber.Ctx) error {
 	if metadata, ok := result.Metadata.(models.ScrapingMetadata); ok {
 		metadata.Authenticated = true
 		metadata.AuthMethod = "JWT"
-		metadata.ClientIP = getClientIP(c)
-		metadata.RequestTimestamp = time.Now()
+		metadata.ClientIP = displayIP(c)
+		metadata.RequestTimestamp = models.Now()
+		if inferYear(c) {
+			metadata.InferredYear = target.Year
+		}
+		if !h.reportFilters {
+			metadata.FiltersApplied = nil
+		}
+		// An explicit date_format is echoed with the month and day it was read as
+		if format := c.Query("date_format"); format != "" && target.Date != "" {
+			metadata.DateFormat = strings.ToLower(format)
+			metadata.InterpretedMonth, _ = strconv.Atoi(target.Date[:2])
+			metadata.InterpretedDay, _ = strconv.Atoi(target.Date[2:])
+		}
 		result.Metadata = metadata
 	}
 
+	// Existence checks get a summary of the devotional in the metadata instead of the content
+	if content, ok := result.Data.(*models.DevotionalContent); ok && headOnly {
+		if metadata, ok := result.Metadata.(models.ScrapingMetadata); ok {
+			metadata.Content = &models.ContentSummary{
+				QualityScore:          scraper.QualityScore(content),
+				WordCount:             content.WordCount,
+				ParagraphCount:        content.ParagraphCount,
+				HasScriptureReference: content.ScriptureReference != "",
+				ExtractionMethod:      content.ExtractionMethod,
+			}
+			result.Metadata = metadata
+		}
+		result.Data = nil
+	}
+
+	// Shape content per request without touching the cached copy
+	if content, ok := result.Data.(*models.DevotionalContent); ok {
+		if !includeHTML {
+			content = withoutHTML(content)
+		}
+		if maxParagraphs > 0 {
+			content = truncateParagraphs(content, maxParagraphs)
+		}
+		if stripRefs {
+			content = withoutScriptureReferences(content, h.canonicalRefs)
+		}
+		if includeStats {
+			content = h.withWordStats(content)
+		}
+		result.Data = content
+	}
+
+	// Validators let clients check freshness with a HEAD before downloading the body
+	if metadata, ok := result.Metadata.(models.ScrapingMetadata); ok {
+		c.Set(fiber.HeaderLastModified, metadata.ScrapedAt.UTC().Format(http.TimeFormat))
+	}
+
 	statusCode := 200
 	if result.Status != "success" {
 		statusCode = 500
 	}
 
+	// Downstream caches may keep content for as long as our own cache would
+	if metadata, ok := result.Metadata.(models.ScrapingMetadata); ok && statusCode == 200 {
+		c.Set(fiber.HeaderCacheControl, "public, max-age="+strconv.FormatInt(metadata.CacheTTLRemainingSeconds, 10))
+	}
+
+	// Bandwidth-sensitive clients can drop provenance metadata from successful responses
+	if statusCode == 200 && !headOnly && !c.QueryBool("meta", h.includeMeta) {
+		result.Metadata = nil
+	}
+
+	if content, ok := result.Data.(*models.DevotionalContent); ok {
+		// Oversized responses lose trailing paragraphs so downstream buffers are not overrun
+		if h.maxResponseBytes > 0 && statusCode == 200 {
+			var err error
+			if content, err = h.fitResponseSize(c, result, content, format, target, includeStats); err != nil {
+				return err
+			}
+		}
+		etag, err := contentETag(c, content, format, target, result.Metadata != nil)
+		if err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderETag, etag)
+	}
+
+	// Voice assistants get the devotional as SSML; errors stay JSON
+	if content, ok := result.Data.(*models.DevotionalContent); ok && statusCode == 200 && format == formatSSML {
+		log.Printf("Request completed with status: %s, code: %d (ssml)", result.Status, statusCode)
+		c.Set(fiber.HeaderContentType, "application/ssml+xml; charset=utf-8")
+		return c.Status(statusCode).SendString(renderSSML(content))
+	}
+
+	// Sites embed the devotional as schema.org JSON-LD for rich results; errors stay JSON
+	if content, ok := result.Data.(*models.DevotionalContent); ok && statusCode == 200 && format == formatJSONLD {
+		body, err := renderJSONLD(content, target)
+		if err != nil {
+			return err
+		}
+		log.Printf("Request completed with status: %s, code: %d (jsonld)", result.Status, statusCode)
+		c.Set(fiber.HeaderContentType, "application/ld+json; charset=utf-8")
+		return c.Status(statusCode).Send(body)
+	}
+
+	// Long devotionals start reaching slow clients before the whole body is encoded
+	if content, ok := result.Data.(*models.DevotionalContent); ok && statusCode == 200 && h.streamThreshold > 0 && paragraphBytes(content) > h.streamThreshold {
+		log.Printf("Request completed with status: %s, code: %d (streamed)", result.Status, statusCode)
+		return sendJSONStream(c, statusCode, result, content)
+	}
+
 	log.Printf("Request completed with status: %s, code: %d", result.Status, statusCode)
 	return c.Status(statusCode).JSON(result)
 }
@@ -151,7 +298,67 @@ func (h *SABDAHandler) HealthCheck(c *fiber.Ctx) error {
 			Service: "SABDA Scraper API",
 		},
 		Metadata: map[string]interface{}{
-			"timestamp": time.Now(),
+			"timestamp": models.Now(),
+		},
+	})
+}
+
+// HealthLive reports that the process is up. It stays 200 during maintenance mode.
+func (h *SABDAHandler) HealthLive(c *fiber.Ctx) error {
+	return c.JSON(models.APIResponse{
+		Status:  "success",
+		Message: "Service is alive",
+		Metadata: map[string]interface{}{
+			"timestamp": models.Now(),
+		},
+	})
+}
+
+// HealthReady reports whether the service should receive traffic. It answers 503 while the
+// extraction self-test finds that SABDA pages no longer yield quality content.
+func (h *SABDAHandler) HealthReady(c *fiber.Ctx) error {
+	selfTest := h.selfTest.Status()
+	if !h.selfTest.Ready() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(models.APIResponse{
+			Status:  "error",
+			Message: "Service is degraded: extraction self-test is failing",
+			Data: map[string]interface{}{
+				"self_test": selfTest,
+			},
+			Metadata: map[string]interface{}{
+				"error_type": "ExtractionDegradedError",
+				"timestamp":  models.Now(),
+			},
+		})
+	}
+
+	return c.JSON(models.APIResponse{
+		Status:  "success",
+		Message: "Service is ready",
+		Data: map[string]interface{}{
+			"self_test": selfTest,
+		},
+		Metadata: map[string]interface{}{
+			"timestamp": models.Now(),
+		},
+	})
+}
+
+// GetVersion reports the running build, Go runtime and uptime
+func (h *SABDAHandler) GetVersion(c *fiber.Ctx) error {
+	return c.JSON(models.APIResponse{
+		Status:  "success",
+		Message: "Version retrieved successfully",
+		Data: map[string]interface{}{
+			"version":        h.build.Version,
+			"commit":         h.build.Commit,
+			"build_time":     h.build.BuildTime,
+			"go_version":     runtime.Version(),
+			"started_at":     models.NewTimestamp(h.startedAt),
+			"uptime_seconds": int64(time.Since(h.startedAt).Seconds()),
+		},
+		Metadata: map[string]interface{}{
+			"timestamp": models.Now(),
 		},
 	})
 }
@@ -162,8 +369,9 @@ func (h *SABDAHandler) Home(c *fiber.Ctx) error {
 		Status:  "success",
 		Message: "API documentation retrieved successfully",
 		Data: map[string]interface{}{
-			"service": "SABDA Scraper API",
-			"version": "2.0.0",
+			"service":  "SABDA Scraper API",
+			"version":  h.build.Version,
+			"commit":   h.build.Commit,
 			"language": "Go",
 			"endpoints": map[string]interface{}{
 				"/api/auth/token": map[string]interface{}{
@@ -174,6 +382,13 @@ func (h *SABDAHandler) Home(c *fiber.Ctx) error {
 					},
 					"example": "POST with {\"api_key\": \"your_api_key\"}",
 				},
+				"/api/auth/tokens": map[string]interface{}{
+					"method":      "POST",
+					"description": "Generate tokens for several API keys at once (requires admin token)",
+					"body": map[string]string{
+						"api_keys": "List of API keys (at most 50)",
+					},
+				},
 				"/api/sabda": map[string]interface{}{
 					"method":      "GET",
 					"description": "Get SABDA devotional content (requires authentication)",
@@ -181,15 +396,142 @@ func (h *SABDAHandler) Home(c *fiber.Ctx) error {
 						"Authorization": "Bearer <token>",
 					},
 					"parameters": map[string]string{
-						"year": "Year (integer, e.g., 2025)",
-						"date": "Date in MMDD format (string, e.g., '0902' for September 2nd)",
+						"year":           "Year (integer, e.g., 2025); date-indexed publications only",
+						"date":           "Date in MMDD format (string, e.g., '0902' for September 2nd); date-indexed publications only",
+						"publication":    "Optional publication code (one of: " + joinStrings(h.scraperService.EnabledPublications(), ", ") + "; default " + scraper.DefaultPublication + ")",
+						"edition":        "Edition number for edition-indexed publications (e.g., ?publication=e-konsel&edition=150)",
+						"date_format":    "Optional; 'mmdd' (default) or 'ddmm' to send the date day-first, e.g. '0209' for September 2nd (reported as interpreted_month/interpreted_day)",
+						"auto_year":      "Optional; with year omitted, 'true' picks the current Jakarta year, or last year when the date has not yet occurred (reported as inferred_year)",
+						"max_paragraphs": "Optional cap on returned paragraphs for previews (integer, e.g., 2)",
+						"include_html":   "Optional; 'true' adds sanitized inline HTML per paragraph in devotional_html",
+						"no_cache":       "Optional; 'true' forces a fresh scrape (admin tokens only)",
+						"max_age":        "Optional; re-scrape when the cached copy is older than this many seconds (non-admin values are raised to the configured minimum)",
+						"strip_refs":     "Optional; 'true' removes parenthesized scripture citations from the paragraphs and lists them in scripture_references",
+						"stats":          "Optional; 'true' adds word_stats, the most frequent words excluding Indonesian stopwords",
+						"meta":           "Optional; 'false' omits the metadata block from successful responses",
+						"head_only":      "Optional; 'true' omits data and summarizes the devotional in metadata.content",
+						"output":         "Optional output format (one of: " + joinStrings(h.enabledFormatList(), ", ") + "); negotiated from Accept when omitted",
+						"lang":           "Optional language of validation errors, 'id' (default) or 'en'; Accept-Language is used when omitted",
 					},
 					"example": "/api/sabda?year=2025&date=0902",
 				},
+				"/api/sabda/range": map[string]interface{}{
+					"method":      "GET",
+					"description": "Get devotional content for a range of dates (requires authentication)",
+					"parameters": map[string]string{
+						"year":   "Year (integer, e.g., 2025)",
+						"start":  "First date in MMDD format (inclusive)",
+						"end":    "Last date in MMDD format (inclusive)",
+						"stream": "Optional; 'true' streams newline-delimited JSON (application/x-ndjson) as each date completes",
+						"toc":    "Optional; 'false' omits the table_of_contents (date, title and scripture reference of each scraped date)",
+					},
+					"example": "/api/sabda/range?year=2025&start=0901&end=0907&stream=true",
+				},
+				"/api/sabda/context": map[string]interface{}{
+					"method":      "GET",
+					"description": "Scripture reference and title of a date and the days before and after it; unavailable days are null (requires authentication)",
+					"parameters": map[string]string{
+						"year": "Year (integer, e.g., 2025)",
+						"date": "Date in MMDD format",
+					},
+					"example": "/api/sabda/context?year=2025&date=0902",
+				},
 				"/api/health": map[string]interface{}{
 					"method":      "GET",
 					"description": "Health check endpoint",
 				},
+				"/api/version": map[string]interface{}{
+					"method":      "GET",
+					"description": "Build version, commit, build time, Go version and uptime",
+				},
+				"/api/health/live": map[string]interface{}{
+					"method":      "GET",
+					"description": "Liveness check; stays up during maintenance mode",
+				},
+				"/api/health/ready": map[string]interface{}{
+					"method":      "GET",
+					"description": "Readiness check; 503 while the extraction self-test finds extraction broken",
+				},
+				"/api/admin/maintenance": map[string]interface{}{
+					"method":      "POST",
+					"description": "Toggle maintenance mode (requires admin token)",
+					"body": map[string]string{
+						"enabled": "true or false",
+						"message": "Optional message shown to clients",
+					},
+				},
+				"/api/diagnostics": map[string]interface{}{
+					"method":      "GET",
+					"description": "Scrape outcome counters, cache state and recent upstream scrapes (requires admin token)",
+				},
+				"/api/stats/corpus": map[string]interface{}{
+					"method":      "GET",
+					"description": "Aggregate stats over cached devotionals: entry count, average word count, top books, quality score distribution (requires authentication)",
+				},
+				"/api/admin/raw": map[string]interface{}{
+					"method":      "GET",
+					"description": "Raw upstream HTML for an issue, bypassing extraction and cache (requires admin token)",
+					"parameters": map[string]string{
+						"year":        "Year (integer, e.g., 2025)",
+						"date":        "Date in MMDD format",
+						"publication": "Optional publication code",
+						"edition":     "Edition number for edition-indexed publications",
+					},
+					"example": "/api/admin/raw?year=2025&date=0902",
+				},
+				"/api/admin/config": map[string]interface{}{
+					"method":      "GET",
+					"description": "Effective runtime configuration with secrets redacted (requires admin token)",
+				},
+				"/api/admin/quality": map[string]interface{}{
+					"method":      "GET",
+					"description": "Per-date extraction quality report for an e-SH date range, without content (requires admin token)",
+					"parameters": map[string]string{
+						"year":  "Year (integer)",
+						"start": "Start date in MMDD format",
+						"end":   "End date in MMDD format (inclusive)",
+					},
+					"example": "/api/admin/quality?year=2025&start=0101&end=0131",
+				},
+				"/api/admin/reload": map[string]interface{}{
+					"method":      "POST",
+					"description": "Re-read the config file and apply API keys and rate limits, listing the changed settings in metadata.applied; 409 if an immutable setting changed (requires admin token)",
+				},
+				"/api/admin/keys/check": map[string]interface{}{
+					"method":      "POST",
+					"description": "Check whether an API key is accepted and report its label and scope, without issuing a token (requires admin token)",
+					"body": map[string]string{
+						"api_key": "API key to check (string)",
+					},
+				},
+				"/api/cache/warm": map[string]interface{}{
+					"method":      "POST",
+					"description": "Scrape an issue or date range into the cache in the background; returns 202 with a job ID (requires admin or warmer token)",
+					"body": map[string]string{
+						"year":        "Year (integer)",
+						"date":        "Date in MMDD format",
+						"start":       "Range start date in MMDD format, instead of date",
+						"end":         "Range end date in MMDD format (inclusive)",
+						"publication": "Optional publication code",
+						"edition":     "Edition number for edition-indexed publications",
+					},
+				},
+				"/api/admin/warm/:id": map[string]interface{}{
+					"method":      "GET",
+					"description": "Progress of a cache warm job: completed, total, failures and ETA (requires admin token)",
+				},
+				"/metrics": map[string]interface{}{
+					"method":      "GET",
+					"description": "Prometheus metrics (requires admin token)",
+				},
+				"/favicon.ico": map[string]interface{}{
+					"method":      "GET",
+					"description": "Favicon from the static directory, or 204 without one",
+				},
+				StaticPrefix + "/*": map[string]interface{}{
+					"method":      "GET",
+					"description": "Static assets, when a static directory is configured",
+				},
 			},
 			"authentication": map[string]interface{}{
 				"type": "JWT Bearer Token",
@@ -201,14 +543,191 @@ func (h *SABDAHandler) Home(c *fiber.Ctx) error {
 			},
 		},
 		Metadata: map[string]interface{}{
-			"timestamp":     time.Now(),
+			"timestamp":     models.Now(),
 			"cors_enabled":  true,
 			"flutter_ready": true,
-			"go_version":    true,
+			"go_version":    runtime.Version(),
 		},
 	})
 }
 
+// requestContext returns a context carrying the request's correlation identifiers for upstream calls
+func requestContext(c *fiber.Ctx) context.Context {
+	requestID, _ := c.Locals("requestid").(string)
+	return scraper.WithCorrelation(c.UserContext(), scraper.Correlation{
+		RequestID:   requestID,
+		TraceParent: c.Get("traceparent"),
+	})
+}
+
+// contentETag derives a strong ETag by hashing content as it is encoded in the response body,
+// so every option that shapes the content, from HTML and truncation to scripture text and the
+// output format, yields its own validator. The metadata block changes with each request and is
+// left out, but whether it is sent at all is part of the tag.
+func contentETag(c *fiber.Ctx, content *models.DevotionalContent, format string, target scraper.Target, withMetadata bool) (string, error) {
+	var body []byte
+	var err error
+	if format == formatJSON {
+		body, err = c.App().Config().JSONEncoder(content)
+	} else {
+		body, err = renderBody(c, nil, content, format, target)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(body)
+	etag := hex.EncodeToString(hash[:16])
+	if withMetadata {
+		etag += "-meta"
+	}
+	return `"` + etag + `"`, nil
+}
+
+// truncateParagraphs returns a copy of content limited to the first max paragraphs. The
+// entries of a combined page and the separated reading and reflection are dropped from
+// truncated copies.
+func truncateParagraphs(content *models.DevotionalContent, max int) *models.DevotionalContent {
+	if len(content.DevotionalContent) <= max {
+		return content
+	}
+
+	truncated := *content
+	truncated.DevotionalContent = content.DevotionalContent[:max]
+	if len(content.DevotionalHTML) > max {
+		truncated.DevotionalHTML = content.DevotionalHTML[:max]
+	}
+	truncated.FullText, truncated.WordCount = scraper.FullText(truncated.DevotionalContent)
+	truncated.ParagraphCount = max
+	truncated.Entries = nil
+	truncated.ScriptureReading, truncated.Reflection = nil, nil
+	if !content.Truncated {
+		truncated.TotalParagraphs = len(content.DevotionalContent)
+	}
+	truncated.Truncated = true
+
+	return &truncated
+}
+
+// fitResponseSize drops trailing paragraphs of content until the response body, encoded in
+// format exactly as it will be sent, is at most maxResponseBytes. result carries the fitted
+// copy, with word stats recomputed and the dropped paragraphs counted in its metadata. Content
+// too large even without paragraphs is sent with none.
+func (h *SABDAHandler) fitResponseSize(c *fiber.Ctx, result *models.APIResponse, content *models.DevotionalContent, format string, target scraper.Target, includeStats bool) (*models.DevotionalContent, error) {
+	full := content
+	for kept := len(full.DevotionalContent); ; kept-- {
+		if kept < len(full.DevotionalContent) {
+			content = truncateParagraphs(full, kept)
+			if includeStats {
+				content = h.withWordStats(content)
+			}
+			result.Data = content
+			if metadata, ok := result.Metadata.(models.ScrapingMetadata); ok {
+				metadata.Truncated = true
+				metadata.DroppedParagraphs = len(full.DevotionalContent) - kept
+				result.Metadata = metadata
+			}
+		}
+		body, err := renderBody(c, result, content, format, target)
+		if err != nil {
+			return nil, err
+		}
+		if len(body) <= h.maxResponseBytes || kept == 0 {
+			return content, nil
+		}
+	}
+}
+
+// renderBody encodes a successful content response as it is sent in format
+func renderBody(c *fiber.Ctx, result *models.APIResponse, content *models.DevotionalContent, format string, target scraper.Target) ([]byte, error) {
+	switch format {
+	case formatSSML:
+		return []byte(renderSSML(content)), nil
+	case formatJSONLD:
+		return renderJSONLD(content, target)
+	default:
+		return c.App().Config().JSONEncoder(result)
+	}
+}
+
+// withWordStats returns a copy of content with the top words of its full text
+func (h *SABDAHandler) withWordStats(content *models.DevotionalContent) *models.DevotionalContent {
+	withStats := *content
+	withStats.WordStats = h.statsService.TopWords(content.FullText)
+	return &withStats
+}
+
+// withoutScriptureReferences returns a copy of content with parenthesized scripture citations
+// removed from the paragraphs and collected, de-duplicated, into ScriptureReferences. With
+// canonical set the collected references are canonicalized first. The full text and word
+// counts are recomputed from the stripped paragraphs.
+func withoutScriptureReferences(content *models.DevotionalContent, canonical bool) *models.DevotionalContent {
+	stripped := *content
+	stripped.DevotionalContent = make([]string, len(content.DevotionalContent))
+	seen := make(map[string]bool)
+	var refs []string
+	for i, paragraph := range content.DevotionalContent {
+		cleaned, found := scraper.StripScriptureReferences(paragraph)
+		stripped.DevotionalContent[i] = cleaned
+		for _, ref := range found {
+			if canonical {
+				ref = scraper.CanonicalReference(ref)
+			}
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+	if len(refs) == 0 {
+		return content
+	}
+
+	stripped.DevotionalHTML = stripReferences(content.DevotionalHTML)
+	stripped.ScriptureReading = stripReferences(content.ScriptureReading)
+	stripped.Reflection = stripReferences(content.Reflection)
+	if content.Entries != nil {
+		stripped.Entries = make([]models.DevotionalEntry, len(content.Entries))
+		for i, entry := range content.Entries {
+			entry.DevotionalContent = stripReferences(entry.DevotionalContent)
+			entry.DevotionalHTML = stripReferences(entry.DevotionalHTML)
+			_, entry.WordCount = scraper.FullText(entry.DevotionalContent)
+			stripped.Entries[i] = entry
+		}
+	}
+	stripped.ScriptureReferences = refs
+	stripped.FullText, stripped.WordCount = scraper.FullText(stripped.DevotionalContent)
+
+	return &stripped
+}
+
+// stripReferences returns paragraphs with their parenthesized scripture citations removed
+func stripReferences(paragraphs []string) []string {
+	if paragraphs == nil {
+		return nil
+	}
+	stripped := make([]string, len(paragraphs))
+	for i, paragraph := range paragraphs {
+		stripped[i], _ = scraper.StripScriptureReferences(paragraph)
+	}
+	return stripped
+}
+
+// withoutHTML returns a copy of content with the per-paragraph HTML removed, including from
+// the entries of a combined page
+func withoutHTML(content *models.DevotionalContent) *models.DevotionalContent {
+	stripped := *content
+	stripped.DevotionalHTML = nil
+	if content.Entries != nil {
+		stripped.Entries = make([]models.DevotionalEntry, len(content.Entries))
+		for i, entry := range content.Entries {
+			entry.DevotionalHTML = nil
+			stripped.Entries[i] = entry
+		}
+	}
+	return &stripped
+}
+
 func joinStrings(strs []string, separator string) string {
 	if len(strs) == 0 {
 		return ""
@@ -216,10 +735,10 @@ func joinStrings(strs []string, separator string) string {
 	if len(strs) == 1 {
 		return strs[0]
 	}
-	
+
 	result := strs[0]
 	for i := 1; i < len(strs); i++ {
 		result += separator + strs[i]
 	}
 	return result
-}
\ No newline at end of file
+}
@@ -0,0 +1,403 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+// testContent returns devotional content whose derived fields are computed like the scraper's
+func testContent(paragraphs ...string) *models.DevotionalContent {
+	content := &models.DevotionalContent{DevotionalContent: paragraphs, ParagraphCount: len(paragraphs)}
+	content.FullText, content.WordCount = scraper.FullText(paragraphs)
+	return content
+}
+
+// assertDerivedText fails t unless content's full text and word count describe its paragraphs
+func assertDerivedText(t *testing.T, content *models.DevotionalContent) {
+	t.Helper()
+	if want := strings.Join(content.DevotionalContent, " "); content.FullText != want {
+		t.Errorf("FullText = %q, want %q", content.FullText, want)
+	}
+	if want := len(strings.Fields(content.FullText)); content.WordCount != want {
+		t.Errorf("WordCount = %d, want %d", content.WordCount, want)
+	}
+}
+
+func TestTruncateParagraphsKeepsDerivedTextConsistent(t *testing.T) {
+	content := testContent("Satu dua tiga.", "Empat lima.", "Enam.")
+
+	truncated := truncateParagraphs(content, 2)
+	if got := len(truncated.DevotionalContent); got != 2 {
+		t.Fatalf("paragraphs = %d, want 2", got)
+	}
+	if !truncated.Truncated || truncated.TotalParagraphs != 3 {
+		t.Errorf("Truncated = %v, TotalParagraphs = %d, want true, 3", truncated.Truncated, truncated.TotalParagraphs)
+	}
+	assertDerivedText(t, truncated)
+	assertDerivedText(t, truncateParagraphs(content, 5))
+	if content.WordCount != 6 {
+		t.Errorf("original WordCount = %d, want 6", content.WordCount)
+	}
+}
+
+func TestWithoutScriptureReferencesRecomputesDerivedText(t *testing.T) {
+	content := testContent("Allah mengasihi dunia (Yohanes 3:16) dengan sempurna.", "Tanpa rujukan di sini.")
+	content.Entries = []models.DevotionalEntry{{DevotionalContent: content.DevotionalContent, WordCount: content.WordCount}}
+
+	stripped := withoutScriptureReferences(content, false)
+	if len(stripped.ScriptureReferences) != 1 {
+		t.Fatalf("ScriptureReferences = %v, want one reference", stripped.ScriptureReferences)
+	}
+	if strings.Contains(stripped.FullText, "3:16") {
+		t.Errorf("FullText still cites the reference: %q", stripped.FullText)
+	}
+	assertDerivedText(t, stripped)
+	if entry := stripped.Entries[0]; entry.WordCount != stripped.WordCount {
+		t.Errorf("entry WordCount = %d, want %d", entry.WordCount, stripped.WordCount)
+	}
+	if content.WordCount == stripped.WordCount {
+		t.Errorf("WordCount unchanged at %d after stripping", content.WordCount)
+	}
+}
+
+func TestFitResponseSizeMeasuresEncodedBody(t *testing.T) {
+	paragraphs := make([]string, 10)
+	for i := range paragraphs {
+		paragraphs[i] = strings.Repeat("firman ", 20)
+	}
+	h := &SABDAHandler{maxResponseBytes: 900, statsService: services.NewStatsService(3, nil)}
+
+	var body []byte
+	var fitted *models.DevotionalContent
+	var metadata models.ScrapingMetadata
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		content := h.withWordStats(testContent(paragraphs...))
+		result := &models.APIResponse{Status: "success", Data: content, Metadata: models.ScrapingMetadata{URL: "https://www.sabda.org/"}}
+		var err error
+		if fitted, err = h.fitResponseSize(c, result, content, formatJSON, scraper.Target{}, true); err != nil {
+			return err
+		}
+		metadata = result.Metadata.(models.ScrapingMetadata)
+		body, err = c.App().Config().JSONEncoder(result)
+		return err
+	})
+	if _, err := app.Test(httptest.NewRequest("GET", "/", nil)); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	if len(body) > h.maxResponseBytes {
+		t.Errorf("body is %d bytes, want at most %d", len(body), h.maxResponseBytes)
+	}
+	if len(fitted.DevotionalContent) == 0 || len(fitted.DevotionalContent) == len(paragraphs) {
+		t.Fatalf("kept %d of %d paragraphs, want some dropped", len(fitted.DevotionalContent), len(paragraphs))
+	}
+	if !metadata.Truncated || metadata.DroppedParagraphs != len(paragraphs)-len(fitted.DevotionalContent) {
+		t.Errorf("metadata Truncated = %v, DroppedParagraphs = %d", metadata.Truncated, metadata.DroppedParagraphs)
+	}
+	assertDerivedText(t, fitted)
+	if got := fitted.WordStats[0].Count; got != fitted.WordCount {
+		t.Errorf("word stats count %d for the only word, want %d", got, fitted.WordCount)
+	}
+}
+
+// inHandler runs fn with the context of a request to a fresh app
+func inHandler(t *testing.T, fn func(c *fiber.Ctx) error) {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/", fn)
+	if _, err := app.Test(httptest.NewRequest("GET", "/", nil)); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+}
+
+func TestContentETagCoversEveryRepresentationOption(t *testing.T) {
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	base := testContent("Allah mengasihi dunia.", "Kasih-Nya kekal.")
+	withScripture := *base
+	withScripture.ScriptureText = "Karena begitu besar kasih Allah akan dunia ini"
+	withHTML := *base
+	withHTML.DevotionalHTML = []string{"<p>Allah mengasihi dunia.</p>", "<p>Kasih-Nya kekal.</p>"}
+
+	tags := make(map[string]string)
+	inHandler(t, func(c *fiber.Ctx) error {
+		variants := []struct {
+			name     string
+			content  *models.DevotionalContent
+			format   string
+			withMeta bool
+		}{
+			{"json", base, formatJSON, true},
+			{"meta=false", base, formatJSON, false},
+			{"scripture text", &withScripture, formatJSON, true},
+			{"html", &withHTML, formatJSON, true},
+			{"truncated", truncateParagraphs(base, 1), formatJSON, true},
+			{"ssml", base, formatSSML, true},
+			{"jsonld", base, formatJSONLD, true},
+		}
+		for _, variant := range variants {
+			etag, err := contentETag(c, variant.content, variant.format, target, variant.withMeta)
+			if err != nil {
+				return err
+			}
+			for name, other := range tags {
+				if other == etag {
+					t.Errorf("%s and %s share ETag %s", variant.name, name, etag)
+				}
+			}
+			tags[variant.name] = etag
+		}
+
+		again, err := contentETag(c, testContent("Allah mengasihi dunia.", "Kasih-Nya kekal."), formatJSON, target, true)
+		if err != nil {
+			return err
+		}
+		if again != tags["json"] {
+			t.Errorf("ETag of identical content = %s, want %s", again, tags["json"])
+		}
+		return nil
+	})
+}
+
+func TestGetContentCacheControl(t *testing.T) {
+	cache := services.NewCacheService(time.Hour, 10*time.Minute, 10, 0, 0, 0, 0, false)
+	scraperService := services.NewScraperService(false, models.ScraperConfig{}, cache, nil, nil, nil)
+	published := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	unpublished := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0903"}
+	cache.Set(published.CacheKey(), *testContent("Allah mengasihi dunia."), time.Now().Add(-10*time.Minute))
+	cache.SetNegative(unpublished.CacheKey())
+
+	h := NewSABDAHandler(scraperService, services.NewStatsService(3, nil), nil, models.ServerConfig{IncludeMetadata: true}, models.ScraperConfig{InteractiveTimeout: time.Second}, models.BuildInfo{})
+	app := fiber.New()
+	app.Get("/api/sabda", h.GetContent)
+
+	tests := []struct {
+		name, query  string
+		status       int
+		cacheControl string
+	}{
+		{"cached content", "year=2025&date=0902", 200, "public, max-age=3000"},
+		{"unpublished", "year=2025&date=0903", 404, "public, max-age=600"},
+		{"invalid", "year=2025&date=1399", 400, "no-store"},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/sabda?"+tt.query, nil))
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tt.name, err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, resp.StatusCode, tt.status)
+		}
+		got := resp.Header.Get(fiber.HeaderCacheControl)
+		// The remaining TTL may tick down by a second while the request runs
+		if got != tt.cacheControl && !(tt.status == 200 && got == "public, max-age=2999") {
+			t.Errorf("%s: Cache-Control = %q, want %q", tt.name, got, tt.cacheControl)
+		}
+		if etag := resp.Header.Get(fiber.HeaderETag); (etag != "") != (tt.status == 200) {
+			t.Errorf("%s: ETag = %q", tt.name, etag)
+		}
+	}
+}
+
+func TestGetContentOmitsMetadata(t *testing.T) {
+	cache := services.NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+	scraperService := services.NewScraperService(false, models.ScraperConfig{}, cache, nil, nil, nil)
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	cache.Set(target.CacheKey(), *testContent("Allah mengasihi dunia."), time.Now())
+
+	tests := []struct {
+		name            string
+		includeMetadata bool
+		query           string
+		want            bool
+	}{
+		{"default on", true, "", true},
+		{"suppressed", true, "&meta=false", false},
+		{"default off", false, "", false},
+		{"requested", false, "&meta=true", true},
+	}
+	for _, tt := range tests {
+		h := NewSABDAHandler(scraperService, services.NewStatsService(3, nil), nil, models.ServerConfig{IncludeMetadata: tt.includeMetadata}, models.ScraperConfig{InteractiveTimeout: time.Second}, models.BuildInfo{})
+		app := fiber.New()
+		app.Get("/api/sabda", h.GetContent)
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/sabda?year=2025&date=0902"+tt.query, nil))
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tt.name, err)
+		}
+		var body map[string]json.RawMessage
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: decode failed: %v", tt.name, err)
+		}
+		if _, ok := body["metadata"]; ok != tt.want {
+			t.Errorf("%s: metadata present = %v, want %v", tt.name, ok, tt.want)
+		}
+		if _, ok := body["data"]; !ok {
+			t.Errorf("%s: data missing", tt.name)
+		}
+	}
+}
+
+func TestGetContentPadsThreeDigitDate(t *testing.T) {
+	cache := services.NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+	scraperService := services.NewScraperService(false, models.ScraperConfig{}, cache, nil, nil, nil)
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	cache.Set(target.CacheKey(), *testContent("Allah mengasihi dunia."), time.Now())
+
+	h := NewSABDAHandler(scraperService, services.NewStatsService(3, nil), nil, models.ServerConfig{IncludeMetadata: true}, models.ScraperConfig{InteractiveTimeout: time.Second}, models.BuildInfo{})
+	app := fiber.New()
+	app.Get("/api/sabda", h.GetContent)
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/sabda?year=2025&date=902", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("status %d, want 200 from the cached 0902 entry", resp.StatusCode)
+	}
+}
+
+func TestGetContentDateFormats(t *testing.T) {
+	cache := services.NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+	scraperService := services.NewScraperService(false, models.ScraperConfig{}, cache, nil, nil, nil)
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	cache.Set(target.CacheKey(), *testContent("Allah mengasihi dunia."), time.Now())
+
+	h := NewSABDAHandler(scraperService, services.NewStatsService(3, nil), nil, models.ServerConfig{IncludeMetadata: true}, models.ScraperConfig{InteractiveTimeout: time.Second}, models.BuildInfo{})
+	app := fiber.New()
+	app.Get("/api/sabda", h.GetContent)
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		echo   bool
+	}{
+		{"default mmdd", "date=0902", 200, false},
+		{"explicit mmdd", "date=0902&date_format=mmdd", 200, true},
+		{"day-first", "date=0209&date_format=ddmm", 200, true},
+		{"unknown format", "date=0902&date_format=yyyy", 400, false},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/sabda?year=2025&"+tt.query, nil))
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tt.name, err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, resp.StatusCode, tt.status)
+			continue
+		}
+		if tt.status != 200 {
+			continue
+		}
+		var body struct {
+			Metadata models.ScrapingMetadata `json:"metadata"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: decode failed: %v", tt.name, err)
+		}
+		if tt.echo && (body.Metadata.InterpretedMonth != 9 || body.Metadata.InterpretedDay != 2) {
+			t.Errorf("%s: interpreted %d/%d, want month 9 day 2", tt.name, body.Metadata.InterpretedMonth, body.Metadata.InterpretedDay)
+		}
+		if !tt.echo && body.Metadata.DateFormat != "" {
+			t.Errorf("%s: date_format = %q, want it omitted", tt.name, body.Metadata.DateFormat)
+		}
+	}
+}
+
+// statusStub answers every upstream request with status and body
+type statusStub struct {
+	status int
+	body   string
+}
+
+func (s statusStub) RoundTrip(r *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: s.status, Header: http.Header{"Content-Type": {"text/html"}}, Body: io.NopCloser(strings.NewReader(s.body)), Request: r}, nil
+}
+
+func TestGetContentMapsFailureReasons(t *testing.T) {
+	tests := []struct {
+		name   string
+		stub   statusStub
+		status int
+		reason string
+	}{
+		{"unpublished", statusStub{404, "not found"}, 404, services.ReasonNotPublished},
+		{"no content container", statusStub{200, "<html><body><nav>Beranda</nav></body></html>"}, 502, services.ReasonParseFailure},
+		{"upstream down", statusStub{500, "internal error"}, 503, services.ReasonUpstreamError},
+	}
+	for _, tt := range tests {
+		previous := http.DefaultTransport
+		http.DefaultTransport = tt.stub
+		cfg := models.ScraperConfig{DisablePrintFallback: true, InteractiveTimeout: time.Minute}
+		cache := services.NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+		scraperService := services.NewScraperService(false, cfg, cache, nil, nil, nil)
+		http.DefaultTransport = previous
+
+		h := NewSABDAHandler(scraperService, services.NewStatsService(3, nil), nil, models.ServerConfig{IncludeMetadata: true}, cfg, models.BuildInfo{})
+		app := fiber.New()
+		app.Get("/api/sabda", h.GetContent)
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/sabda?year=2025&date=0902", nil), -1)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tt.name, err)
+		}
+		var body struct {
+			Metadata map[string]interface{} `json:"metadata"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: decode failed: %v", tt.name, err)
+		}
+		if resp.StatusCode != tt.status || body.Metadata["reason"] != tt.reason {
+			t.Errorf("%s: status %d reason %v, want %d %s", tt.name, resp.StatusCode, body.Metadata["reason"], tt.status, tt.reason)
+		}
+	}
+}
+
+func TestGetContentHeadOnlyOmitsData(t *testing.T) {
+	cache := services.NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+	scraperService := services.NewScraperService(false, models.ScraperConfig{}, cache, nil, nil, nil)
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	content := testContent("Allah mengasihi dunia.", "Tuhan, ajarlah kami setia. Amin.")
+	content.ScriptureReference = "Yohanes 3:16"
+	content.ExtractionMethod = "default/direct"
+	cache.Set(target.CacheKey(), *content, time.Now())
+
+	h := NewSABDAHandler(scraperService, services.NewStatsService(3, nil), nil, models.ServerConfig{IncludeMetadata: true}, models.ScraperConfig{InteractiveTimeout: time.Second}, models.BuildInfo{})
+	app := fiber.New()
+	app.Get("/api/sabda", h.GetContent)
+
+	for _, query := range []string{"&head_only=true", "&head_only=true&meta=false", "&head_only=true&output=jsonld"} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/sabda?year=2025&date=0902"+query, nil))
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", query, err)
+		}
+		var body struct {
+			Data     json.RawMessage `json:"data"`
+			Metadata struct {
+				Content *models.ContentSummary `json:"content"`
+			} `json:"metadata"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: decode failed: %v", query, err)
+		}
+		if resp.StatusCode != 200 || len(body.Data) != 0 {
+			t.Errorf("%s: status %d with data %s, want 200 without data", query, resp.StatusCode, body.Data)
+		}
+		summary := body.Metadata.Content
+		if summary == nil {
+			t.Errorf("%s: metadata.content missing", query)
+			continue
+		}
+		if summary.WordCount != content.WordCount || summary.ParagraphCount != 2 || !summary.HasScriptureReference || summary.ExtractionMethod != "default/direct" {
+			t.Errorf("%s: content summary = %+v", query, *summary)
+		}
+	}
+}
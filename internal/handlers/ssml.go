@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+const formatSSML = "ssml"
+
+// SSML pauses between the sections of a devotional read aloud
+const (
+	ssmlSectionBreak   = `<break time="1s"/>`
+	ssmlParagraphBreak = `<break time="500ms"/>`
+)
+
+// renderSSML renders content as an SSML document for voice assistants: the title with emphasis,
+// then the scripture reference and text, then the paragraphs, separated by pauses
+func renderSSML(content *models.DevotionalContent) string {
+	var b strings.Builder
+	b.WriteString("<speak>")
+
+	if content.DevotionalTitle != "" {
+		b.WriteString(`<p><emphasis level="strong">`)
+		writeSSMLText(&b, content.DevotionalTitle)
+		b.WriteString("</emphasis></p>")
+		b.WriteString(ssmlSectionBreak)
+	}
+
+	if content.ScriptureReference != "" || content.ScriptureText != "" {
+		if content.ScriptureReference != "" {
+			b.WriteString("<p>")
+			writeSSMLText(&b, content.ScriptureReference)
+			b.WriteString("</p>")
+		}
+		if content.ScriptureText != "" {
+			b.WriteString("<p>")
+			writeSSMLText(&b, content.ScriptureText)
+			b.WriteString("</p>")
+		}
+		b.WriteString(ssmlSectionBreak)
+	}
+
+	for i, paragraph := range content.DevotionalContent {
+		if i > 0 {
+			b.WriteString(ssmlParagraphBreak)
+		}
+		b.WriteString("<p>")
+		writeSSMLText(&b, paragraph)
+		b.WriteString("</p>")
+	}
+
+	b.WriteString("</speak>")
+	return b.String()
+}
+
+// writeSSMLText writes scraped text with XML special characters escaped
+func writeSSMLText(b *strings.Builder, text string) {
+	xml.EscapeText(b, []byte(text))
+}
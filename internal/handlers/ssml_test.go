@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+func TestRenderSSMLIsWellFormed(t *testing.T) {
+	content := testContent(`Allah & "dunia" <selalu> bekerja.`, "Tuhan, ajarlah kami setia. Amin.")
+	content.DevotionalTitle = "Hal Kecil & Besar"
+	content.ScriptureReference = "Lukas 13:18-21"
+	content.ScriptureText = "Kerajaan Allah seumpama biji sesawi <kecil>."
+
+	ssml := renderSSML(content)
+	decoder := xml.NewDecoder(strings.NewReader(ssml))
+	var elements, breaks []string
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("SSML is not well-formed: %v\n%s", err, ssml)
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			elements = append(elements, token.Name.Local)
+			if token.Name.Local == "break" {
+				breaks = append(breaks, token.Attr[0].Value)
+			}
+		case xml.CharData:
+			text.Write(token)
+		}
+	}
+
+	if elements[0] != "speak" || elements[1] != "p" || elements[2] != "emphasis" {
+		t.Errorf("elements = %v, want speak, then the title in p and emphasis", elements)
+	}
+	if want := []string{"1s", "1s", "500ms"}; strings.Join(breaks, ",") != strings.Join(want, ",") {
+		t.Errorf("breaks = %v, want %v", breaks, want)
+	}
+	for _, want := range []string{"Hal Kecil & Besar", "Lukas 13:18-21", "biji sesawi <kecil>.", `Allah & "dunia" <selalu> bekerja.`} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("spoken text %q lacks %q", text.String(), want)
+		}
+	}
+}
+
+func TestRenderSSMLSkipsMissingSections(t *testing.T) {
+	ssml := renderSSML(&models.DevotionalContent{DevotionalContent: []string{"Satu."}})
+	if want := "<speak><p>Satu.</p></speak>"; ssml != want {
+		t.Errorf("renderSSML() = %s, want %s", ssml, want)
+	}
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// StaticPrefix is where the static assets directory is mounted, apart from the API routes
+const StaticPrefix = "/static"
+
+// faviconMaxAge is how long browsers may keep the favicon, or the lack of one, before asking again
+const faviconMaxAge = "public, max-age=86400"
+
+// Favicon serves favicon.ico from staticDir, or answers 204 No Content when no static
+// directory is configured or it has no favicon, so browsers stop logging 404s for it
+func Favicon(staticDir string) fiber.Handler {
+	favicon := ""
+	if staticDir != "" {
+		path := filepath.Join(staticDir, "favicon.ico")
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			favicon = path
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderCacheControl, faviconMaxAge)
+		if favicon == "" {
+			return c.SendStatus(fiber.StatusNoContent)
+		}
+		return c.SendFile(favicon)
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+// streamedField is the devotional_content key as the JSON encoder writes it with no paragraphs
+var streamedField = []byte(`"devotional_content":null`)
+
+// paragraphBytes estimates the serialized size of content's paragraphs, which dominate long
+// devotionals, without encoding them
+func paragraphBytes(content *models.DevotionalContent) int {
+	size := len(content.FullText)
+	for _, paragraph := range content.DevotionalContent {
+		size += len(paragraph)
+	}
+	for _, paragraph := range content.DevotionalHTML {
+		size += len(paragraph)
+	}
+	return size
+}
+
+// sendJSONStream writes result with chunked transfer encoding: the envelope up to the
+// devotional_content array goes out first, then each paragraph is flushed as it is encoded.
+// The body is byte-for-byte what c.JSON would write for the same result.
+func sendJSONStream(c *fiber.Ctx, statusCode int, result *models.APIResponse, content *models.DevotionalContent) error {
+	encode := c.App().Config().JSONEncoder
+
+	data := *content
+	data.DevotionalContent = nil
+	envelope := *result
+	envelope.Data = &data
+	body, err := encode(envelope)
+	if err != nil {
+		return err
+	}
+	split := bytes.Index(body, streamedField)
+	if split < 0 {
+		return c.Status(statusCode).JSON(result)
+	}
+	head := body[:split+len(streamedField)-len("null")]
+	tail := body[split+len(streamedField):]
+
+	paragraphs := content.DevotionalContent
+	c.Status(statusCode).Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		w.Write(head)
+		if paragraphs == nil {
+			w.WriteString("null")
+		} else {
+			w.WriteByte('[')
+			for i, paragraph := range paragraphs {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				encoded, err := encode(paragraph)
+				if err != nil {
+					log.Printf("Streaming response aborted: %v", err)
+					return
+				}
+				w.Write(encoded)
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+			w.WriteByte(']')
+		}
+		w.Write(tail)
+		w.Flush()
+	})
+	return nil
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+func TestLargeContentIsStreamedChunked(t *testing.T) {
+	cache := services.NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+	scraperService := services.NewScraperService(false, models.ScraperConfig{}, cache, nil, nil, nil)
+	paragraphs := make([]string, 200)
+	for i := range paragraphs {
+		paragraphs[i] = strings.Repeat("Allah bekerja melalui hal kecil. ", 20)
+	}
+	large := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	cache.Set(large.CacheKey(), *testContent(paragraphs...), time.Now())
+	small := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0903"}
+	cache.Set(small.CacheKey(), *testContent("Allah mengasihi dunia."), time.Now())
+
+	get := func(threshold int, date string) ([]byte, []string) {
+		t.Helper()
+		h := NewSABDAHandler(scraperService, services.NewStatsService(3, nil), nil, models.ServerConfig{StreamThresholdBytes: threshold}, models.ScraperConfig{InteractiveTimeout: time.Second}, models.BuildInfo{})
+		app := fiber.New()
+		app.Get("/api/sabda", h.GetContent)
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/sabda?year=2025&date="+date, nil))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		if resp.StatusCode != 200 {
+			t.Fatalf("status %d, want 200", resp.StatusCode)
+		}
+		return body, resp.TransferEncoding
+	}
+
+	streamed, encoding := get(10000, "0902")
+	if len(encoding) == 0 || encoding[0] != "chunked" {
+		t.Errorf("large response Transfer-Encoding = %v, want chunked", encoding)
+	}
+	buffered, encoding := get(0, "0902")
+	if len(encoding) != 0 {
+		t.Errorf("unstreamed response Transfer-Encoding = %v, want none", encoding)
+	}
+	if !bytes.Equal(streamed, buffered) {
+		t.Errorf("streamed body (%d bytes) differs from the c.JSON body (%d bytes)", len(streamed), len(buffered))
+	}
+	if _, encoding := get(10000, "0903"); len(encoding) != 0 {
+		t.Errorf("small response Transfer-Encoding = %v, want the normal path", encoding)
+	}
+}
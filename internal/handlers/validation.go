@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+// fieldError is a validation problem as a message code and the arguments of its template
+type fieldError struct {
+	code messageCode
+	args []interface{}
+}
+
+// validationErrors collects validation problems keyed by request field
+type validationErrors map[string]fieldError
+
+// validationError returns the validation errors of a single field
+func validationError(field string, code messageCode, args ...interface{}) validationErrors {
+	return validationErrors{field: {code: code, args: args}}
+}
+
+// add records a problem for field, keeping the first problem reported for each field
+func (v validationErrors) add(field string, code messageCode, args ...interface{}) {
+	if _, exists := v[field]; !exists {
+		v[field] = fieldError{code: code, args: args}
+	}
+}
+
+// response builds the ValidationError envelope in lang with a readable summary, the
+// per-field messages and their codes
+func (v validationErrors) response(lang string) models.APIResponse {
+	fields := make([]string, 0, len(v))
+	for field := range v {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	summary := make([]string, 0, len(fields))
+	messages := make(map[string]string, len(fields))
+	codes := make(map[string]messageCode, len(fields))
+	for _, field := range fields {
+		problem := v[field]
+		messages[field] = localize(lang, problem.code, problem.args...)
+		codes[field] = problem.code
+		summary = append(summary, field+": "+messages[field])
+	}
+
+	return models.APIResponse{
+		Status:  "error",
+		Message: localize(lang, msgValidationFailed, joinStrings(summary, "; ")),
+		Errors:  messages,
+		Metadata: map[string]interface{}{
+			"error_type":  "ValidationError",
+			"error_codes": codes,
+		},
+	}
+}
+
+// send writes the 400 ValidationError response in the request's language
+func (v validationErrors) send(c *fiber.Ctx) error {
+	lang := requestLanguage(c)
+	c.Set(fiber.HeaderContentLanguage, lang)
+	return c.Status(400).JSON(v.response(lang))
+}
+
+// parseTarget resolves the publication, year, date and edition query parameters into a scrape
+// target, addressing the issue by the publication's indexing scheme. Only publications listed
+// in enabled are accepted.
+func parseTarget(c *fiber.Ctx, errs validationErrors, enabled []string) scraper.Target {
+	date := dateToMMDD(errs, c.Query("date_format"), c.Query("date"))
+	yearStr := c.Query("year")
+	if inferYear(c) {
+		if date, ok := scraper.NormalizeDate(date); ok {
+			yearStr = strconv.Itoa(scraper.InferYear(date, time.Now().In(scraper.Jakarta)))
+		}
+	}
+	return buildTarget(errs, enabled, c.Query("publication"), yearStr, date, c.Query("edition"))
+}
+
+// Date formats accepted by the date_format parameter
+const (
+	dateFormatMMDD = "mmdd"
+	dateFormatDDMM = "ddmm"
+)
+
+// dateToMMDD converts date from the client's declared date_format to the canonical MMDD.
+// The format is never guessed: day-first input must be requested explicitly with ddmm.
+// Malformed dates are returned unchanged for validateMMDD to report.
+func dateToMMDD(errs validationErrors, format, date string) string {
+	switch strings.ToLower(format) {
+	case "", dateFormatMMDD:
+		return date
+	case dateFormatDDMM:
+		ddmm, ok := scraper.NormalizeDate(date)
+		if !ok {
+			return date
+		}
+		return ddmm[2:] + ddmm[:2]
+	default:
+		errs.add("date_format", msgInvalidDateFormat, dateFormatMMDD, dateFormatDDMM)
+		return date
+	}
+}
+
+// inferYear reports whether the year is omitted and the client opted in with auto_year=true,
+// so a date that has not yet occurred in Jakarta this year resolves to last year
+func inferYear(c *fiber.Ctx) bool {
+	return c.Query("year") == "" && c.QueryBool("auto_year")
+}
+
+// buildTarget validates raw target fields from a query string or request body
+func buildTarget(errs validationErrors, enabled []string, publicationCode, yearStr, date, edition string) scraper.Target {
+	if publicationCode == "" {
+		publicationCode = scraper.DefaultPublication
+	}
+	publicationCode = strings.ToLower(publicationCode)
+	target := scraper.Target{Publication: publicationCode}
+
+	publication, ok := scraper.LookupPublication(publicationCode)
+	if !ok {
+		errs.add("publication", msgUnknownPublication, joinStrings(enabled, ", "))
+		return target
+	}
+	if !validatePublicationEnabled(errs, enabled, publication.Code) {
+		return target
+	}
+
+	if publication.Indexing == scraper.IndexByEdition {
+		validateEdition(errs, publication, edition)
+		target.Edition = edition
+		return target
+	}
+
+	if edition != "" {
+		errs.add("edition", msgEditionUnsupported, publication.Code)
+	}
+	target.Year = validateYear(errs, "year", yearStr)
+	target.Date = validateMMDD(errs, "date", date)
+	return target
+}
+
+// validatePublicationEnabled checks that the deployment serves publication code
+func validatePublicationEnabled(errs validationErrors, enabled []string, code string) bool {
+	for _, allowed := range enabled {
+		if allowed == code {
+			return true
+		}
+	}
+	if len(enabled) == 0 {
+		errs.add("publication", msgNoPublicationsEnabled, code)
+	} else {
+		errs.add("publication", msgPublicationDisabled, code, joinStrings(enabled, ", "))
+	}
+	return false
+}
+
+// validateYear checks a required year parameter within the supported range
+func validateYear(errs validationErrors, field, yearStr string) int {
+	if yearStr == "" {
+		errs.add(field, msgYearRequired)
+		return 0
+	}
+
+	year, err := strconv.Atoi(yearStr)
+	if err != nil {
+		errs.add(field, msgNotInteger)
+		return 0
+	}
+
+	currentYear := time.Now().Year()
+	if year < 2000 || year > currentYear+1 {
+		errs.add(field, msgYearOutOfRange, currentYear+1)
+		return 0
+	}
+
+	return year
+}
+
+// validateMMDD checks a required date parameter in MMDD format (month 01-12, day 01-31)
+// and returns it zero-padded to four digits
+func validateMMDD(errs validationErrors, field, date string) string {
+	if date == "" {
+		errs.add(field, msgDateRequired)
+		return date
+	}
+
+	date, ok := scraper.NormalizeDate(date)
+	if !ok {
+		errs.add(field, msgDateMalformed)
+		return date
+	}
+
+	month, _ := strconv.Atoi(date[:2])
+	day, _ := strconv.Atoi(date[2:])
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		errs.add(field, msgDateOutOfRange)
+	}
+	return date
+}
+
+// validateEdition checks the edition parameter of an edition-indexed publication
+func validateEdition(errs validationErrors, publication scraper.Publication, edition string) {
+	if edition == "" {
+		errs.add("edition", msgEditionRequired, publication.Code, publication.Code)
+		return
+	}
+	if !publication.ValidEdition(edition) {
+		errs.add("edition", msgEditionInvalid, publication.Code, publication.EditionPattern.String())
+	}
+}
+
+// validatePositiveInt checks an optional positive integer parameter, returning 0 when absent
+func validatePositiveInt(errs validationErrors, field, value string) int {
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		errs.add(field, msgNotPositiveInteger)
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/internal/services"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+func TestValidationErrorsAreKeyedByField(t *testing.T) {
+	cache := services.NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+	scraperService := services.NewScraperService(false, models.ScraperConfig{}, cache, nil, nil, nil)
+	sabda := NewSABDAHandler(scraperService, services.NewStatsService(3, nil), nil, models.ServerConfig{}, models.ScraperConfig{}, models.BuildInfo{})
+	auth := services.NewAuthService("secret", time.Hour, map[string]string{"flutter": "client-key"}, map[string]string{}, nil)
+	limiter := services.NewRateLimitService(1000, 0, time.Minute, 0, 0)
+	authHandler := NewAuthHandler(auth, limiter, limiter, nil)
+
+	app := fiber.New()
+	app.Get("/api/sabda", sabda.GetContent)
+	app.Post("/api/auth/token", authHandler.GetToken)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		fields []string
+	}{
+		{"content", "GET", "/api/sabda?date=1399&max_paragraphs=-1", "", []string{"date", "max_paragraphs", "year"}},
+		{"token", "POST", "/api/auth/token", `{"api_key": ""}`, []string{"api_key"}},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tt.name, err)
+		}
+		if resp.StatusCode != fiber.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", tt.name, resp.StatusCode)
+		}
+		var body struct {
+			Status   string                 `json:"status"`
+			Message  string                 `json:"message"`
+			Errors   map[string]string      `json:"errors"`
+			Metadata map[string]interface{} `json:"metadata"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: decode failed: %v", tt.name, err)
+		}
+		if body.Status != "error" || body.Metadata["error_type"] != "ValidationError" {
+			t.Errorf("%s: status %q, error_type %v; want a ValidationError", tt.name, body.Status, body.Metadata["error_type"])
+		}
+		codes, _ := body.Metadata["error_codes"].(map[string]interface{})
+		if len(body.Errors) != len(tt.fields) || len(codes) != len(tt.fields) {
+			t.Errorf("%s: errors %v, codes %v; want exactly %v", tt.name, body.Errors, codes, tt.fields)
+		}
+		for _, field := range tt.fields {
+			if body.Errors[field] == "" || codes[field] == nil {
+				t.Errorf("%s: no message or code for %s in %v", tt.name, field, body.Errors)
+			}
+			if !strings.Contains(body.Message, field+": ") {
+				t.Errorf("%s: summary %q does not mention %s", tt.name, body.Message, field)
+			}
+		}
+	}
+}
+
+func TestDisabledPublicationIsRejected(t *testing.T) {
+	cache := services.NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+	tests := []struct {
+		name    string
+		enabled []string
+		status  int
+	}{
+		{"disabled", []string{"e-sh"}, fiber.StatusBadRequest},
+		{"all by default", nil, fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		scraperService := services.NewScraperService(false, models.ScraperConfig{EnabledPublications: tt.enabled}, cache, nil, nil, nil)
+		target := scraper.Target{Publication: "e-konsel", Edition: "150"}
+		cache.Set(target.CacheKey(), *testContent("Konseling Kristen bagi keluarga."), time.Now())
+		h := NewSABDAHandler(scraperService, services.NewStatsService(3, nil), nil, models.ServerConfig{}, models.ScraperConfig{InteractiveTimeout: time.Second}, models.BuildInfo{})
+		app := fiber.New()
+		app.Get("/api/sabda", h.GetContent)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/sabda?publication=e-konsel&edition=150", nil))
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tt.name, err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, resp.StatusCode, tt.status)
+		}
+		if tt.status != fiber.StatusBadRequest {
+			continue
+		}
+		var body struct {
+			Errors   map[string]string      `json:"errors"`
+			Metadata map[string]interface{} `json:"metadata"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: decode failed: %v", tt.name, err)
+		}
+		codes, _ := body.Metadata["error_codes"].(map[string]interface{})
+		if codes["publication"] != string(msgPublicationDisabled) || !strings.Contains(body.Errors["publication"], "e-sh") {
+			t.Errorf("%s: publication error %q (%v), want publication_disabled listing e-sh", tt.name, body.Errors["publication"], codes["publication"])
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Vary adds headers to the response's Vary header, so shared caches and CDNs keep a separate
+// copy for each value of the request headers that select the representation
+func Vary(headers ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Vary(headers...)
+		return c.Next()
+	}
+}
+
+// ContentVary lists the request headers that the /api/sabda content endpoints depend on:
+// Accept selects the output format or the NDJSON range stream, Accept-Language the language
+// of error messages, and Accept-Encoding any compression applied on the way out. The bearer
+// token decides which options (no_cache, small max_age) are honored, so Authorization is
+// listed unless varyAuthorization is off.
+func ContentVary(varyAuthorization bool) []string {
+	headers := []string{fiber.HeaderAccept, fiber.HeaderAcceptLanguage, fiber.HeaderAcceptEncoding}
+	if varyAuthorization {
+		headers = append(headers, fiber.HeaderAuthorization)
+	}
+	return headers
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestContentVary(t *testing.T) {
+	tests := []struct {
+		varyAuthorization bool
+		want              string
+	}{
+		{true, "Accept, Accept-Language, Accept-Encoding, Authorization"},
+		{false, "Accept, Accept-Language, Accept-Encoding"},
+	}
+	for _, tt := range tests {
+		app := fiber.New()
+		app.Get("/api/sabda", Vary(ContentVary(tt.varyAuthorization)...), func(c *fiber.Ctx) error {
+			c.Vary(fiber.HeaderOrigin)
+			return c.SendStatus(fiber.StatusOK)
+		})
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/sabda", nil))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		if got, want := resp.Header.Get(fiber.HeaderVary), tt.want+", Origin"; got != want {
+			t.Errorf("varyAuthorization %v: Vary = %q, want %q", tt.varyAuthorization, got, want)
+		}
+	}
+}
@@ -4,53 +4,202 @@ import "time"
 
 // Config represents application configuration
 type Config struct {
-	Server ServerConfig `mapstructure:"server"`
-	JWT    JWTConfig    `mapstructure:"jwt"`
-	Cache  CacheConfig  `mapstructure:"cache"`
-	Rate   RateConfig   `mapstructure:"rate"`
-	API    APIConfig    `mapstructure:"api"`
-	CORS   CORSConfig   `mapstructure:"cors"`
+	Server      ServerConfig      `mapstructure:"server"`
+	JWT         JWTConfig         `mapstructure:"jwt"`
+	Cache       CacheConfig       `mapstructure:"cache"`
+	Rate        RateConfig        `mapstructure:"rate"`
+	API         APIConfig         `mapstructure:"api"`
+	CORS        CORSConfig        `mapstructure:"cors"`
+	Scraper     ScraperConfig     `mapstructure:"scraper"`
+	Stats       StatsConfig       `mapstructure:"stats"`
+	Analytics   AnalyticsConfig   `mapstructure:"analytics"`
+	Scripture   ScriptureConfig   `mapstructure:"scripture"`
+	Audit       AuditConfig       `mapstructure:"audit"`
+	SelfTest    SelfTestConfig    `mapstructure:"self_test"`
+	Privacy     PrivacyConfig     `mapstructure:"privacy"`
+	Diagnostics DiagnosticsConfig `mapstructure:"diagnostics"`
+	// SafeMode forces off every feature making outbound requests besides scraping itself
+	SafeMode bool `mapstructure:"safe_mode"`
 }
 
 // ServerConfig represents server configuration
 type ServerConfig struct {
-	Port        string        `mapstructure:"port"`
-	Host        string        `mapstructure:"host"`
-	Debug       bool          `mapstructure:"debug"`
-	Timeout     time.Duration `mapstructure:"timeout"`
-	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
+	Port                  string        `mapstructure:"port"`
+	Host                  string        `mapstructure:"host"`
+	Debug                 bool          `mapstructure:"debug"`
+	Timeout               time.Duration `mapstructure:"timeout"`
+	IdleTimeout           time.Duration `mapstructure:"idle_timeout"`
+	EnabledFormats        []string      `mapstructure:"enabled_formats"`
+	NegotiationOrder      []string      `mapstructure:"negotiation_order"`
+	DefaultLanguage       string        `mapstructure:"default_language"`
+	StaticDir             string        `mapstructure:"static_dir"`
+	RequestIDHeader       string        `mapstructure:"request_id_header"`
+	IncludeMetadata       bool          `mapstructure:"include_metadata"`
+	Maintenance           bool          `mapstructure:"maintenance"`
+	MaintenanceRetryAfter time.Duration `mapstructure:"maintenance_retry_after"`
+	StrictContentType     bool          `mapstructure:"strict_content_type"`
+	TimeFormat            string        `mapstructure:"time_format"`
+	MaxResponseBytes      int           `mapstructure:"max_response_bytes"`
+	StreamThresholdBytes  int           `mapstructure:"stream_threshold_bytes"`
+	ReportFilters         bool          `mapstructure:"report_filters"`
+	VaryAuthorization     bool          `mapstructure:"vary_authorization"`
+	TLSCertFile           string        `mapstructure:"tls_cert_file"`
+	TLSKeyFile            string        `mapstructure:"tls_key_file"`
+	TLSMinVersion         string        `mapstructure:"tls_min_version"`
+	TLSCipherSuites       []string      `mapstructure:"tls_cipher_suites"`
 }
 
 // JWTConfig represents JWT configuration
 type JWTConfig struct {
-	SecretKey        string        `mapstructure:"secret_key"`
-	ExpirationHours  int           `mapstructure:"expiration_hours"`
-	ExpirationDelta  time.Duration `mapstructure:"-"`
+	SecretKey            string        `mapstructure:"secret_key"`
+	SecretFile           string        `mapstructure:"secret_file"`
+	SecretEnv            string        `mapstructure:"secret_env"`
+	AllowEphemeralSecret bool          `mapstructure:"allow_ephemeral_secret"`
+	ExpirationHours      int           `mapstructure:"expiration_hours"`
+	ExpirationDelta      time.Duration `mapstructure:"-"`
 }
 
 // CacheConfig represents cache configuration
 type CacheConfig struct {
-	TTLSeconds int           `mapstructure:"ttl_seconds"`
-	TTL        time.Duration `mapstructure:"-"`
-	MaxSize    int           `mapstructure:"max_size"`
+	TTLSeconds             int           `mapstructure:"ttl_seconds"`
+	TTL                    time.Duration `mapstructure:"-"`
+	MaxSize                int           `mapstructure:"max_size"`
+	NegativeTTL            time.Duration `mapstructure:"negative_ttl"`
+	CleanupIntervalSeconds int           `mapstructure:"cleanup_interval_seconds"`
+	CleanupInterval        time.Duration `mapstructure:"-"`
+	CleanupJitterSeconds   int           `mapstructure:"cleanup_jitter_seconds"`
+	CleanupJitter          time.Duration `mapstructure:"-"`
+	RefreshAheadWindow     time.Duration `mapstructure:"refresh_ahead_window"`
+	RefreshAheadMinHits    int64         `mapstructure:"refresh_ahead_min_hits"`
+	SkipStaleWrites        bool          `mapstructure:"skip_stale_writes"`
+	WarmConcurrency        int           `mapstructure:"warm_concurrency"`
+	WarmJobRetention       time.Duration `mapstructure:"warm_job_retention"`
 }
 
 // RateConfig represents rate limiting configuration
 type RateConfig struct {
-	MaxRequestsPerMinute int           `mapstructure:"max_requests_per_minute"`
-	WindowDuration       time.Duration `mapstructure:"-"`
-	CleanupInterval      time.Duration `mapstructure:"-"`
+	MaxRequestsPerMinute        int           `mapstructure:"max_requests_per_minute"`
+	WarmRequestsPerMinute       int           `mapstructure:"warm_requests_per_minute"`
+	BatchTokenRequestsPerMinute int           `mapstructure:"batch_token_requests_per_minute"`
+	MaxClients                  int           `mapstructure:"max_clients"`
+	WindowDuration              time.Duration `mapstructure:"-"`
+	CleanupIntervalSeconds      int           `mapstructure:"cleanup_interval_seconds"`
+	CleanupInterval             time.Duration `mapstructure:"-"`
+	CleanupJitterSeconds        int           `mapstructure:"cleanup_jitter_seconds"`
+	CleanupJitter               time.Duration `mapstructure:"-"`
 }
 
 // APIConfig represents API keys configuration
 type APIConfig struct {
 	FlutterKey string `mapstructure:"flutter_key"`
 	MobileKey  string `mapstructure:"mobile_key"`
+	AdminKey   string `mapstructure:"admin_key"`
+	WarmerKey  string `mapstructure:"warmer_key"`
 }
 
-// CORSConfig represents CORS configuration
+// CORSConfig represents CORS configuration. When Policies is empty the top-level
+// fields form the single policy.
 type CORSConfig struct {
-	AllowedOrigins []string `mapstructure:"allowed_origins"`
-	AllowedMethods []string `mapstructure:"allowed_methods"`
-	AllowedHeaders []string `mapstructure:"allowed_headers"`
-}
\ No newline at end of file
+	AllowedOrigins []string     `mapstructure:"allowed_origins"`
+	AllowedMethods []string     `mapstructure:"allowed_methods"`
+	AllowedHeaders []string     `mapstructure:"allowed_headers"`
+	Policies       []CORSPolicy `mapstructure:"policies"`
+}
+
+// CORSPolicy is a CORS policy applied to requests whose Origin matches one of AllowedOrigins
+type CORSPolicy struct {
+	AllowedOrigins   []string `mapstructure:"allowed_origins" json:"allowed_origins"`
+	AllowedMethods   []string `mapstructure:"allowed_methods" json:"allowed_methods"`
+	AllowedHeaders   []string `mapstructure:"allowed_headers" json:"allowed_headers"`
+	AllowCredentials bool     `mapstructure:"allow_credentials" json:"allow_credentials"`
+}
+
+// ScraperConfig represents scraping configuration
+type ScraperConfig struct {
+	MaxRangeDays             int           `mapstructure:"max_range_days"`
+	RequestIDHeader          string        `mapstructure:"request_id_header"`
+	PropagateTraceparent     bool          `mapstructure:"propagate_traceparent"`
+	DailyQuota               int           `mapstructure:"daily_quota"`
+	NonAdminNoCache          string        `mapstructure:"non_admin_no_cache"`
+	MinClientMaxAge          time.Duration `mapstructure:"min_client_max_age"`
+	MinScrapeInterval        time.Duration `mapstructure:"min_scrape_interval"`
+	EnabledPublications      []string      `mapstructure:"enabled_publications"`
+	MaxConcurrentScrapes     int           `mapstructure:"max_concurrent_scrapes"`
+	MaxInFlightPerClient     int           `mapstructure:"max_inflight_per_client"`
+	QueueTimeout             time.Duration `mapstructure:"queue_timeout"`
+	ScriptureBookPattern     string        `mapstructure:"scripture_book_pattern"`
+	DisablePrintFallback     bool          `mapstructure:"disable_print_fallback"`
+	ExcerptLength            int           `mapstructure:"excerpt_length"`
+	CanonicalRefs            bool          `mapstructure:"canonical_refs"`
+	FollowPrintLink          bool          `mapstructure:"follow_print_link"`
+	MergeShortParagraphs     bool          `mapstructure:"merge_short_paragraphs"`
+	ShortParagraphLength     int           `mapstructure:"short_paragraph_length"`
+	MergedParagraphMaxLength int           `mapstructure:"merged_paragraph_max_length"`
+	InteractiveTimeout       time.Duration `mapstructure:"interactive_timeout"`
+	BackgroundTimeout        time.Duration `mapstructure:"background_timeout"`
+	FollowRedirects          bool          `mapstructure:"follow_redirects"`
+	MaxRedirects             int           `mapstructure:"max_redirects"`
+	ShareInFlightScrapes     bool          `mapstructure:"share_inflight_scrapes"`
+	DetectDuplicates         bool          `mapstructure:"detect_duplicates"`
+	EmptyResponseMinWords    int           `mapstructure:"empty_response_min_words"`
+	EmptyResponseRetries     int           `mapstructure:"empty_response_retries"`
+	EmptyResponseRetryDelay  time.Duration `mapstructure:"empty_response_retry_delay"`
+	ConditionalScrapes       bool          `mapstructure:"conditional_scrapes"`
+	ReportCacheBackend       bool          `mapstructure:"report_cache_backend"`
+	ExtractImages            bool          `mapstructure:"extract_images"`
+	SplitPrintReading        bool          `mapstructure:"split_print_reading"`
+	LeadingTrimPatterns      []string      `mapstructure:"leading_trim_patterns"`
+	TrailingTrimPatterns     []string      `mapstructure:"trailing_trim_patterns"`
+	// ContentSelectors maps a publication code to the content container CSS selectors tried in order
+	ContentSelectors map[string][]string `mapstructure:"content_selectors"`
+}
+
+// StatsConfig represents word statistics configuration
+type StatsConfig struct {
+	TopN      int      `mapstructure:"top_n"`
+	Stopwords []string `mapstructure:"stopwords"`
+}
+
+// ScriptureConfig represents the optional Bible API used to fill in missing scripture text
+type ScriptureConfig struct {
+	APIURL    string        `mapstructure:"api_url"`
+	TextField string        `mapstructure:"text_field"`
+	Timeout   time.Duration `mapstructure:"timeout"`
+	CacheTTL  time.Duration `mapstructure:"cache_ttl"`
+}
+
+// AuditConfig represents authentication audit log configuration
+type AuditConfig struct {
+	// Destination is "stdout", "stderr", a file path, or "off"
+	Destination string `mapstructure:"destination"`
+}
+
+// SelfTestConfig represents the background extraction self-test configuration
+type SelfTestConfig struct {
+	// Date is a known-good past e-SH issue as YYYY-MM-DD
+	Date       string        `mapstructure:"date"`
+	Year       int           `mapstructure:"-"`
+	MonthDay   string        `mapstructure:"-"`
+	Interval   time.Duration `mapstructure:"interval"`
+	Jitter     time.Duration `mapstructure:"jitter"`
+	MinQuality int           `mapstructure:"min_quality"`
+}
+
+// PrivacyConfig represents how client IPs are handled in logs and responses
+type PrivacyConfig struct {
+	MaskClientIP bool `mapstructure:"mask_client_ip"`
+	// IPHashSalt, when set, masks IPs by salted hash instead of truncation
+	IPHashSalt string `mapstructure:"ip_hash_salt"`
+}
+
+// DiagnosticsConfig represents diagnostics configuration
+type DiagnosticsConfig struct {
+	// HistorySize is how many recent scrapes diagnostics keeps in memory; 0 disables them
+	HistorySize int `mapstructure:"history_size"`
+}
+
+// AnalyticsConfig represents scrape history configuration
+type AnalyticsConfig struct {
+	Store      string `mapstructure:"store"`
+	BufferSize int    `mapstructure:"buffer_size"`
+}
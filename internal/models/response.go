@@ -4,33 +4,153 @@ import "time"
 
 // APIResponse represents a standardized API response
 type APIResponse struct {
-	Status   string      `json:"status"`
-	Message  string      `json:"message"`
-	Data     interface{} `json:"data,omitempty"`
-	Metadata interface{} `json:"metadata,omitempty"`
+	Status   string            `json:"status"`
+	Message  string            `json:"message"`
+	Data     interface{}       `json:"data,omitempty"`
+	Errors   map[string]string `json:"errors,omitempty"`
+	Metadata interface{}       `json:"metadata,omitempty"`
 }
 
 // DevotionalContent represents the scraped devotional content
 type DevotionalContent struct {
-	Title               string    `json:"title"`
-	ScriptureReference  string    `json:"scripture_reference"`
-	DevotionalTitle     string    `json:"devotional_title"`
-	DevotionalContent   []string  `json:"devotional_content"`
-	FullText            string    `json:"full_text"`
-	WordCount           int       `json:"word_count"`
-	ParagraphCount      int       `json:"paragraph_count"`
+	Title                 string            `json:"title"`
+	ScriptureReference    string            `json:"scripture_reference"`
+	ScriptureReferenceRaw string            `json:"scripture_reference_raw,omitempty"`
+	ScriptureText         string            `json:"scripture_text"`
+	ScriptureReferences   []string          `json:"scripture_references,omitempty"`
+	DevotionalTitle       string            `json:"devotional_title"`
+	DevotionalContent     []string          `json:"devotional_content"`
+	DevotionalHTML        []string          `json:"devotional_html,omitempty"`
+	ScriptureReading      []string          `json:"scripture_reading,omitempty"`
+	Reflection            []string          `json:"reflection,omitempty"`
+	FullText              string            `json:"full_text"`
+	Excerpt               string            `json:"excerpt,omitempty"`
+	WordCount             int               `json:"word_count"`
+	ParagraphCount        int               `json:"paragraph_count"`
+	ContentHash           string            `json:"content_hash"`
+	SourceURL             string            `json:"source_url"`
+	Edition               string            `json:"edition,omitempty"`
+	Author                string            `json:"author,omitempty"`
+	SourceTag             string            `json:"source_tag,omitempty"`
+	ExtractionMethod      string            `json:"extraction_method,omitempty"`
+	WordStats             []WordFrequency   `json:"word_stats,omitempty"`
+	TotalParagraphs       int               `json:"total_paragraphs,omitempty"`
+	Truncated             bool              `json:"truncated,omitempty"`
+	Combined              bool              `json:"combined,omitempty"`
+	Entries               []DevotionalEntry `json:"entries,omitempty"`
+	Images                []string          `json:"images,omitempty"`
+	// FiltersApplied, FinalURL, DuplicateOf and Warnings are reported in the scraping metadata,
+	// not in the content. FinalURL is only set when the scraped URL redirected.
+	FiltersApplied *FilterCounts `json:"-"`
+	FinalURL       string        `json:"-"`
+	DuplicateOf    *DuplicateOf  `json:"-"`
+	Warnings       []string      `json:"-"`
+	// ParagraphSource is how the extractor found the paragraphs, for extraction metrics
+	ParagraphSource string `json:"-"`
+}
+
+// DuplicateOf identifies an earlier cached issue whose content is identical to this one
+type DuplicateOf struct {
+	Publication string `json:"publication"`
+	Year        int    `json:"year,omitempty"`
+	Date        string `json:"date,omitempty"`
+	Edition     string `json:"edition,omitempty"`
+}
+
+// FilterCounts counts the paragraphs, or lines of text-based extraction, each extraction
+// filter dropped
+type FilterCounts struct {
+	Donation    int `json:"donation"`
+	TooShort    int `json:"too_short"`
+	Header      int `json:"header"`
+	Centered    int `json:"centered"`
+	Boilerplate int `json:"boilerplate"`
+}
+
+// Add accumulates the counts of other into f
+func (f *FilterCounts) Add(other FilterCounts) {
+	f.Donation += other.Donation
+	f.TooShort += other.TooShort
+	f.Header += other.Header
+	f.Centered += other.Centered
+	f.Boilerplate += other.Boilerplate
+}
+
+// DevotionalEntry is one of the devotionals on a page that combines several, such as a
+// weekend edition covering Saturday and Sunday
+type DevotionalEntry struct {
+	ScriptureReference string   `json:"scripture_reference"`
+	ScriptureText      string   `json:"scripture_text"`
+	DevotionalTitle    string   `json:"devotional_title"`
+	DevotionalContent  []string `json:"devotional_content"`
+	DevotionalHTML     []string `json:"devotional_html,omitempty"`
+	WordCount          int      `json:"word_count"`
+	ParagraphCount     int      `json:"paragraph_count"`
+}
+
+// WordFrequency is the number of occurrences of a word in a devotional
+type WordFrequency struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// CorpusStats aggregates the devotionals currently in the cache
+type CorpusStats struct {
+	TotalEntries        int             `json:"total_entries"`
+	AverageWordCount    float64         `json:"average_word_count"`
+	TopBooks            []BookFrequency `json:"top_books"`
+	QualityDistribution []QualityBucket `json:"quality_distribution"`
+}
+
+// BookFrequency is the number of cached devotionals citing a scripture book
+type BookFrequency struct {
+	Book  string `json:"book"`
+	Count int    `json:"count"`
+}
+
+// QualityBucket is the number of cached devotionals whose quality score is within Min and Max
+type QualityBucket struct {
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+	Count int `json:"count"`
 }
 
 // ScrapingMetadata represents metadata for scraping requests
 type ScrapingMetadata struct {
-	URL             string    `json:"url"`
-	ScrapedAt       time.Time `json:"scraped_at"`
-	Source          string    `json:"source"`
-	Cached          bool      `json:"cached,omitempty"`
-	Authenticated   bool      `json:"authenticated,omitempty"`
-	AuthMethod      string    `json:"auth_method,omitempty"`
-	ClientIP        string    `json:"client_ip,omitempty"`
-	RequestTimestamp time.Time `json:"request_timestamp,omitempty"`
+	URL                      string          `json:"url"`
+	ScrapedAt                Timestamp       `json:"scraped_at"`
+	Source                   string          `json:"source"`
+	Publication              string          `json:"publication,omitempty"`
+	Cached                   bool            `json:"cached,omitempty"`
+	CacheBackend             string          `json:"cache_backend,omitempty"`
+	CacheBypassed            bool            `json:"cache_bypassed,omitempty"`
+	CacheAgeSeconds          int64           `json:"cache_age_seconds"`
+	CacheTTLRemainingSeconds int64           `json:"cache_ttl_remaining_seconds"`
+	Refreshed                bool            `json:"refreshed,omitempty"`
+	InferredYear             int             `json:"inferred_year,omitempty"`
+	DateFormat               string          `json:"date_format,omitempty"`
+	InterpretedMonth         int             `json:"interpreted_month,omitempty"`
+	InterpretedDay           int             `json:"interpreted_day,omitempty"`
+	Warnings                 []string        `json:"warnings,omitempty"`
+	Truncated                bool            `json:"truncated,omitempty"`
+	DroppedParagraphs        int             `json:"dropped_paragraphs,omitempty"`
+	FiltersApplied           *FilterCounts   `json:"filters_applied,omitempty"`
+	FinalURL                 string          `json:"final_url,omitempty"`
+	DuplicateOf              *DuplicateOf    `json:"duplicate_of,omitempty"`
+	Content                  *ContentSummary `json:"content,omitempty"`
+	Authenticated            bool            `json:"authenticated,omitempty"`
+	AuthMethod               string          `json:"auth_method,omitempty"`
+	ClientIP                 string          `json:"client_ip,omitempty"`
+	RequestTimestamp         Timestamp       `json:"request_timestamp,omitempty"`
+}
+
+// ContentSummary describes a devotional without its text, for existence and coverage checks
+type ContentSummary struct {
+	QualityScore          int    `json:"quality_score"`
+	WordCount             int    `json:"word_count"`
+	ParagraphCount        int    `json:"paragraph_count"`
+	HasScriptureReference bool   `json:"has_scripture_reference"`
+	ExtractionMethod      string `json:"extraction_method,omitempty"`
 }
 
 // AuthRequest represents authentication request
@@ -38,17 +158,67 @@ type AuthRequest struct {
 	APIKey string `json:"api_key"`
 }
 
+// CacheWarmRequest represents a request to pre-scrape an issue, or a range of dates, into the cache
+type CacheWarmRequest struct {
+	Publication string `json:"publication"`
+	Year        int    `json:"year"`
+	Date        string `json:"date"`
+	Edition     string `json:"edition"`
+	// Start and End, in MMDD format, warm every date between them instead of a single Date
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// MaintenanceRequest represents a request to toggle maintenance mode
+type MaintenanceRequest struct {
+	Enabled *bool  `json:"enabled"`
+	Message string `json:"message"`
+}
+
+// QualityReportEntry summarizes extraction quality for one date of a quality report
+type QualityReportEntry struct {
+	Date                  string `json:"date"`
+	WordCount             int    `json:"word_count"`
+	ParagraphCount        int    `json:"paragraph_count"`
+	HasScriptureReference bool   `json:"has_scripture_reference"`
+	ExtractionMethod      string `json:"extraction_method,omitempty"`
+	QualityScore          int    `json:"quality_score"`
+	Cached                bool   `json:"cached"`
+	Error                 string `json:"error,omitempty"`
+}
+
+// BatchAuthRequest represents a request to mint tokens for several API keys at once
+type BatchAuthRequest struct {
+	APIKeys []string `json:"api_keys"`
+}
+
+// BatchAuthResult is the outcome for one key of a batch token request, in request order
+type BatchAuthResult struct {
+	Index     int    `json:"index"`
+	Token     string `json:"token,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresIn int64  `json:"expires_in,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// APIKeyCheckResult reports whether an API key is accepted, without the key or a token
+type APIKeyCheckResult struct {
+	Valid bool   `json:"valid"`
+	Label string `json:"label,omitempty"`
+	Scope string `json:"scope,omitempty"`
+}
+
 // AuthResponse represents authentication response
 type AuthResponse struct {
-	Token     string    `json:"token"`
-	TokenType string    `json:"token_type"`
-	ExpiresIn int64     `json:"expires_in"`
+	Token     string `json:"token"`
+	TokenType string `json:"token_type"`
+	ExpiresIn int64  `json:"expires_in"`
 }
 
 // AuthMetadata represents authentication metadata
 type AuthMetadata struct {
-	Timestamp time.Time `json:"timestamp"`
-	ExpiresAt time.Time `json:"expires_at"`
+	Timestamp Timestamp `json:"timestamp"`
+	ExpiresAt Timestamp `json:"expires_at"`
 }
 
 // HealthData represents health check data
@@ -56,14 +226,22 @@ type HealthData struct {
 	Service string `json:"service"`
 }
 
+// BuildInfo describes the running build, injected at build time
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+}
+
 // CacheItem represents cached content with timestamp
 type CacheItem struct {
 	Content   DevotionalContent `json:"content"`
 	Timestamp time.Time         `json:"timestamp"`
+	Hits      int64             `json:"hits"`
 }
 
 // RateLimitInfo represents rate limiting information
 type RateLimitInfo struct {
-	Requests  []time.Time `json:"requests"`
-	ClientIP  string      `json:"client_ip"`
-}
\ No newline at end of file
+	Requests []time.Time `json:"requests"`
+	ClientIP string      `json:"client_ip"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"log"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Timestamp formats for JSON responses
+const (
+	TimeFormatRFC3339     = "rfc3339"
+	TimeFormatRFC3339Nano = "rfc3339nano"
+	TimeFormatUnix        = "unix"
+)
+
+// timestampFormat is the format every Timestamp is written in, set once at startup
+var timestampFormat atomic.Value
+
+// SetTimestampFormat chooses how every Timestamp is written to JSON: RFC 3339 with second
+// precision, RFC 3339 with nanoseconds, or Unix epoch seconds. Unknown formats fall back to
+// RFC 3339.
+func SetTimestampFormat(format string) {
+	switch format {
+	case TimeFormatRFC3339, TimeFormatRFC3339Nano, TimeFormatUnix:
+	default:
+		log.Printf("Unknown time format %q, using %s", format, TimeFormatRFC3339)
+		format = TimeFormatRFC3339
+	}
+	timestampFormat.Store(format)
+}
+
+// Timestamp is a time written to JSON responses in the configured format. It is formatted
+// when encoded, so devotional text that merely looks like a timestamp is never rewritten.
+type Timestamp struct {
+	time.Time
+}
+
+// NewTimestamp wraps t for a JSON response
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t}
+}
+
+// Now returns the current time for a JSON response
+func Now() Timestamp {
+	return Timestamp{Time: time.Now()}
+}
+
+// MarshalJSON writes the timestamp in the format chosen with SetTimestampFormat
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	format, _ := timestampFormat.Load().(string)
+	switch format {
+	case TimeFormatRFC3339Nano:
+		return t.Time.MarshalJSON()
+	case TimeFormatUnix:
+		return strconv.AppendInt(nil, t.Unix(), 10), nil
+	default:
+		return strconv.AppendQuote(nil, t.Format(time.RFC3339)), nil
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestampMarshalJSON(t *testing.T) {
+	defer SetTimestampFormat(TimeFormatRFC3339)
+	at := time.Date(2025, 9, 2, 6, 0, 1, 500000000, time.UTC)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{TimeFormatRFC3339, `"2025-09-02T06:00:01Z"`},
+		{TimeFormatRFC3339Nano, `"2025-09-02T06:00:01.5Z"`},
+		{TimeFormatUnix, `1756792801`},
+		{"bogus", `"2025-09-02T06:00:01Z"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			SetTimestampFormat(tt.format)
+			got, err := json.Marshal(NewTimestamp(at))
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimestampLeavesTextAlone(t *testing.T) {
+	defer SetTimestampFormat(TimeFormatRFC3339)
+	SetTimestampFormat(TimeFormatUnix)
+
+	paragraph := "2025-09-02T06:00:01.5Z"
+	body, err := json.Marshal(APIResponse{
+		Data:     DevotionalContent{DevotionalContent: []string{paragraph}},
+		Metadata: ScrapingMetadata{ScrapedAt: NewTimestamp(time.Unix(1756792801, 0))},
+	})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded struct {
+		Data struct {
+			DevotionalContent []string `json:"devotional_content"`
+		} `json:"data"`
+		Metadata struct {
+			ScrapedAt int64 `json:"scraped_at"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if decoded.Data.DevotionalContent[0] != paragraph {
+		t.Errorf("paragraph = %q, want %q", decoded.Data.DevotionalContent[0], paragraph)
+	}
+	if decoded.Metadata.ScrapedAt != 1756792801 {
+		t.Errorf("scraped_at = %d, want 1756792801", decoded.Metadata.ScrapedAt)
+	}
+}
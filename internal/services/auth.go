@@ -4,25 +4,88 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Token scopes carried in the "scope" claim
+const (
+	ScopeClient = "client"
+	ScopeAdmin  = "admin"
+	ScopeWarmer = "warmer"
+)
+
 // AuthService handles JWT authentication
 type AuthService struct {
 	secretKey  string
 	expiration time.Duration
+	keys       atomic.Pointer[authKeys]
+	audit      *AuditLogger
+}
+
+// authKeys is an immutable snapshot of the accepted API keys, swapped as a whole on reload
+type authKeys struct {
 	apiKeys    map[string]string
+	scopedKeys map[string]string
+	// hashes maps the hashed form of every accepted key, as carried in token claims, to its label
+	hashes map[string]string
+	// scopes maps the hashed form of every accepted key to the scope its tokens currently carry
+	scopes map[string]string
 }
 
-// NewAuthService creates a new authentication service
-func NewAuthService(secretKey string, expiration time.Duration, apiKeys map[string]string) *AuthService {
-	return &AuthService{
+// NewAuthService creates a new authentication service.
+// scopedKeys maps a scope (e.g. ScopeAdmin) to the API key whose tokens carry it; empty keys are ignored.
+// Tokens issued for ordinary API keys carry ScopeClient. Key updates are recorded to audit,
+// which may be nil.
+func NewAuthService(secretKey string, expiration time.Duration, apiKeys map[string]string, scopedKeys map[string]string, audit *AuditLogger) *AuthService {
+	service := &AuthService{
 		secretKey:  secretKey,
 		expiration: expiration,
+		audit:      audit,
+	}
+	service.keys.Store(service.newAuthKeys(apiKeys, scopedKeys))
+	return service
+}
+
+// UpdateKeys replaces the accepted API keys. Requests in flight keep the keys they started
+// with; tokens issued for a key that is no longer accepted stop verifying.
+func (a *AuthService) UpdateKeys(apiKeys map[string]string, scopedKeys map[string]string) {
+	keys := a.newAuthKeys(apiKeys, scopedKeys)
+	a.keys.Store(keys)
+
+	labels := make([]string, 0, len(keys.hashes))
+	for _, label := range keys.hashes {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	a.audit.Record(AuditEvent{Event: AuditKeysUpdated, Outcome: AuditSuccess, Label: strings.Join(labels, ",")})
+}
+
+func (a *AuthService) newAuthKeys(apiKeys map[string]string, scopedKeys map[string]string) *authKeys {
+	keys := &authKeys{
 		apiKeys:    apiKeys,
+		scopedKeys: scopedKeys,
+		hashes:     make(map[string]string),
+		scopes:     make(map[string]string),
 	}
+	for label, key := range apiKeys {
+		if key != "" {
+			keys.hashes[a.hashAPIKey(key)] = label
+			keys.scopes[a.hashAPIKey(key)] = ScopeClient
+		}
+	}
+	// A key that is also scoped carries its scope, as in scopeFor
+	for scope, key := range scopedKeys {
+		if key != "" {
+			keys.hashes[a.hashAPIKey(key)] = scope
+			keys.scopes[a.hashAPIKey(key)] = scope
+		}
+	}
+	return keys
 }
 
 // GenerateToken generates a JWT token for the given API key
@@ -40,6 +103,7 @@ func (a *AuthService) GenerateToken(apiKey string) (string, time.Time, error) {
 		"api_key": a.hashAPIKey(apiKey),
 		"exp":     expiresAt.Unix(),
 		"iat":     now.Unix(),
+		"scope":   a.scopeFor(apiKey),
 	}
 
 	// Create token
@@ -75,6 +139,15 @@ func (a *AuthService) VerifyToken(tokenString string) (*jwt.MapClaims, error) {
 		return nil, fmt.Errorf("invalid token claims")
 	}
 
+	// Tokens die with their API key once it is removed from the configuration, and carry the
+	// key's current scope rather than the one it had when the token was issued
+	keyHash, _ := claims["api_key"].(string)
+	scope := a.keys.Load().scopes[keyHash]
+	if scope == "" {
+		return nil, fmt.Errorf("token was issued for an API key that is no longer accepted")
+	}
+	claims["scope"] = scope
+
 	return &claims, nil
 }
 
@@ -83,8 +156,58 @@ func (a *AuthService) IsValidAPIKey(apiKey string) bool {
 	return a.isValidAPIKey(apiKey)
 }
 
+// KeyInfo describes a valid API key without exposing it: label is the name the key is
+// configured under (e.g. "flutter", or the scope for scoped keys) and scope is the scope its
+// tokens carry. Both are empty for keys that are not accepted.
+func (a *AuthService) KeyInfo(apiKey string) (label, scope string) {
+	keys := a.keys.Load()
+	for scoped, key := range keys.scopedKeys {
+		if key != "" && apiKey == key {
+			return scoped, scoped
+		}
+	}
+	for name, key := range keys.apiKeys {
+		if apiKey == key {
+			return name, ScopeClient
+		}
+	}
+	return "", ""
+}
+
+// TokenLabel returns the label of the API key verified token claims were issued for, or an
+// empty string when that key is no longer accepted
+func (a *AuthService) TokenLabel(claims *jwt.MapClaims) string {
+	if claims == nil {
+		return ""
+	}
+	keyHash, _ := (*claims)["api_key"].(string)
+	return a.keys.Load().hashes[keyHash]
+}
+
+// HasScope reports whether verified token claims grant the given scope. VerifyToken sets the
+// scope claim from the key's current configuration.
+func HasScope(claims *jwt.MapClaims, scope string) bool {
+	if claims == nil {
+		return false
+	}
+	granted, _ := (*claims)["scope"].(string)
+	return granted == scope
+}
+
+func (a *AuthService) scopeFor(apiKey string) string {
+	for scope, key := range a.keys.Load().scopedKeys {
+		if key != "" && apiKey == key {
+			return scope
+		}
+	}
+	return ScopeClient
+}
+
 func (a *AuthService) isValidAPIKey(apiKey string) bool {
-	for _, validKey := range a.apiKeys {
+	if a.scopeFor(apiKey) != ScopeClient {
+		return true
+	}
+	for _, validKey := range a.keys.Load().apiKeys {
 		if apiKey == validKey {
 			return true
 		}
@@ -95,4 +218,4 @@ func (a *AuthService) isValidAPIKey(apiKey string) bool {
 func (a *AuthService) hashAPIKey(apiKey string) string {
 	hash := sha256.Sum256([]byte(apiKey))
 	return hex.EncodeToString(hash[:])
-}
\ No newline at end of file
+}
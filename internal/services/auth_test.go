@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifyTokenUsesCurrentScope(t *testing.T) {
+	auth := NewAuthService("secret", time.Hour, map[string]string{"flutter": "client-key"}, map[string]string{ScopeAdmin: "admin-key"}, nil)
+	token, _, err := auth.GenerateToken("admin-key")
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	claims, err := auth.VerifyToken(token)
+	if err != nil {
+		t.Fatalf("VerifyToken() error = %v", err)
+	}
+	if !HasScope(claims, ScopeAdmin) {
+		t.Fatalf("token of admin key lacks admin scope")
+	}
+
+	// A reload demotes the admin key to an ordinary client key
+	auth.UpdateKeys(map[string]string{"flutter": "client-key", "partner": "admin-key"}, map[string]string{})
+	claims, err = auth.VerifyToken(token)
+	if err != nil {
+		t.Fatalf("VerifyToken() after reload error = %v", err)
+	}
+	if HasScope(claims, ScopeAdmin) {
+		t.Errorf("token kept admin scope after its key was demoted")
+	}
+	if !HasScope(claims, ScopeClient) {
+		t.Errorf("token of demoted key lacks client scope")
+	}
+	if label := auth.TokenLabel(claims); label != "partner" {
+		t.Errorf("TokenLabel() = %q, want partner", label)
+	}
+
+	// Removing the key revokes its tokens
+	auth.UpdateKeys(map[string]string{"flutter": "client-key"}, map[string]string{})
+	if _, err := auth.VerifyToken(token); err == nil {
+		t.Errorf("VerifyToken() accepted a token of a removed key")
+	}
+}
+
+func TestVerifyTokenPromotesClientKey(t *testing.T) {
+	auth := NewAuthService("secret", time.Hour, map[string]string{"flutter": "shared-key"}, map[string]string{}, nil)
+	token, _, err := auth.GenerateToken("shared-key")
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	auth.UpdateKeys(map[string]string{"flutter": "shared-key"}, map[string]string{ScopeWarmer: "shared-key"})
+	claims, err := auth.VerifyToken(token)
+	if err != nil {
+		t.Fatalf("VerifyToken() error = %v", err)
+	}
+	if !HasScope(claims, ScopeWarmer) {
+		t.Errorf("token lacks the scope its key was given on reload")
+	}
+}
@@ -9,30 +9,59 @@ import (
 
 // CacheService handles content caching
 type CacheService struct {
-	cache   map[string]models.CacheItem
-	mutex   sync.RWMutex
-	ttl     time.Duration
-	maxSize int
+	cache     map[string]models.CacheItem
+	negatives map[string]time.Time
+	// hashes maps a content hash to the key of the first cached entry with that content
+	hashes          map[string]string
+	mutex           sync.RWMutex
+	ttl             time.Duration
+	negativeTTL     time.Duration
+	maxSize         int
+	skipStaleWrites bool
+
+	refreshAheadWindow  time.Duration
+	refreshAheadMinHits int64
 }
 
-// NewCacheService creates a new cache service
-func NewCacheService(ttl time.Duration, maxSize int) *CacheService {
+// NewCacheService creates a new cache service.
+// Negative (not published) results are kept for negativeTTL; a non-positive value disables them.
+// A non-positive cleanupInterval disables background cleanup of expired entries.
+// Entries read at least refreshAheadMinHits times are due for refresh within refreshAheadWindow
+// of expiry; a non-positive window disables refresh-ahead. With skipStaleWrites, Set leaves an
+// entry in place when the content written was scraped no later than the entry's.
+func NewCacheService(ttl, negativeTTL time.Duration, maxSize int, cleanupInterval, cleanupJitter, refreshAheadWindow time.Duration, refreshAheadMinHits int64, skipStaleWrites bool) *CacheService {
 	service := &CacheService{
-		cache:   make(map[string]models.CacheItem),
-		ttl:     ttl,
-		maxSize: maxSize,
+		cache:       make(map[string]models.CacheItem),
+		negatives:   make(map[string]time.Time),
+		hashes:      make(map[string]string),
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		maxSize:     maxSize,
+
+		skipStaleWrites:     skipStaleWrites,
+		refreshAheadWindow:  refreshAheadWindow,
+		refreshAheadMinHits: refreshAheadMinHits,
 	}
 
 	// Start cleanup goroutine
-	go service.cleanupExpired()
+	go runPeriodic(cleanupInterval, cleanupJitter, service.cleanupExpired)
 
 	return service
 }
 
 // Get retrieves content from cache
 func (c *CacheService) Get(key string) (*models.DevotionalContent, bool) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
+	item, exists := c.GetItem(key)
+	if !exists {
+		return nil, false
+	}
+	return &item.Content, true
+}
+
+// GetItem retrieves content from cache along with the time it was stored, counting the read
+func (c *CacheService) GetItem(key string) (*models.CacheItem, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
 	item, exists := c.cache[key]
 	if !exists {
@@ -44,23 +73,145 @@ func (c *CacheService) Get(key string) (*models.DevotionalContent, bool) {
 		return nil, false
 	}
 
-	return &item.Content, true
+	item.Hits++
+	c.cache[key] = item
+	return &item, true
+}
+
+// Peek returns the entry for key even when it has expired, as long as it has not been removed,
+// without counting a read
+func (c *CacheService) Peek(key string) (*models.CacheItem, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	item, exists := c.cache[key]
+	if !exists {
+		return nil, false
+	}
+	return &item, true
+}
+
+// DueForRefresh reports whether item is read often enough and close enough to expiry
+// that it should be re-scraped before it expires
+func (c *CacheService) DueForRefresh(item *models.CacheItem) bool {
+	if c.refreshAheadWindow <= 0 || item.Hits < c.refreshAheadMinHits {
+		return false
+	}
+	return time.Until(item.Timestamp.Add(c.ttl)) <= c.refreshAheadWindow
+}
+
+// CacheBackendMemory names the in-process cache backend
+const CacheBackendMemory = "memory"
+
+// Backend names the storage serving cache hits, reported to clients as cache_backend
+func (c *CacheService) Backend() string {
+	return CacheBackendMemory
+}
+
+// TTL returns how long entries stay fresh
+func (c *CacheService) TTL() time.Duration {
+	return c.ttl
+}
+
+// NegativeTTL returns how long not-published results are remembered; non-positive when disabled
+func (c *CacheService) NegativeTTL() time.Duration {
+	return c.negativeTTL
 }
 
-// Set stores content in cache
-func (c *CacheService) Set(key string, content models.DevotionalContent) {
+// Set stores content scraped at scrapedAt in cache. When stale writes are skipped, an entry
+// scraped at or after scrapedAt is kept, so the slower of two concurrent scrapes of a key
+// cannot displace the fresher result; Set reports whether it wrote the entry.
+func (c *CacheService) Set(key string, content models.DevotionalContent, scrapedAt time.Time) bool {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	// Remove oldest entries if cache is full
-	if len(c.cache) >= c.maxSize {
+	previous, exists := c.cache[key]
+	if exists && c.skipStaleWrites && !previous.Timestamp.Before(scrapedAt) {
+		return false
+	}
+
+	// Remove oldest entries if cache is full; replacing an entry does not grow it
+	if !exists && len(c.cache) >= c.maxSize {
 		c.removeOldest()
 	}
 
+	if exists {
+		c.unindex(key, previous.Content.ContentHash)
+	}
 	c.cache[key] = models.CacheItem{
 		Content:   content,
-		Timestamp: time.Now(),
+		Timestamp: scrapedAt,
 	}
+	if _, indexed := c.keyForHash(content.ContentHash); !indexed && content.ContentHash != "" {
+		c.hashes[content.ContentHash] = key
+	}
+	delete(c.negatives, key)
+	return true
+}
+
+// DuplicateOf returns the key of another unexpired entry whose content hash is hash, so
+// content about to be stored under key can be recognized as a republished issue
+func (c *CacheService) DuplicateOf(key, hash string) (string, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	original, found := c.keyForHash(hash)
+	if !found || original == key {
+		return "", false
+	}
+	return original, true
+}
+
+// keyForHash returns the indexed key for hash if its entry is unexpired and still has that content
+func (c *CacheService) keyForHash(hash string) (string, bool) {
+	key, indexed := c.hashes[hash]
+	if !indexed {
+		return "", false
+	}
+	item, exists := c.cache[key]
+	if !exists || item.Content.ContentHash != hash || time.Since(item.Timestamp) > c.ttl {
+		return "", false
+	}
+	return key, true
+}
+
+// remove deletes the entry for key along with its hash index entry
+func (c *CacheService) remove(key string) {
+	if item, exists := c.cache[key]; exists {
+		c.unindex(key, item.Content.ContentHash)
+	}
+	delete(c.cache, key)
+}
+
+// unindex drops the hash index entry for hash if it points at key
+func (c *CacheService) unindex(key, hash string) {
+	if c.hashes[hash] == key {
+		delete(c.hashes, hash)
+	}
+}
+
+// SetNegative records that no content exists for key, so repeated lookups can skip scraping
+func (c *CacheService) SetNegative(key string) {
+	if c.negativeTTL <= 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.negatives) >= c.maxSize {
+		c.removeOldestNegative()
+	}
+	c.negatives[key] = time.Now()
+}
+
+// IsNegative reports whether key has an unexpired negative entry
+func (c *CacheService) IsNegative(key string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	cachedAt, exists := c.negatives[key]
+	return exists && time.Since(cachedAt) <= c.negativeTTL
 }
 
 // Clear removes all items from cache
@@ -69,6 +220,22 @@ func (c *CacheService) Clear() {
 	defer c.mutex.Unlock()
 
 	c.cache = make(map[string]models.CacheItem)
+	c.hashes = make(map[string]string)
+	c.negatives = make(map[string]time.Time)
+}
+
+// Snapshot returns a copy of the content of all unexpired entries, in no particular order
+func (c *CacheService) Snapshot() []models.DevotionalContent {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	contents := make([]models.DevotionalContent, 0, len(c.cache))
+	for _, item := range c.cache {
+		if time.Since(item.Timestamp) <= c.ttl {
+			contents = append(contents, item.Content)
+		}
+	}
+	return contents
 }
 
 // Size returns the current cache size
@@ -91,25 +258,39 @@ func (c *CacheService) removeOldest() {
 	}
 
 	if oldestKey != "" {
-		delete(c.cache, oldestKey)
+		c.remove(oldestKey)
+	}
+}
+
+func (c *CacheService) removeOldestNegative() {
+	var oldestKey string
+	var oldestTime time.Time
+
+	for key, cachedAt := range c.negatives {
+		if oldestKey == "" || cachedAt.Before(oldestTime) {
+			oldestKey = key
+			oldestTime = cachedAt
+		}
+	}
+
+	if oldestKey != "" {
+		delete(c.negatives, oldestKey)
 	}
 }
 
 func (c *CacheService) cleanupExpired() {
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			c.mutex.Lock()
-			now := time.Now()
-			for key, item := range c.cache {
-				if now.Sub(item.Timestamp) > c.ttl {
-					delete(c.cache, key)
-				}
-			}
-			c.mutex.Unlock()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	for key, item := range c.cache {
+		if now.Sub(item.Timestamp) > c.ttl {
+			c.remove(key)
 		}
 	}
-}
\ No newline at end of file
+	for key, cachedAt := range c.negatives {
+		if now.Sub(cachedAt) > c.negativeTTL {
+			delete(c.negatives, key)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+func newTestCache(maxSize int, skipStaleWrites bool) *CacheService {
+	return NewCacheService(time.Hour, time.Hour, maxSize, 0, 0, 0, 0, skipStaleWrites)
+}
+
+func TestCacheSetSkipsStaleWrites(t *testing.T) {
+	scrapedAt := time.Now()
+	fresher := models.DevotionalContent{Title: "fresher"}
+	slower := models.DevotionalContent{Title: "slower"}
+
+	c := newTestCache(10, true)
+	if !c.Set("e-sh:2025:0902", fresher, scrapedAt) {
+		t.Fatal("first write skipped")
+	}
+	if c.Set("e-sh:2025:0902", slower, scrapedAt.Add(-time.Second)) {
+		t.Error("older scrape replaced the fresher entry")
+	}
+	if c.Set("e-sh:2025:0902", slower, scrapedAt) {
+		t.Error("scrape from the same moment replaced the entry")
+	}
+	if content, _ := c.Get("e-sh:2025:0902"); content.Title != "fresher" {
+		t.Errorf("cached %q, want fresher", content.Title)
+	}
+	if !c.Set("e-sh:2025:0902", slower, scrapedAt.Add(time.Second)) {
+		t.Error("newer scrape skipped")
+	}
+
+	overwriting := newTestCache(10, false)
+	overwriting.Set("e-sh:2025:0902", fresher, scrapedAt)
+	if !overwriting.Set("e-sh:2025:0902", slower, scrapedAt.Add(-time.Second)) {
+		t.Error("write skipped with stale writes allowed")
+	}
+	if content, _ := overwriting.Get("e-sh:2025:0902"); content.Title != "slower" {
+		t.Errorf("cached %q, want the last write", content.Title)
+	}
+}
+
+func TestCacheHashIndex(t *testing.T) {
+	now := time.Now()
+	republished := models.DevotionalContent{ContentHash: "abc"}
+	c := newTestCache(2, false)
+
+	c.Set("e-sh:2025:0901", republished, now)
+	if original, found := c.DuplicateOf("e-sh:2025:0908", "abc"); !found || original != "e-sh:2025:0901" {
+		t.Errorf("DuplicateOf() = %q, %v, want the first issue", original, found)
+	}
+	if _, found := c.DuplicateOf("e-sh:2025:0901", "abc"); found {
+		t.Error("entry reported as a duplicate of itself")
+	}
+
+	// Replacing the entry's content drops it from the index
+	c.Set("e-sh:2025:0901", models.DevotionalContent{ContentHash: "def"}, now)
+	if _, found := c.DuplicateOf("e-sh:2025:0908", "abc"); found {
+		t.Error("replaced content still indexed")
+	}
+
+	// Evicting the entry drops it from the index
+	c.Set("e-sh:2025:0902", models.DevotionalContent{ContentHash: "ghi"}, now.Add(time.Second))
+	c.Set("e-sh:2025:0903", models.DevotionalContent{ContentHash: "jkl"}, now.Add(2*time.Second))
+	if _, found := c.DuplicateOf("e-sh:2025:0908", "def"); found {
+		t.Error("evicted entry still indexed")
+	}
+	if len(c.hashes) != 2 {
+		t.Errorf("hash index holds %d entries for 2 cached, want 2", len(c.hashes))
+	}
+
+	// Expired entries are not reported
+	c.Set("e-sh:2025:0904", models.DevotionalContent{ContentHash: "mno"}, now.Add(-2*time.Hour))
+	if _, found := c.DuplicateOf("e-sh:2025:0908", "mno"); found {
+		t.Error("expired entry reported as the original")
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClientBusy is returned when a cache miss would start another upstream scrape for a client
+// that already has its limit of scrapes in flight
+var ErrClientBusy = errors.New("too many concurrent scrapes for client")
+
+// clientScrape is the client a request scrapes for. Every scrape made with the request's
+// context shares one of the client's slots, so a range or context request counts once.
+type clientScrape struct {
+	client string
+	// active is the number of the request's scrapes holding its slot, guarded by the limiter
+	active int
+}
+
+type clientScrapeKey struct{}
+
+// WithScrapeClient returns a context whose cache-missing scrapes count against client's limit
+// of concurrent in-flight scrapes. Contexts without a client, e.g. warm jobs, are not limited.
+func WithScrapeClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, clientScrapeKey{}, &clientScrape{client: client})
+}
+
+// clientLimiter bounds the requests per client with an upstream scrape in flight
+type clientLimiter struct {
+	mutex    sync.Mutex
+	limit    int
+	inFlight map[string]int
+}
+
+// newClientLimiter allows limit concurrent scraping requests per client, or returns nil when
+// limit is not positive
+func newClientLimiter(limit int) *clientLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &clientLimiter{limit: limit, inFlight: make(map[string]int)}
+}
+
+// acquire takes a slot for the client of ctx, reporting false when the client is at its limit.
+// A nil limiter or a context without a client always succeeds; release undoes a success.
+func (l *clientLimiter) acquire(ctx context.Context) bool {
+	scrape, _ := ctx.Value(clientScrapeKey{}).(*clientScrape)
+	if l == nil || scrape == nil {
+		return true
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if scrape.active == 0 {
+		if l.inFlight[scrape.client] >= l.limit {
+			return false
+		}
+		l.inFlight[scrape.client]++
+	}
+	scrape.active++
+	return true
+}
+
+func (l *clientLimiter) release(ctx context.Context) {
+	scrape, _ := ctx.Value(clientScrapeKey{}).(*clientScrape)
+	if l == nil || scrape == nil {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	scrape.active--
+	if scrape.active > 0 {
+		return
+	}
+	if l.inFlight[scrape.client] <= 1 {
+		delete(l.inFlight, scrape.client)
+	} else {
+		l.inFlight[scrape.client]--
+	}
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+func TestClientLimiter(t *testing.T) {
+	limiter := newClientLimiter(1)
+	first := WithScrapeClient(context.Background(), "ip:203.0.113.7")
+	second := WithScrapeClient(context.Background(), "ip:203.0.113.7")
+	other := WithScrapeClient(context.Background(), "ip:198.51.100.1")
+
+	if !limiter.acquire(first) {
+		t.Fatal("first request refused")
+	}
+	if !limiter.acquire(first) {
+		t.Error("second scrape of the same request refused; a request holds one slot")
+	}
+	if limiter.acquire(second) {
+		t.Error("second request of the same client allowed beyond the limit")
+	}
+	if !limiter.acquire(other) {
+		t.Error("request of another client refused")
+	}
+	if !limiter.acquire(context.Background()) {
+		t.Error("scrape without a client refused")
+	}
+
+	limiter.release(first)
+	if limiter.acquire(second) {
+		t.Error("slot freed while the first request still has a scrape in flight")
+	}
+	limiter.release(first)
+	if !limiter.acquire(second) {
+		t.Error("slot not freed once the first request finished")
+	}
+	limiter.release(second)
+	limiter.release(other)
+	if len(limiter.inFlight) != 0 {
+		t.Errorf("inFlight = %v after every release, want empty", limiter.inFlight)
+	}
+}
+
+func TestClientLimiterDisabled(t *testing.T) {
+	var limiter *clientLimiter = newClientLimiter(0)
+	ctx := WithScrapeClient(context.Background(), "ip:203.0.113.7")
+	for i := 0; i < 3; i++ {
+		if !limiter.acquire(ctx) {
+			t.Fatal("disabled limiter refused a scrape")
+		}
+	}
+	limiter.release(ctx)
+}
+
+func TestScrapeContentRefusesConcurrentColdRequestsOfOneClient(t *testing.T) {
+	stub := &upstreamStub{delay: 300 * time.Millisecond}
+	s := newStubbedScraperService(t, models.ScraperConfig{MaxInFlightPerClient: 1, DisablePrintFallback: true}, stub)
+	target := func(date string) scraper.Target {
+		return scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: date}
+	}
+
+	// Warm one date so a cache hit can be checked while the client is at its limit
+	if _, err := s.ScrapeContent(context.Background(), target("0901"), ScrapeOptions{}); err != nil {
+		t.Fatalf("warming scrape failed: %v", err)
+	}
+
+	dates := []string{"0902", "0903", "0904"}
+	errs := make([]error, len(dates))
+	var wg sync.WaitGroup
+	for i, date := range dates {
+		wg.Add(1)
+		go func(i int, date string) {
+			defer wg.Done()
+			ctx := WithScrapeClient(context.Background(), "ip:203.0.113.7")
+			_, errs[i] = s.ScrapeContent(ctx, target(date), ScrapeOptions{})
+		}(i, date)
+	}
+	var otherErr, hitErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		time.Sleep(50 * time.Millisecond)
+		_, otherErr = s.ScrapeContent(WithScrapeClient(context.Background(), "ip:198.51.100.1"), target("0905"), ScrapeOptions{})
+		_, hitErr = s.ScrapeContent(WithScrapeClient(context.Background(), "ip:203.0.113.7"), target("0901"), ScrapeOptions{})
+	}()
+	wg.Wait()
+
+	succeeded, refused := 0, 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, ErrClientBusy):
+			refused++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if succeeded != 1 || refused != 2 {
+		t.Errorf("succeeded = %d, refused = %d, want 1 and 2", succeeded, refused)
+	}
+	if otherErr != nil {
+		t.Errorf("another client's cold request failed: %v", otherErr)
+	}
+	if hitErr != nil {
+		t.Errorf("cache hit of a busy client failed: %v", hitErr)
+	}
+	if got := s.OutcomeCounts()[OutcomeClientBusy]; got != 2 {
+		t.Errorf("client_busy outcomes = %d, want 2", got)
+	}
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// scrapeFlight lets concurrent scrapes of the same cache key share one upstream call, so a
+// request for a date that a warm job or refresh-ahead is already scraping waits for that
+// scrape instead of starting its own.
+type scrapeFlight struct {
+	mutex sync.Mutex
+	calls map[string]*flightCall
+	// timeout bounds a shared scrape, which no longer ends with the request that started it
+	timeout time.Duration
+}
+
+// flightCall is an upstream scrape in progress; result is set before done is closed
+type flightCall struct {
+	done   chan struct{}
+	result upstreamResult
+}
+
+func newScrapeFlight(timeout time.Duration) *scrapeFlight {
+	return &scrapeFlight{calls: make(map[string]*flightCall), timeout: timeout}
+}
+
+// do runs scrape for key unless a scrape of key is already in flight, in which case it waits
+// for that scrape and reports shared. The scrape runs detached from the caller that started
+// it, keeping its context values but bounded by the flight's timeout instead of that
+// caller's deadline, so a starting request that gives up does not fail every request waiting
+// on the scrape. Every caller, the starting one included, gives up with its own context's
+// error when that ends before the scrape does.
+func (f *scrapeFlight) do(ctx context.Context, key string, scrape func(context.Context) upstreamResult) (upstreamResult, bool) {
+	f.mutex.Lock()
+	call, shared := f.calls[key]
+	if !shared {
+		call = &flightCall{done: make(chan struct{})}
+		f.calls[key] = call
+		go f.run(ctx, key, call, scrape)
+	}
+	f.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		return call.result, shared
+	case <-ctx.Done():
+		return upstreamResult{err: ctx.Err()}, shared
+	}
+}
+
+// run performs the scrape of call and publishes its result
+func (f *scrapeFlight) run(ctx context.Context, key string, call *flightCall, scrape func(context.Context) upstreamResult) {
+	scrapeCtx, cancel := WithScrapeTimeout(context.WithoutCancel(ctx), f.timeout)
+	defer cancel()
+
+	call.result = scrape(scrapeCtx)
+
+	f.mutex.Lock()
+	delete(f.calls, key)
+	f.mutex.Unlock()
+	close(call.done)
+}
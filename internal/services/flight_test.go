@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+type flightTestKey struct{}
+
+func TestFlightSharesOneScrape(t *testing.T) {
+	f := newScrapeFlight(time.Minute)
+	release := make(chan struct{})
+	var mutex sync.Mutex
+	scrapes := 0
+	scrape := func(context.Context) upstreamResult {
+		mutex.Lock()
+		scrapes++
+		mutex.Unlock()
+		<-release
+		return upstreamResult{content: &models.DevotionalContent{Title: "shared"}}
+	}
+
+	results := make(chan bool, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			result, shared := f.do(context.Background(), "key", scrape)
+			if result.content == nil || result.content.Title != "shared" {
+				t.Errorf("result = %+v, want the shared content", result)
+			}
+			results <- shared
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	leaders := 0
+	for i := 0; i < 3; i++ {
+		if !<-results {
+			leaders++
+		}
+	}
+	if scrapes != 1 || leaders != 1 {
+		t.Errorf("scrapes = %d, leaders = %d, want 1 and 1", scrapes, leaders)
+	}
+}
+
+func TestFlightScrapeOutlivesStartingRequest(t *testing.T) {
+	f := newScrapeFlight(time.Minute)
+	release := make(chan struct{})
+	scrapeErr := make(chan error, 1)
+	var correlation interface{}
+	scrape := func(ctx context.Context) upstreamResult {
+		correlation = ctx.Value(flightTestKey{})
+		if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) < 30*time.Second {
+			t.Errorf("scrape deadline = %v, %v; want the flight's timeout", deadline, ok)
+		}
+		<-release
+		scrapeErr <- ctx.Err()
+		return upstreamResult{content: &models.DevotionalContent{Title: "late"}}
+	}
+
+	leaderCtx, cancel := context.WithTimeout(context.WithValue(context.Background(), flightTestKey{}, "req-1"), 20*time.Millisecond)
+	defer cancel()
+	joined := make(chan upstreamResult, 1)
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		result, shared := f.do(context.Background(), "key", scrape)
+		if !shared {
+			t.Error("second caller started its own scrape")
+		}
+		joined <- result
+	}()
+
+	result, shared := f.do(leaderCtx, "key", scrape)
+	if shared {
+		t.Error("first caller reported a shared scrape")
+	}
+	if !errors.Is(result.err, context.DeadlineExceeded) {
+		t.Errorf("starting request err = %v, want its own deadline", result.err)
+	}
+
+	close(release)
+	if got := <-joined; got.content == nil || got.content.Title != "late" {
+		t.Errorf("waiting request got %+v, want the scraped content", got)
+	}
+	if err := <-scrapeErr; err != nil {
+		t.Errorf("scrape context ended with the starting request: %v", err)
+	}
+	if correlation != "req-1" {
+		t.Errorf("scrape context value = %v, want the starting request's", correlation)
+	}
+}
+
+func TestFlightTimeoutBoundsScrape(t *testing.T) {
+	f := newScrapeFlight(20 * time.Millisecond)
+	result, _ := f.do(context.Background(), "key", func(ctx context.Context) upstreamResult {
+		<-ctx.Done()
+		return upstreamResult{err: ctx.Err()}
+	})
+	if !errors.Is(result.err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the flight's timeout", result.err)
+	}
+}
+
+func TestFlightForgetsFinishedScrapes(t *testing.T) {
+	f := newScrapeFlight(time.Minute)
+	scrapes := 0
+	scrape := func(context.Context) upstreamResult {
+		scrapes++
+		return upstreamResult{err: ErrUpstream}
+	}
+	for i := 0; i < 2; i++ {
+		if _, shared := f.do(context.Background(), "key", scrape); shared {
+			t.Error("finished scrape reused by a later call")
+		}
+	}
+	if scrapes != 2 {
+		t.Errorf("scrapes = %d, want every later call to scrape again", scrapes)
+	}
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+// upstreamStub answers every sabda.org request with testdata/esh.html, after delay, and
+// counts the requests. URLs containing a key of pages get that testdata file instead, and
+// URLs containing notFound, when set, get a 404. Conditional requests are counted too, and
+// answered with a 304 when notModified is set.
+type upstreamStub struct {
+	delay       time.Duration
+	pages       map[string]string
+	notFound    string
+	notModified bool
+	requests    atomic.Int64
+	conditional atomic.Int64
+}
+
+func (u *upstreamStub) RoundTrip(r *http.Request) (*http.Response, error) {
+	u.requests.Add(1)
+	time.Sleep(u.delay)
+	if u.notFound != "" && strings.Contains(r.URL.String(), u.notFound) {
+		return &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("not found")), Request: r}, nil
+	}
+	if r.Header.Get("If-Modified-Since") != "" {
+		u.conditional.Add(1)
+		if u.notModified {
+			return &http.Response{StatusCode: http.StatusNotModified, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("")), Request: r}, nil
+		}
+	}
+	page := "esh.html"
+	for fragment, file := range u.pages {
+		if strings.Contains(r.URL.String(), fragment) {
+			page = file
+		}
+	}
+	body, err := os.ReadFile("testdata/" + page)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": {"text/html"}}, Body: io.NopCloser(strings.NewReader(string(body))), Request: r}, nil
+}
+
+// newStubbedScraperService returns a scraper service whose upstream requests go to stub
+func newStubbedScraperService(t *testing.T, cfg models.ScraperConfig, stub *upstreamStub) *ScraperService {
+	t.Helper()
+	return newStubbedScraperServiceWithCache(t, cfg, stub, NewCacheService(time.Hour, time.Hour, 100, 0, 0, 0, 0, true))
+}
+
+// newStubbedScraperServiceWithCache is newStubbedScraperService reading and filling cache
+func newStubbedScraperServiceWithCache(t *testing.T, cfg models.ScraperConfig, stub *upstreamStub, cache *CacheService) *ScraperService {
+	t.Helper()
+	previous := http.DefaultTransport
+	http.DefaultTransport = stub
+	t.Cleanup(func() { http.DefaultTransport = previous })
+	return NewScraperService(false, cfg, cache, nil, nil, nil)
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+// historyFlushInterval bounds how long a record may sit in the write buffer
+const historyFlushInterval = time.Second
+
+// ScrapeRecord is one line of the scrape history log
+type ScrapeRecord struct {
+	Time        models.Timestamp `json:"time"`
+	Publication string           `json:"publication"`
+	Year        int              `json:"year,omitempty"`
+	Date        string           `json:"date,omitempty"`
+	Edition     string           `json:"edition,omitempty"`
+	Outcome     string           `json:"outcome"`
+	DurationMS  int64            `json:"duration_ms"`
+	Quality     int              `json:"quality"`
+	Paragraphs  int              `json:"paragraphs"`
+	URL         string           `json:"url,omitempty"`
+	Error       string           `json:"error,omitempty"`
+}
+
+// HistoryStore appends scrape records to a JSON lines file from a background goroutine,
+// so recording never blocks a request. A nil store records nothing.
+type HistoryStore struct {
+	records chan ScrapeRecord
+	done    chan struct{}
+	dropped atomic.Int64
+	mutex   sync.RWMutex
+	closed  bool
+}
+
+// NewHistoryStore opens path for appending and starts the writer. Records are queued in a
+// buffer of bufferSize; when it is full new records are dropped rather than waited for.
+func NewHistoryStore(path string, bufferSize int) (*HistoryStore, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open scrape history %s: %w", path, err)
+	}
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+
+	store := &HistoryStore{
+		records: make(chan ScrapeRecord, bufferSize),
+		done:    make(chan struct{}),
+	}
+	go store.run(file)
+	return store, nil
+}
+
+// Record queues record for writing
+func (h *HistoryStore) Record(record ScrapeRecord) {
+	if h == nil {
+		return
+	}
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	if h.closed {
+		return
+	}
+	select {
+	case h.records <- record:
+	default:
+		if h.dropped.Add(1)%100 == 1 {
+			log.Printf("Scrape history buffer full, %d records dropped so far", h.dropped.Load())
+		}
+	}
+}
+
+// Close writes out queued records and closes the file. Later records are discarded.
+func (h *HistoryStore) Close() {
+	if h == nil {
+		return
+	}
+	h.mutex.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.records)
+	}
+	h.mutex.Unlock()
+	<-h.done
+}
+
+func (h *HistoryStore) run(file *os.File) {
+	defer close(h.done)
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	ticker := time.NewTicker(historyFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case record, ok := <-h.records:
+			if !ok {
+				if err := writer.Flush(); err != nil {
+					log.Printf("Scrape history flush failed: %v", err)
+				}
+				return
+			}
+			if err := encoder.Encode(record); err != nil {
+				log.Printf("Scrape history write failed: %v", err)
+			}
+		case <-ticker.C:
+			if err := writer.Flush(); err != nil {
+				log.Printf("Scrape history flush failed: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+// defaultMaintenanceMessage is shown when maintenance is enabled without a message
+const defaultMaintenanceMessage = "The service is temporarily down for maintenance. Please try again later."
+
+// MaintenanceStatus describes the current maintenance mode state
+type MaintenanceStatus struct {
+	Enabled    bool              `json:"enabled"`
+	Message    string            `json:"message,omitempty"`
+	RetryAfter time.Duration     `json:"-"`
+	Since      *models.Timestamp `json:"since,omitempty"`
+}
+
+// MaintenanceService holds the runtime maintenance mode flag
+type MaintenanceService struct {
+	mutex      sync.RWMutex
+	status     MaintenanceStatus
+	retryAfter time.Duration
+}
+
+// NewMaintenanceService creates a new maintenance service, starting enabled when configured so
+func NewMaintenanceService(enabled bool, retryAfter time.Duration) *MaintenanceService {
+	service := &MaintenanceService{retryAfter: retryAfter}
+	service.Set(enabled, "")
+	return service
+}
+
+// Set turns maintenance mode on or off. An empty message uses the default text.
+func (m *MaintenanceService) Set(enabled bool, message string) MaintenanceStatus {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !enabled {
+		m.status = MaintenanceStatus{}
+		return m.status
+	}
+
+	if message == "" {
+		message = defaultMaintenanceMessage
+	}
+	since := m.status.Since
+	if !m.status.Enabled {
+		now := models.Now()
+		since = &now
+	}
+	m.status = MaintenanceStatus{
+		Enabled:    true,
+		Message:    message,
+		RetryAfter: m.retryAfter,
+		Since:      since,
+	}
+	return m.status
+}
+
+// Status returns the current maintenance mode state
+func (m *MaintenanceService) Status() MaintenanceStatus {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.status
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+// Scrape outcome labels. Every call to ScrapeContent records exactly one of these, so they
+// add up to the content requests served. A background refresh-ahead is recorded as
+// refresh_ahead whatever its result, since the request that triggered it was already
+// counted as a cache hit.
+const (
+	OutcomeCacheHit         = "cache_hit"
+	OutcomeNegativeCacheHit = "negative_cache_hit"
+	OutcomeFresh            = "fresh"
+	OutcomePrintFallback    = "print_fallback"
+	OutcomeLowQuality       = "low_quality"
+	OutcomeNotFound         = "not_found"
+	OutcomeParseFailure     = "parse_failure"
+	OutcomeFailed           = "failed"
+	OutcomeQuotaExceeded    = "quota_exceeded"
+	OutcomeQueueTimeout     = "queue_timeout"
+	OutcomeClientBusy       = "client_busy"
+	OutcomeShared           = "shared"
+	OutcomeNotModified      = "not_modified"
+	OutcomeInvalidTarget    = "invalid_target"
+	OutcomeRefreshAhead     = "refresh_ahead"
+)
+
+// scrapeOutcomes lists all outcome labels in reporting order
+var scrapeOutcomes = []string{
+	OutcomeCacheHit,
+	OutcomeNegativeCacheHit,
+	OutcomeFresh,
+	OutcomePrintFallback,
+	OutcomeLowQuality,
+	OutcomeNotFound,
+	OutcomeParseFailure,
+	OutcomeFailed,
+	OutcomeQuotaExceeded,
+	OutcomeQueueTimeout,
+	OutcomeClientBusy,
+	OutcomeShared,
+	OutcomeNotModified,
+	OutcomeInvalidTarget,
+	OutcomeRefreshAhead,
+}
+
+// labelCounters holds concurrency-safe counters for a fixed set of labels
+type labelCounters struct {
+	counts map[string]*atomic.Int64
+}
+
+func newLabelCounters(labels []string) *labelCounters {
+	counts := make(map[string]*atomic.Int64, len(labels))
+	for _, label := range labels {
+		counts[label] = new(atomic.Int64)
+	}
+	return &labelCounters{counts: counts}
+}
+
+// inc counts one occurrence of label; labels outside the set are ignored
+func (o *labelCounters) inc(label string) {
+	if count, ok := o.counts[label]; ok {
+		count.Add(1)
+	}
+}
+
+func (o *labelCounters) snapshot() map[string]int64 {
+	snapshot := make(map[string]int64, len(o.counts))
+	for label, count := range o.counts {
+		snapshot[label] = count.Load()
+	}
+	return snapshot
+}
+
+// registerCounters exposes counters to Prometheus as one series of name per label value
+func registerCounters(registerer prometheus.Registerer, counters *labelCounters, labels []string, name, help, labelName string) error {
+	for _, label := range labels {
+		count := counters.counts[label]
+		counter := prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        name,
+			Help:        help,
+			ConstLabels: prometheus.Labels{labelName: label},
+		}, func() float64 {
+			return float64(count.Load())
+		})
+		if err := registerer.Register(counter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterScraperMetrics exposes the scraper service counters to Prometheus
+func RegisterScraperMetrics(registerer prometheus.Registerer, s *ScraperService) error {
+	if err := registerCounters(registerer, s.outcomes, scrapeOutcomes, "sabda_scrape_outcomes_total", "Number of content requests by scrape outcome.", "outcome"); err != nil {
+		return err
+	}
+	return registerCounters(registerer, s.methods, scraper.ParagraphSources, "sabda_extraction_methods_total", "Number of fresh scrapes by how the extractor found the paragraphs.", "method")
+}
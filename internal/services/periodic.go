@@ -0,0 +1,30 @@
+package services
+
+import (
+	"math/rand"
+	"time"
+)
+
+// runPeriodic calls fn after every interval plus a random jitter of up to the given amount,
+// so that independent cleanup loops drift apart instead of contending for locks at the same instant.
+// A non-positive interval disables the loop.
+func runPeriodic(interval, jitter time.Duration, fn func()) {
+	if interval <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(nextDelay(interval, jitter))
+	defer timer.Stop()
+
+	for range timer.C {
+		fn()
+		timer.Reset(nextDelay(interval, jitter))
+	}
+}
+
+func nextDelay(interval, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return interval
+	}
+	return interval + time.Duration(rand.Int63n(int64(jitter)))
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"strings"
+)
+
+// maskedIPv6Bits is how much of an IPv6 address survives truncation: the /48 site prefix
+const maskedIPv6Bits = 48
+
+// unparsedIP stands in for a client address that is not an IP and so cannot be truncated
+const unparsedIP = "masked"
+
+// IPMasker hides client IPs before they are logged or returned. Without a salt it truncates
+// addresses, zeroing the last IPv4 octet and all but the /48 of an IPv6 address; with one it
+// replaces them with a salted hash, which still tells clients apart. A nil masker leaves IPs
+// unchanged.
+type IPMasker struct {
+	salt string
+}
+
+// NewIPMasker returns a masker, or nil when masking is disabled
+func NewIPMasker(enabled bool, salt string) *IPMasker {
+	if !enabled {
+		return nil
+	}
+	return &IPMasker{salt: salt}
+}
+
+// Mask returns ip masked for logs and responses
+func (m *IPMasker) Mask(ip string) string {
+	if m == nil || ip == "" {
+		return ip
+	}
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	if m.salt != "" {
+		sum := sha256.Sum256([]byte(m.salt + strings.ToLower(ip)))
+		return hex.EncodeToString(sum[:8])
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return unparsedIP
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return parsed.Mask(net.CIDRMask(maskedIPv6Bits, 128)).String()
+}
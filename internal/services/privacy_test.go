@@ -0,0 +1,32 @@
+package services
+
+import "testing"
+
+func TestIPMaskerMask(t *testing.T) {
+	tests := []struct {
+		name   string
+		masker *IPMasker
+		ip     string
+		want   string
+	}{
+		{"disabled", NewIPMasker(false, ""), "203.0.113.77", "203.0.113.77"},
+		{"IPv4", NewIPMasker(true, ""), "203.0.113.77", "203.0.113.0"},
+		{"IPv4 with port", NewIPMasker(true, ""), "203.0.113.77:5123", "203.0.113.0"},
+		{"IPv6", NewIPMasker(true, ""), "2001:db8:1:2:3:4:5:6", "2001:db8:1::"},
+		{"not an IP", NewIPMasker(true, ""), "unknown", unparsedIP},
+	}
+	for _, tt := range tests {
+		if got := tt.masker.Mask(tt.ip); got != tt.want {
+			t.Errorf("%s: Mask(%q) = %q, want %q", tt.name, tt.ip, got, tt.want)
+		}
+	}
+
+	salted := NewIPMasker(true, "salt")
+	hashed := salted.Mask("203.0.113.77")
+	if len(hashed) != 16 || hashed == "203.0.113.77" {
+		t.Errorf("salted Mask() = %q, want a 16-character hash", hashed)
+	}
+	if salted.Mask("203.0.113.77") != hashed || salted.Mask("203.0.113.78") == hashed {
+		t.Errorf("salted Mask() must be stable per IP and tell IPs apart")
+	}
+}
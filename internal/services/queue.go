@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrScrapeBusy is returned when no upstream scrape slot frees up within the queue timeout
+var ErrScrapeBusy = errors.New("all scrape slots busy")
+
+// QueueStatus reports the state of the upstream scrape queue
+type QueueStatus struct {
+	Unlimited      bool    `json:"unlimited"`
+	Capacity       int     `json:"capacity"`
+	InFlight       int     `json:"in_flight"`
+	Waiting        int64   `json:"waiting"`
+	TimeoutSeconds float64 `json:"timeout_seconds"`
+}
+
+// scrapeQueue bounds concurrent upstream scrapes. Requests wait up to timeout for a slot
+// so short bursts are smoothed out instead of rejected.
+type scrapeQueue struct {
+	slots   chan struct{}
+	timeout time.Duration
+	waiting atomic.Int64
+}
+
+// newScrapeQueue creates a queue with capacity slots; a non-positive capacity never blocks
+func newScrapeQueue(capacity int, timeout time.Duration) *scrapeQueue {
+	queue := &scrapeQueue{timeout: timeout}
+	if capacity > 0 {
+		queue.slots = make(chan struct{}, capacity)
+	}
+	return queue
+}
+
+// acquire takes a slot, waiting up to the queue timeout. It returns ErrScrapeBusy on timeout
+// and the context error if ctx ends first.
+func (q *scrapeQueue) acquire(ctx context.Context) error {
+	if q.slots == nil {
+		return nil
+	}
+
+	select {
+	case q.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	q.waiting.Add(1)
+	defer q.waiting.Add(-1)
+
+	timer := time.NewTimer(q.timeout)
+	defer timer.Stop()
+
+	select {
+	case q.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrScrapeBusy
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *scrapeQueue) release() {
+	if q.slots != nil {
+		<-q.slots
+	}
+}
+
+func (q *scrapeQueue) status() QueueStatus {
+	return QueueStatus{
+		Unlimited:      q.slots == nil,
+		Capacity:       cap(q.slots),
+		InFlight:       len(q.slots),
+		Waiting:        q.waiting.Load(),
+		TimeoutSeconds: q.timeout.Seconds(),
+	}
+}
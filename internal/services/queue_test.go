@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScrapeQueueWaitsForSlot(t *testing.T) {
+	q := newScrapeQueue(1, time.Second)
+	if err := q.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	acquired := make(chan error)
+	go func() { acquired <- q.acquire(context.Background()) }()
+	// The queued request is reported while it waits
+	for deadline := time.Now().Add(time.Second); q.status().Waiting != 1; {
+		if time.Now().After(deadline) {
+			t.Fatalf("waiting = %d, want 1", q.status().Waiting)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	q.release()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Errorf("queued acquire() error = %v, want a slot", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued request did not proceed after a slot was released")
+	}
+	if status := q.status(); status.Waiting != 0 || status.InFlight != 1 {
+		t.Errorf("waiting = %d, in flight = %d; want 0, 1", status.Waiting, status.InFlight)
+	}
+}
+
+func TestScrapeQueueTimesOut(t *testing.T) {
+	q := newScrapeQueue(1, 20*time.Millisecond)
+	if err := q.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	if err := q.acquire(context.Background()); !errors.Is(err, ErrScrapeBusy) {
+		t.Errorf("acquire() on a full queue error = %v, want ErrScrapeBusy", err)
+	}
+	if waiting := q.status().Waiting; waiting != 0 {
+		t.Errorf("waiting = %d after timeout, want 0", waiting)
+	}
+}
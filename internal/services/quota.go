@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+// ErrQuotaExceeded is returned when a cache miss would need an upstream scrape after the daily quota is spent
+var ErrQuotaExceeded = errors.New("daily scrape quota exhausted")
+
+// QuotaStatus reports usage of the daily upstream scrape quota
+type QuotaStatus struct {
+	Unlimited bool             `json:"unlimited"`
+	Limit     int              `json:"limit"`
+	Used      int              `json:"used"`
+	Remaining int              `json:"remaining"`
+	ResetsAt  models.Timestamp `json:"resets_at"`
+}
+
+// dailyQuota counts upstream scrapes per Jakarta calendar day
+type dailyQuota struct {
+	mutex sync.Mutex
+	limit int
+	day   string
+	used  int
+}
+
+func newDailyQuota(limit int) *dailyQuota {
+	return &dailyQuota{limit: limit}
+}
+
+// take reserves one upstream scrape, reporting false once today's quota is spent.
+// A non-positive limit never runs out.
+func (q *dailyQuota) take() bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.rollover(time.Now())
+	if q.limit > 0 && q.used >= q.limit {
+		return false
+	}
+	q.used++
+	return true
+}
+
+func (q *dailyQuota) status() QuotaStatus {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	now := time.Now()
+	q.rollover(now)
+
+	status := QuotaStatus{
+		Unlimited: q.limit <= 0,
+		Limit:     q.limit,
+		Used:      q.used,
+		ResetsAt:  models.NewTimestamp(nextJakartaMidnight(now)),
+	}
+	if !status.Unlimited {
+		status.Remaining = q.limit - q.used
+	}
+	return status
+}
+
+// rollover resets the counter when the Jakarta date has changed since the last scrape
+func (q *dailyQuota) rollover(now time.Time) {
+	day := now.In(scraper.Jakarta).Format("2006-01-02")
+	if day != q.day {
+		q.day = day
+		q.used = 0
+	}
+}
+
+func nextJakartaMidnight(now time.Time) time.Time {
+	local := now.In(scraper.Jakarta)
+	return time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, scraper.Jakarta)
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDailyQuotaResetsAtJakartaMidnight(t *testing.T) {
+	// Jakarta is UTC+7, so its midnight falls at 17:00 UTC
+	beforeMidnight := time.Date(2025, 9, 1, 16, 59, 59, 0, time.UTC)
+	afterMidnight := time.Date(2025, 9, 1, 17, 0, 0, 0, time.UTC)
+
+	q := newDailyQuota(2)
+	q.rollover(beforeMidnight.Add(-16 * time.Hour))
+	q.used = 2
+	q.rollover(beforeMidnight)
+	if q.used != 2 {
+		t.Errorf("used = %d one second before Jakarta midnight, want 2", q.used)
+	}
+	q.rollover(afterMidnight)
+	if q.used != 0 {
+		t.Errorf("used = %d at Jakarta midnight, want 0", q.used)
+	}
+
+	if got := nextJakartaMidnight(beforeMidnight); !got.Equal(afterMidnight) {
+		t.Errorf("nextJakartaMidnight(%v) = %v, want %v", beforeMidnight, got, afterMidnight)
+	}
+	if got, want := nextJakartaMidnight(afterMidnight), afterMidnight.Add(24*time.Hour); !got.Equal(want) {
+		t.Errorf("nextJakartaMidnight(%v) = %v, want %v", afterMidnight, got, want)
+	}
+}
+
+func TestDailyQuotaTake(t *testing.T) {
+	q := newDailyQuota(2)
+	for i := 0; i < 2; i++ {
+		if !q.take() {
+			t.Fatalf("take %d refused within the quota", i+1)
+		}
+	}
+	if q.take() {
+		t.Error("take allowed beyond the quota")
+	}
+	if status := q.status(); status.Used != 2 || status.Remaining != 0 || status.Unlimited {
+		t.Errorf("status = %+v, want 2 used and none remaining", status)
+	}
+
+	unlimited := newDailyQuota(0)
+	for i := 0; i < 5; i++ {
+		if !unlimited.take() {
+			t.Fatal("unlimited quota refused a scrape")
+		}
+	}
+	if status := unlimited.status(); !status.Unlimited || status.Used != 5 {
+		t.Errorf("status = %+v, want unlimited with 5 used", status)
+	}
+}
@@ -1,30 +1,62 @@
 package services
 
 import (
+	"container/list"
+	"log"
 	"sync"
 	"time"
 
 	"github.com/pranahonk/sabda-scraper-go/internal/models"
 )
 
-// RateLimitService handles rate limiting
+// RateLimiter limits requests per client. Implementations may keep their state per process
+// or share it between replicas.
+type RateLimiter interface {
+	// IsAllowed records a request from clientIP and reports whether it is within the limit
+	IsAllowed(clientIP string) bool
+	// GetRequestCount returns the requests counted for clientIP in the current window
+	GetRequestCount(clientIP string) int
+	// Reset forgets the requests counted for clientIP
+	Reset(clientIP string)
+	// Clear forgets all counted requests
+	Clear()
+	// UpdateLimit changes the requests allowed per window, taking effect on the next request
+	UpdateLimit(maxRequests int)
+	// Limit returns the requests allowed per window
+	Limit() int
+	// RetryAfter returns how long until clientIP may make another request, zero when it may now
+	RetryAfter(clientIP string) time.Duration
+}
+
+var _ RateLimiter = (*RateLimitService)(nil)
+
+// RateLimitService handles rate limiting in process memory
 type RateLimitService struct {
-	clients    map[string]*models.RateLimitInfo
+	// clients maps each tracked IP to its element in recent, whose value is the *models.RateLimitInfo
+	clients map[string]*list.Element
+	// recent orders tracked clients from most to least recently active
+	recent     *list.List
 	mutex      sync.RWMutex
 	maxReqs    int
+	maxClients int
 	window     time.Duration
+	evicted    int
 }
 
-// NewRateLimitService creates a new rate limiting service
-func NewRateLimitService(maxRequestsPerMinute int, windowDuration time.Duration) *RateLimitService {
+// NewRateLimitService creates a new rate limiting service tracking at most maxClients client IPs;
+// beyond that the least recently active client is forgotten. A non-positive maxClients tracks
+// every client. A non-positive cleanupInterval disables background pruning of idle clients.
+func NewRateLimitService(maxRequestsPerMinute, maxClients int, windowDuration, cleanupInterval, cleanupJitter time.Duration) *RateLimitService {
 	service := &RateLimitService{
-		clients: make(map[string]*models.RateLimitInfo),
-		maxReqs: maxRequestsPerMinute,
-		window:  windowDuration,
+		clients:    make(map[string]*list.Element),
+		recent:     list.New(),
+		maxReqs:    maxRequestsPerMinute,
+		maxClients: maxClients,
+		window:     windowDuration,
 	}
 
 	// Start cleanup goroutine
-	go service.cleanup()
+	go runPeriodic(cleanupInterval, cleanupJitter, service.cleanup)
 
 	return service
 }
@@ -35,15 +67,23 @@ func (r *RateLimitService) IsAllowed(clientIP string) bool {
 	defer r.mutex.Unlock()
 
 	now := time.Now()
-	
+
 	// Get or create client info
-	client, exists := r.clients[clientIP]
-	if !exists {
+	var client *models.RateLimitInfo
+	if element, exists := r.clients[clientIP]; exists {
+		r.recent.MoveToFront(element)
+		client = element.Value.(*models.RateLimitInfo)
+	} else {
+		// Bound memory under floods of unique IPs by forgetting the least recently active client
+		if r.maxClients > 0 && len(r.clients) >= r.maxClients {
+			r.remove(r.recent.Back())
+			r.evicted++
+		}
 		client = &models.RateLimitInfo{
-			ClientIP:  clientIP,
-			Requests:  make([]time.Time, 0),
+			ClientIP: clientIP,
+			Requests: make([]time.Time, 0),
 		}
-		r.clients[clientIP] = client
+		r.clients[clientIP] = r.recent.PushFront(client)
 	}
 
 	// Clean old requests outside the window
@@ -65,24 +105,66 @@ func (r *RateLimitService) IsAllowed(clientIP string) bool {
 	return true
 }
 
+// UpdateLimit changes the requests allowed per window. Requests already counted are kept.
+func (r *RateLimitService) UpdateLimit(maxRequests int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.maxReqs = maxRequests
+}
+
+// Limit returns the requests allowed per window
+func (r *RateLimitService) Limit() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.maxReqs
+}
+
+// RetryAfter returns how long until enough of the client's counted requests leave the window
+// for another to be allowed. A limit of zero never allows one; the full window is returned.
+func (r *RateLimitService) RetryAfter(clientIP string) time.Duration {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	element, exists := r.clients[clientIP]
+	if !exists {
+		return 0
+	}
+	if r.maxReqs <= 0 {
+		return r.window
+	}
+
+	now := time.Now()
+	var counted []time.Time
+	for _, reqTime := range element.Value.(*models.RateLimitInfo).Requests {
+		if now.Sub(reqTime) < r.window {
+			counted = append(counted, reqTime)
+		}
+	}
+	if len(counted) < r.maxReqs {
+		return 0
+	}
+	// Requests are recorded in order, so this one leaving the window frees a slot
+	return counted[len(counted)-r.maxReqs].Add(r.window).Sub(now)
+}
+
 // GetRequestCount returns the current request count for a client
 func (r *RateLimitService) GetRequestCount(clientIP string) int {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	client, exists := r.clients[clientIP]
+	element, exists := r.clients[clientIP]
 	if !exists {
 		return 0
 	}
 
 	now := time.Now()
 	count := 0
-	for _, reqTime := range client.Requests {
+	for _, reqTime := range element.Value.(*models.RateLimitInfo).Requests {
 		if now.Sub(reqTime) < r.window {
 			count++
 		}
 	}
-	
+
 	return count
 }
 
@@ -91,7 +173,9 @@ func (r *RateLimitService) Reset(clientIP string) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	delete(r.clients, clientIP)
+	if element, exists := r.clients[clientIP]; exists {
+		r.remove(element)
+	}
 }
 
 // Clear removes all rate limit data
@@ -99,37 +183,42 @@ func (r *RateLimitService) Clear() {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	r.clients = make(map[string]*models.RateLimitInfo)
+	r.clients = make(map[string]*list.Element)
+	r.recent.Init()
+}
+
+// remove stops tracking the client at element
+func (r *RateLimitService) remove(element *list.Element) {
+	client := r.recent.Remove(element).(*models.RateLimitInfo)
+	delete(r.clients, client.ClientIP)
 }
 
 func (r *RateLimitService) cleanup() {
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			r.mutex.Lock()
-			now := time.Now()
-			
-			for clientIP, client := range r.clients {
-				// Clean old requests
-				var validRequests []time.Time
-				for _, reqTime := range client.Requests {
-					if now.Sub(reqTime) < r.window {
-						validRequests = append(validRequests, reqTime)
-					}
-				}
-				
-				if len(validRequests) == 0 {
-					// Remove client if no recent requests
-					delete(r.clients, clientIP)
-				} else {
-					client.Requests = validRequests
-				}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+
+	if r.evicted > 0 {
+		log.Printf("Rate limiter evicted %d clients at the %d-client limit since the last cleanup", r.evicted, r.maxClients)
+		r.evicted = 0
+	}
+
+	for _, element := range r.clients {
+		client := element.Value.(*models.RateLimitInfo)
+		// Clean old requests
+		var validRequests []time.Time
+		for _, reqTime := range client.Requests {
+			if now.Sub(reqTime) < r.window {
+				validRequests = append(validRequests, reqTime)
 			}
-			
-			r.mutex.Unlock()
+		}
+
+		if len(validRequests) == 0 {
+			// Remove client if no recent requests
+			r.remove(element)
+		} else {
+			client.Requests = validRequests
 		}
 	}
-}
\ No newline at end of file
+}
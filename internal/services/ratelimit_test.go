@@ -0,0 +1,37 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterEvictsLeastRecentClientAtCap(t *testing.T) {
+	r := NewRateLimitService(10, 3, time.Minute, 0, 0)
+	r.IsAllowed("10.0.0.1")
+	for i := 0; i < 1000; i++ {
+		r.IsAllowed(fmt.Sprintf("192.0.2.%d", i))
+		// Keeps the first client the most recently active throughout the flood
+		r.IsAllowed("10.0.0.1")
+	}
+	if len(r.clients) != 3 || r.recent.Len() != 3 {
+		t.Fatalf("tracking %d clients (%d in the recency list), want the cap of 3", len(r.clients), r.recent.Len())
+	}
+	if got := r.GetRequestCount("10.0.0.1"); got != 10 {
+		t.Errorf("active client count = %d, want its 10 requests kept", got)
+	}
+	if got := r.GetRequestCount("192.0.2.0"); got != 0 {
+		t.Errorf("least recent client count = %d, want it evicted", got)
+	}
+	if got := r.GetRequestCount("192.0.2.999"); got != 1 {
+		t.Errorf("newest client count = %d, want 1", got)
+	}
+
+	unlimited := NewRateLimitService(10, 0, time.Minute, 0, 0)
+	for i := 0; i < 1000; i++ {
+		unlimited.IsAllowed(fmt.Sprintf("192.0.2.%d", i))
+	}
+	if len(unlimited.clients) != 1000 {
+		t.Errorf("tracking %d clients with no cap, want all 1000", len(unlimited.clients))
+	}
+}
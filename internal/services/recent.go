@@ -0,0 +1,59 @@
+package services
+
+import "sync"
+
+// RecentScrapes keeps the latest upstream scrapes in memory for diagnostics. It is a
+// fixed-size circular buffer allocated up front, so memory stays constant however many
+// scrapes are recorded; the oldest record is overwritten once it is full. A nil buffer
+// records nothing.
+type RecentScrapes struct {
+	mutex   sync.Mutex
+	records []ScrapeRecord
+	next    int
+	count   int
+}
+
+// NewRecentScrapes returns a buffer of the last capacity scrapes, or nil when capacity is
+// not positive
+func NewRecentScrapes(capacity int) *RecentScrapes {
+	if capacity <= 0 {
+		return nil
+	}
+	return &RecentScrapes{records: make([]ScrapeRecord, capacity)}
+}
+
+// Add records a scrape, overwriting the oldest when the buffer is full
+func (r *RecentScrapes) Add(record ScrapeRecord) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.records[r.next] = record
+	r.next = (r.next + 1) % len(r.records)
+	if r.count < len(r.records) {
+		r.count++
+	}
+}
+
+// Snapshot returns the recorded scrapes, newest first
+func (r *RecentScrapes) Snapshot() []ScrapeRecord {
+	if r == nil {
+		return []ScrapeRecord{}
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	snapshot := make([]ScrapeRecord, r.count)
+	for i := range snapshot {
+		snapshot[i] = r.records[(r.next-1-i+len(r.records))%len(r.records)]
+	}
+	return snapshot
+}
+
+// Capacity returns how many scrapes the buffer keeps, 0 when disabled
+func (r *RecentScrapes) Capacity() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.records)
+}
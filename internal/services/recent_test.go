@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+func TestRecentScrapesKeepsNewestFirst(t *testing.T) {
+	r := NewRecentScrapes(3)
+	for _, date := range []string{"0901", "0902", "0903", "0904"} {
+		r.Add(ScrapeRecord{Date: date})
+	}
+
+	snapshot := r.Snapshot()
+	want := []string{"0904", "0903", "0902"}
+	if len(snapshot) != len(want) {
+		t.Fatalf("kept %d records, want %d", len(snapshot), len(want))
+	}
+	for i, record := range snapshot {
+		if record.Date != want[i] {
+			t.Errorf("record %d = %s, want %s", i, record.Date, want[i])
+		}
+	}
+	if r.Capacity() != 3 {
+		t.Errorf("Capacity() = %d, want 3", r.Capacity())
+	}
+
+	var disabled *RecentScrapes = NewRecentScrapes(0)
+	disabled.Add(ScrapeRecord{Date: "0901"})
+	if len(disabled.Snapshot()) != 0 || disabled.Capacity() != 0 {
+		t.Error("disabled buffer recorded a scrape")
+	}
+}
+
+func TestRecentScrapesAddDoesNotAllocate(t *testing.T) {
+	r := NewRecentScrapes(10)
+	record := ScrapeRecord{Date: "0902", Outcome: OutcomeFresh}
+	if allocs := testing.AllocsPerRun(1000, func() { r.Add(record) }); allocs != 0 {
+		t.Errorf("Add allocated %.1f times per insert, want 0", allocs)
+	}
+}
+
+// BenchmarkRecentScrapesAdd shows that inserts into a full buffer allocate nothing, so
+// memory stays constant however many scrapes are recorded
+func BenchmarkRecentScrapesAdd(b *testing.B) {
+	r := NewRecentScrapes(100)
+	record := ScrapeRecord{
+		Time:        models.Now(),
+		Publication: "e-sh",
+		Year:        2025,
+		Date:        "0902",
+		Outcome:     OutcomeFresh,
+		URL:         "https://www.sabda.org/publikasi/e-sh/2025/09/02",
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Add(record)
+	}
+}
@@ -1,8 +1,12 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pranahonk/sabda-scraper-go/internal/models"
@@ -11,66 +15,550 @@ import (
 
 // ScraperService handles scraping operations with caching
 type ScraperService struct {
-	scraper *scraper.SABDAScraper
-	cache   *CacheService
+	scraper   *scraper.SABDAScraper
+	cache     *CacheService
+	outcomes  *labelCounters
+	methods   *labelCounters
+	quota     *dailyQuota
+	history   *HistoryStore
+	recent    *RecentScrapes
+	queue     *scrapeQueue
+	clients   *clientLimiter
+	scripture *ScriptureService
+	// flight shares in-flight upstream scrapes between requests for the same key; nil disables sharing
+	flight *scrapeFlight
+
+	// refreshing holds the cache keys with a refresh-ahead in flight
+	refreshing sync.Map
+
+	minInterval         time.Duration
+	backgroundTimeout   time.Duration
+	detectDuplicates    bool
+	conditionalScrapes  bool
+	reportCacheBackend  bool
+	enabledPublications []string
 }
 
-// NewScraperService creates a new scraper service
-func NewScraperService(debug bool, cache *CacheService) *ScraperService {
+// NewScraperService creates a new scraper service. Upstream scrapes are recorded to history
+// and recent, either of which may be nil to disable the scrape history log or the in-memory
+// buffer of recent scrapes. Devotionals without scripture text get it from scripture, which
+// may be nil to serve them as scraped.
+func NewScraperService(debug bool, cfg models.ScraperConfig, cache *CacheService, history *HistoryStore, recent *RecentScrapes, scripture *ScriptureService) *ScraperService {
+	var flight *scrapeFlight
+	if cfg.ShareInFlightScrapes {
+		flight = newScrapeFlight(cfg.BackgroundTimeout)
+	}
 	return &ScraperService{
-		scraper: scraper.New(debug),
-		cache:   cache,
+		scraper:   scraper.New(debug, cfg),
+		cache:     cache,
+		outcomes:  newLabelCounters(scrapeOutcomes),
+		methods:   newLabelCounters(scraper.ParagraphSources),
+		quota:     newDailyQuota(cfg.DailyQuota),
+		history:   history,
+		recent:    recent,
+		queue:     newScrapeQueue(cfg.MaxConcurrentScrapes, cfg.QueueTimeout),
+		clients:   newClientLimiter(cfg.MaxInFlightPerClient),
+		scripture: scripture,
+		flight:    flight,
+
+		minInterval:         cfg.MinScrapeInterval,
+		backgroundTimeout:   cfg.BackgroundTimeout,
+		detectDuplicates:    cfg.DetectDuplicates,
+		conditionalScrapes:  cfg.ConditionalScrapes,
+		reportCacheBackend:  cfg.ReportCacheBackend,
+		enabledPublications: resolveEnabledPublications(cfg.EnabledPublications),
 	}
 }
 
+// resolveEnabledPublications returns the sorted publication codes the API serves. Unknown codes
+// are ignored; an empty allowlist enables every supported publication.
+func resolveEnabledPublications(codes []string) []string {
+	if len(codes) == 0 {
+		return scraper.PublicationCodes()
+	}
+
+	var enabled []string
+	for _, code := range scraper.PublicationCodes() {
+		for _, allowed := range codes {
+			if strings.EqualFold(strings.TrimSpace(allowed), code) {
+				enabled = append(enabled, code)
+				break
+			}
+		}
+	}
+	for _, code := range codes {
+		if _, ok := scraper.LookupPublication(strings.TrimSpace(code)); !ok {
+			log.Printf("Ignoring unknown publication %q in enabled publications", code)
+		}
+	}
+	return enabled
+}
+
+// EnabledPublications returns the sorted codes of the publications this deployment serves
+func (s *ScraperService) EnabledPublications() []string {
+	return s.enabledPublications
+}
+
+// OutcomeCounts returns the number of requests recorded per scrape outcome
+func (s *ScraperService) OutcomeCounts() map[string]int64 {
+	return s.outcomes.snapshot()
+}
+
+// ExtractionMethodCounts returns the number of fresh scrapes per paragraph source
+func (s *ScraperService) ExtractionMethodCounts() map[string]int64 {
+	return s.methods.snapshot()
+}
+
+// RecentScrapes returns the buffered recent upstream scrapes, newest first, and how many the
+// buffer keeps
+func (s *ScraperService) RecentScrapes() ([]ScrapeRecord, int) {
+	return s.recent.Snapshot(), s.recent.Capacity()
+}
+
+// NegativeCacheTTL returns how long not-published results are cached; non-positive when disabled
+func (s *ScraperService) NegativeCacheTTL() time.Duration {
+	return s.cache.NegativeTTL()
+}
+
+// QueueStatus returns the state of the upstream scrape queue
+func (s *ScraperService) QueueStatus() QueueStatus {
+	return s.queue.status()
+}
+
+// QuotaStatus returns usage of the daily upstream scrape quota
+func (s *ScraperService) QuotaStatus() QuotaStatus {
+	return s.quota.status()
+}
+
+// ErrUpstream wraps scrape failures caused by sabda.org being unreachable or answering with an error
+var ErrUpstream = errors.New("upstream error")
+
+// Reasons reported in the metadata of a scrape that yielded no content
+const (
+	ReasonNotPublished  = "not_published"
+	ReasonParseFailure  = "parse_failure"
+	ReasonUpstreamError = "upstream_error"
+)
+
+// WithScrapeTimeout bounds the scrapes made with the returned context to timeout, covering
+// the wait for a scrape slot, politeness delays and every upstream request of the scrape.
+// A non-positive timeout leaves ctx without a deadline.
+func WithScrapeTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// ScrapeOptions adjusts how a single ScrapeContent call uses the cache
+type ScrapeOptions struct {
+	// BypassCache skips cached and negatively cached entries and refreshes the cache from upstream
+	BypassCache bool
+	// MaxAge, when positive, treats cached entries older than this as stale and refreshes them
+	MaxAge time.Duration
+}
+
 // ScrapeContent scrapes devotional content with caching
-func (s *ScraperService) ScrapeContent(year int, date string) (*models.APIResponse, error) {
+// Correlation identifiers carried by ctx are forwarded on upstream requests.
+func (s *ScraperService) ScrapeContent(ctx context.Context, target scraper.Target, opts ScrapeOptions) (*models.APIResponse, error) {
 	// Create cache key
-	formattedDate := fmt.Sprintf("%04s", date)
-	cacheKey := fmt.Sprintf("sabda_%d_%s", year, formattedDate)
+	cacheKey := target.CacheKey()
+	directURL, printURL, err := target.URLs()
+	if err != nil {
+		s.countOutcome(ctx, OutcomeInvalidTarget)
+		return &models.APIResponse{
+			Status:  "error",
+			Message: fmt.Sprintf("Invalid scrape target: %v", err),
+			Metadata: map[string]interface{}{
+				"error_type": "ValidationError",
+			},
+		}, err
+	}
 
 	// Check cache first
-	if cached, found := s.cache.Get(cacheKey); found {
+	cached, found := s.cache.GetItem(cacheKey)
+	stale := found && opts.MaxAge > 0 && time.Since(cached.Timestamp) > opts.MaxAge
+	// Entries scraped within the minimum interval are served even to forced refreshes,
+	// so rapid sequential requests cannot re-scrape the same key
+	recent := found && time.Since(cached.Timestamp) < s.minInterval
+	if found && ((!opts.BypassCache && !stale) || recent) {
+		if opts.BypassCache || stale {
+			log.Printf("Refresh of %s suppressed; scraped %v ago", cacheKey, time.Since(cached.Timestamp).Round(time.Second))
+		}
 		log.Printf("Cache hit for key: %s", cacheKey)
-		
+		s.countOutcome(ctx, OutcomeCacheHit)
+		if s.cache.DueForRefresh(cached) {
+			s.refreshAhead(target, cacheKey)
+		}
+		age, remaining := cacheTimings(cached.Timestamp, s.cache.TTL(), time.Now())
+		var backend string
+		if s.reportCacheBackend {
+			backend = s.cache.Backend()
+		}
+
 		return &models.APIResponse{
 			Status:  "success",
 			Message: "Content retrieved from cache",
-			Data:    cached,
+			Data:    s.withScriptureText(ctx, &cached.Content),
 			Metadata: models.ScrapingMetadata{
-				URL:       fmt.Sprintf("https://www.sabda.org/publikasi/e-sh/cetak/?tahun=%d&edisi=%s", year, formattedDate),
-				Source:    "SABDA.org",
-				Cached:    true,
-				ScrapedAt: time.Now(),
+				URL:                      printURL,
+				Source:                   "SABDA.org",
+				Publication:              target.Publication,
+				Cached:                   true,
+				CacheBackend:             backend,
+				ScrapedAt:                models.NewTimestamp(cached.Timestamp),
+				CacheAgeSeconds:          age,
+				CacheTTLRemainingSeconds: remaining,
+				FiltersApplied:           cached.Content.FiltersApplied,
+				FinalURL:                 cached.Content.FinalURL,
+				DuplicateOf:              cached.Content.DuplicateOf,
+				Warnings:                 cached.Content.Warnings,
 			},
 		}, nil
 	}
 
-	// Scrape content
-	content, err := s.scraper.ScrapeContent(year, date)
-	if err != nil {
+	// Known-unpublished dates are answered from the negative cache
+	if !opts.BypassCache && s.cache.IsNegative(cacheKey) {
+		log.Printf("Negative cache hit for key: %s", cacheKey)
+		s.countOutcome(ctx, OutcomeNegativeCacheHit)
+		return notFoundResponse(printURL, true), fmt.Errorf("negative cache hit for %s: %w", cacheKey, scraper.ErrNotFound)
+	}
+
+	// A client may only have so many requests waiting on upstream at once; cache hits above
+	// are never limited
+	if !s.clients.acquire(ctx) {
+		log.Printf("Refused scrape of %s; client has too many scrapes in flight", cacheKey)
+		s.countOutcome(ctx, OutcomeClientBusy)
 		return &models.APIResponse{
 			Status:  "error",
-			Message: fmt.Sprintf("Scraping failed: %v", err),
+			Message: "Too many concurrent scrapes from this client; wait for one to finish and retry",
 			Metadata: map[string]interface{}{
-				"url":        fmt.Sprintf("https://www.sabda.org/publikasi/e-sh/cetak/?tahun=%d&edisi=%s", year, formattedDate),
-				"error_type": "ScrapingException",
+				"url":        printURL,
+				"error_type": "ClientBusyError",
 			},
-		}, err
+		}, ErrClientBusy
 	}
+	defer s.clients.release(ctx)
 
-	// Cache the result
-	s.cache.Set(cacheKey, *content)
+	// Concurrent scrapes of the same key share one upstream call
+	var upstream upstreamResult
+	if s.flight != nil {
+		var shared bool
+		upstream, shared = s.flight.do(ctx, cacheKey, func(ctx context.Context) upstreamResult {
+			return s.scrapeUpstream(ctx, target, cacheKey, directURL, printURL)
+		})
+		if shared {
+			log.Printf("Joined in-flight scrape for key: %s", cacheKey)
+			s.countOutcome(ctx, OutcomeShared)
+		}
+	} else {
+		upstream = s.scrapeUpstream(ctx, target, cacheKey, directURL, printURL)
+	}
+	if upstream.err != nil {
+		if upstream.response == nil {
+			// A caller that gave up waiting on a shared scrape
+			return &models.APIResponse{
+				Status:  "error",
+				Message: fmt.Sprintf("Scraping failed: %v", upstream.err),
+				Metadata: map[string]interface{}{
+					"url":        printURL,
+					"error_type": "ScrapingException",
+					"reason":     ReasonUpstreamError,
+				},
+			}, upstream.err
+		}
+		return upstream.response, upstream.err
+	}
 
+	content := upstream.content
 	return &models.APIResponse{
 		Status:  "success",
 		Message: "Content scraped successfully",
-		Data:    content,
+		Data:    s.withScriptureText(ctx, content),
 		Metadata: models.ScrapingMetadata{
-			URL:       fmt.Sprintf("https://www.sabda.org/publikasi/e-sh/cetak/?tahun=%d&edisi=%s", year, formattedDate),
-			Source:    "SABDA.org",
-			Cached:    false,
-			ScrapedAt: time.Now(),
+			URL:                      printURL,
+			Source:                   "SABDA.org",
+			Publication:              target.Publication,
+			Cached:                   false,
+			CacheBypassed:            opts.BypassCache,
+			Refreshed:                stale,
+			ScrapedAt:                models.Now(),
+			CacheTTLRemainingSeconds: int64(s.cache.TTL().Seconds()),
+			FiltersApplied:           content.FiltersApplied,
+			FinalURL:                 content.FinalURL,
+			DuplicateOf:              content.DuplicateOf,
+			Warnings:                 content.Warnings,
 		},
 	}, nil
-}
\ No newline at end of file
+}
+
+// upstreamResult is the outcome of one upstream scrape: the content, or the error response
+// and error to return
+type upstreamResult struct {
+	content  *models.DevotionalContent
+	response *models.APIResponse
+	err      error
+}
+
+// scrapeUpstream scrapes target from sabda.org within the scrape queue and daily quota and
+// caches the outcome under cacheKey
+func (s *ScraperService) scrapeUpstream(ctx context.Context, target scraper.Target, cacheKey, directURL, printURL string) upstreamResult {
+	// Wait briefly for a scrape slot; cache hits above never queue
+	if err := s.queue.acquire(ctx); err != nil {
+		s.countOutcome(ctx, OutcomeQueueTimeout)
+		return upstreamResult{response: &models.APIResponse{
+			Status:  "error",
+			Message: "All scrape slots are busy; please retry shortly",
+			Metadata: map[string]interface{}{
+				"url":        printURL,
+				"error_type": "ScrapeBusyError",
+			},
+		}, err: err}
+	}
+	defer s.queue.release()
+
+	// Once the daily quota is spent only cached content is served
+	if !s.quota.take() {
+		s.countOutcome(ctx, OutcomeQuotaExceeded)
+		status := s.quota.status()
+		return upstreamResult{response: &models.APIResponse{
+			Status:  "error",
+			Message: "Daily scrape quota exhausted; only cached content is available until the quota resets",
+			Metadata: map[string]interface{}{
+				"url":        printURL,
+				"error_type": "QuotaExceededError",
+				"resets_at":  status.ResetsAt,
+			},
+		}, err: ErrQuotaExceeded}
+	}
+
+	// Re-scrapes of a cached issue, including an expired entry not yet cleaned up, only ask
+	// SABDA for the page if it changed since the entry was scraped
+	var since time.Time
+	previous, hasPrevious := s.cache.Peek(cacheKey)
+	if s.conditionalScrapes && hasPrevious {
+		since = previous.Timestamp
+	}
+
+	// Scrape content
+	started := time.Now()
+	result, err := s.scraper.ScrapeContentSince(ctx, target, since)
+	outcome := scrapeOutcome(result, err)
+	s.countOutcome(ctx, outcome)
+	record := scrapeRecord(target, outcome, started, result, err)
+	s.history.Record(record)
+	s.recent.Add(record)
+	if errors.Is(err, scraper.ErrNotModified) {
+		log.Printf("%s not modified upstream since %s; keeping cached content", cacheKey, since.Format(time.RFC3339))
+		content := previous.Content
+		s.cacheResult(cacheKey, content, started)
+		return upstreamResult{content: &content}
+	}
+	if errors.Is(err, scraper.ErrNotFound) {
+		s.cache.SetNegative(cacheKey)
+		return upstreamResult{response: notFoundResponse(printURL, false), err: err}
+	}
+	if errors.Is(err, scraper.ErrParseFailure) {
+		return upstreamResult{response: &models.APIResponse{
+			Status:  "error",
+			Message: "SABDA served a page but no devotional could be extracted from it",
+			Metadata: map[string]interface{}{
+				"url":        printURL,
+				"error_type": "ParseFailureError",
+				"reason":     ReasonParseFailure,
+			},
+		}, err: err}
+	}
+	if err != nil {
+		return upstreamResult{response: &models.APIResponse{
+			Status:  "error",
+			Message: fmt.Sprintf("Scraping failed: %v", err),
+			Metadata: map[string]interface{}{
+				"url":        printURL,
+				"error_type": "ScrapingException",
+				"reason":     ReasonUpstreamError,
+			},
+		}, err: fmt.Errorf("%w: %w", ErrUpstream, err)}
+	}
+
+	// Cache the result with the human-navigable permalink, even when the print page was scraped
+	content := result.Content
+	s.methods.inc(content.ParagraphSource)
+	content.SourceURL = directURL
+	if s.detectDuplicates {
+		content.DuplicateOf = s.duplicateOf(cacheKey, content.ContentHash)
+	}
+	s.cacheResult(cacheKey, *content, started)
+
+	return upstreamResult{content: content}
+}
+
+// cacheResult caches content scraped at scrapedAt, unless a concurrent scrape of the same
+// issue that started later has already cached its result
+func (s *ScraperService) cacheResult(cacheKey string, content models.DevotionalContent, scrapedAt time.Time) {
+	if !s.cache.Set(cacheKey, content, scrapedAt) {
+		log.Printf("Kept fresher cached entry for %s over a scrape started at %s", cacheKey, scrapedAt.Format(time.RFC3339Nano))
+	}
+}
+
+// duplicateOf identifies another cached issue with the same content hash, or returns nil
+func (s *ScraperService) duplicateOf(cacheKey, hash string) *models.DuplicateOf {
+	originalKey, found := s.cache.DuplicateOf(cacheKey, hash)
+	if !found {
+		return nil
+	}
+	original, ok := scraper.ParseCacheKey(originalKey)
+	if !ok {
+		return nil
+	}
+	log.Printf("Content of %s duplicates cached %s", cacheKey, originalKey)
+	return &models.DuplicateOf{
+		Publication: original.Publication,
+		Year:        original.Year,
+		Date:        original.Date,
+		Edition:     original.Edition,
+	}
+}
+
+// withScriptureText returns a copy of content with the passage for its scripture reference
+// fetched from the Bible API when the page had none. The cached devotional is left as
+// scraped, so a failed fetch is retried on the next request.
+func (s *ScraperService) withScriptureText(ctx context.Context, content *models.DevotionalContent) *models.DevotionalContent {
+	if s.scripture == nil || content.ScriptureText != "" || content.ScriptureReference == "" {
+		return content
+	}
+	text, err := s.scripture.Passage(ctx, content.ScriptureReference)
+	if err != nil {
+		log.Printf("Scripture text for %q not fetched: %v", content.ScriptureReference, err)
+		return content
+	}
+	withText := *content
+	withText.ScriptureText = text
+	return &withText
+}
+
+// cacheTimings returns the age in seconds of an entry stored at storedAt and the seconds left
+// before it expires under ttl, never negative
+func cacheTimings(storedAt time.Time, ttl time.Duration, now time.Time) (age, remaining int64) {
+	elapsed := now.Sub(storedAt)
+	age = int64(elapsed.Seconds())
+	if left := ttl - elapsed; left > 0 {
+		remaining = int64(left.Seconds())
+	}
+	return age, remaining
+}
+
+// refreshAheadKey marks the context of a background refresh-ahead scrape
+type refreshAheadKey struct{}
+
+// countOutcome records the outcome of a ScrapeContent call, or refresh_ahead for a background
+// refresh-ahead
+func (s *ScraperService) countOutcome(ctx context.Context, outcome string) {
+	if refresh, _ := ctx.Value(refreshAheadKey{}).(bool); refresh {
+		outcome = OutcomeRefreshAhead
+	}
+	s.outcomes.inc(outcome)
+}
+
+// refreshAhead re-scrapes a hot cache entry in the background before it expires.
+// At most one refresh per key runs at a time.
+func (s *ScraperService) refreshAhead(target scraper.Target, cacheKey string) {
+	if _, running := s.refreshing.LoadOrStore(cacheKey, struct{}{}); running {
+		return
+	}
+
+	go func() {
+		defer s.refreshing.Delete(cacheKey)
+		log.Printf("Refreshing %s ahead of expiry", cacheKey)
+		ctx, cancel := WithScrapeTimeout(context.WithValue(context.Background(), refreshAheadKey{}, true), s.backgroundTimeout)
+		defer cancel()
+		if _, err := s.ScrapeContent(ctx, target, ScrapeOptions{BypassCache: true}); err != nil {
+			log.Printf("Refresh-ahead of %s failed: %v", cacheKey, err)
+		}
+	}()
+}
+
+// FetchRaw fetches the unparsed upstream page for target, bypassing extraction and the cache.
+// Raw fetches count against the daily quota like any other upstream scrape.
+func (s *ScraperService) FetchRaw(ctx context.Context, target scraper.Target) (*scraper.RawPage, error) {
+	if err := s.queue.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer s.queue.release()
+
+	if !s.quota.take() {
+		return nil, ErrQuotaExceeded
+	}
+	return s.scraper.FetchRaw(ctx, target)
+}
+
+// ScrapeUncached scrapes target from sabda.org without reading or writing the cache, so the
+// result always comes from the extractor. It waits for a scrape slot like any other upstream
+// scrape but does not spend the daily quota meant for clients, and is left out of the outcome
+// counts and scrape history.
+func (s *ScraperService) ScrapeUncached(ctx context.Context, target scraper.Target) (*scraper.Result, error) {
+	if err := s.queue.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer s.queue.release()
+
+	return s.scraper.ScrapeContent(ctx, target)
+}
+
+// notFoundResponse builds the response for an issue that has not been published
+func notFoundResponse(url string, cached bool) *models.APIResponse {
+	return &models.APIResponse{
+		Status:  "error",
+		Message: "No devotional published for this date",
+		Metadata: map[string]interface{}{
+			"url":        url,
+			"error_type": "NotFoundError",
+			"reason":     ReasonNotPublished,
+			"cached":     cached,
+		},
+	}
+}
+
+// scrapeOutcome classifies an upstream scrape into exactly one outcome label
+func scrapeOutcome(result *scraper.Result, err error) string {
+	switch {
+	case errors.Is(err, scraper.ErrNotModified):
+		return OutcomeNotModified
+	case errors.Is(err, scraper.ErrNotFound):
+		return OutcomeNotFound
+	case errors.Is(err, scraper.ErrParseFailure):
+		return OutcomeParseFailure
+	case err != nil:
+		return OutcomeFailed
+	case result.LowQuality:
+		return OutcomeLowQuality
+	case result.UsedFallback:
+		return OutcomePrintFallback
+	default:
+		return OutcomeFresh
+	}
+}
+
+// scrapeRecord describes one upstream scrape for the history log
+func scrapeRecord(target scraper.Target, outcome string, started time.Time, result *scraper.Result, err error) ScrapeRecord {
+	record := ScrapeRecord{
+		Time:        models.NewTimestamp(started),
+		Publication: target.Publication,
+		Year:        target.Year,
+		Date:        target.Date,
+		Edition:     target.Edition,
+		Outcome:     outcome,
+		DurationMS:  time.Since(started).Milliseconds(),
+	}
+	if result != nil {
+		record.URL = result.URL
+		record.Quality = scraper.QualityScore(result.Content)
+		if result.Content != nil {
+			record.Paragraphs = result.Content.ParagraphCount
+		}
+	}
+	if err != nil && !errors.Is(err, scraper.ErrNotModified) {
+		record.Error = err.Error()
+	}
+	return record
+}
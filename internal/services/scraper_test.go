@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+func TestCacheTimings(t *testing.T) {
+	storedAt := time.Date(2025, 9, 2, 6, 0, 0, 0, time.UTC)
+	tests := []struct {
+		now            time.Time
+		age, remaining int64
+	}{
+		{storedAt, 0, 3600},
+		{storedAt.Add(5 * time.Minute), 300, 3300},
+		{storedAt.Add(2 * time.Hour), 7200, 0},
+	}
+	for _, tt := range tests {
+		age, remaining := cacheTimings(storedAt, time.Hour, tt.now)
+		if age != tt.age || remaining != tt.remaining {
+			t.Errorf("cacheTimings at %v = %d, %d; want %d, %d", tt.now, age, remaining, tt.age, tt.remaining)
+		}
+	}
+}
+
+func TestScrapeContentReportsCacheAge(t *testing.T) {
+	cache := NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+	s := NewScraperService(false, models.ScraperConfig{}, cache, nil, nil, nil)
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	cache.Set(target.CacheKey(), models.DevotionalContent{Title: "cached"}, time.Now().Add(-5*time.Minute))
+
+	result, err := s.ScrapeContent(context.Background(), target, ScrapeOptions{})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	metadata := result.Metadata.(models.ScrapingMetadata)
+	// Allow for a second ticking over while the request runs
+	if metadata.CacheAgeSeconds < 300 || metadata.CacheAgeSeconds > 301 {
+		t.Errorf("cache_age_seconds = %d, want 300", metadata.CacheAgeSeconds)
+	}
+	if metadata.CacheTTLRemainingSeconds < 3299 || metadata.CacheTTLRemainingSeconds > 3300 {
+		t.Errorf("cache_ttl_remaining_seconds = %d, want 3300", metadata.CacheTTLRemainingSeconds)
+	}
+}
+
+func TestOutcomeCountsAddUpToRequests(t *testing.T) {
+	cache := NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+	s := NewScraperService(false, models.ScraperConfig{}, cache, nil, nil, nil)
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	cache.Set(target.CacheKey(), models.DevotionalContent{Title: "cached"}, time.Now())
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := s.ScrapeContent(ctx, target, ScrapeOptions{}); err != nil {
+			t.Fatalf("ScrapeContent() error = %v", err)
+		}
+	}
+	invalid := scraper.Target{Publication: "nope", Year: 2025, Date: "0902"}
+	if _, err := s.ScrapeContent(ctx, invalid, ScrapeOptions{}); err == nil {
+		t.Fatal("ScrapeContent() of an unknown publication succeeded")
+	}
+	// A background refresh is counted under its own label, not as another request outcome
+	refresh := context.WithValue(ctx, refreshAheadKey{}, true)
+	if _, err := s.ScrapeContent(refresh, target, ScrapeOptions{}); err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+
+	counts := s.OutcomeCounts()
+	want := map[string]int64{OutcomeCacheHit: 2, OutcomeInvalidTarget: 1, OutcomeRefreshAhead: 1}
+	var total int64
+	for outcome, count := range counts {
+		total += count
+		if count != want[outcome] {
+			t.Errorf("%s = %d, want %d", outcome, count, want[outcome])
+		}
+	}
+	if requests := counts[OutcomeCacheHit] + counts[OutcomeInvalidTarget]; requests != 3 {
+		t.Errorf("request outcomes = %d, want 3", requests)
+	}
+	if total != 4 {
+		t.Errorf("total outcomes = %d, want 4", total)
+	}
+}
+
+func TestScrapeContentReportsCachedWarnings(t *testing.T) {
+	cache := NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+	s := NewScraperService(false, models.ScraperConfig{}, cache, nil, nil, nil)
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	warnings := []string{"served from print page", "low content"}
+	cache.Set(target.CacheKey(), models.DevotionalContent{Title: "cached", Warnings: warnings}, time.Now())
+
+	result, err := s.ScrapeContent(context.Background(), target, ScrapeOptions{})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	metadata := result.Metadata.(models.ScrapingMetadata)
+	if strings.Join(metadata.Warnings, "|") != strings.Join(warnings, "|") {
+		t.Errorf("warnings = %q, want %q", metadata.Warnings, warnings)
+	}
+}
+
+func TestScrapeContentRefreshesEntryOlderThanMaxAge(t *testing.T) {
+	stub := &upstreamStub{}
+	s := newStubbedScraperService(t, models.ScraperConfig{DisablePrintFallback: true}, stub)
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	s.cache.Set(target.CacheKey(), models.DevotionalContent{Title: "cached"}, time.Now().Add(-10*time.Minute))
+
+	// A demand looser than the entry's age is served from the cache
+	result, err := s.ScrapeContent(context.Background(), target, ScrapeOptions{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	if metadata := result.Metadata.(models.ScrapingMetadata); !metadata.Cached || metadata.Refreshed {
+		t.Errorf("cached = %v, refreshed = %v; want the cached copy", metadata.Cached, metadata.Refreshed)
+	}
+
+	result, err = s.ScrapeContent(context.Background(), target, ScrapeOptions{MaxAge: time.Minute})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	metadata := result.Metadata.(models.ScrapingMetadata)
+	if metadata.Cached || !metadata.Refreshed || metadata.CacheAgeSeconds != 0 {
+		t.Errorf("cached = %v, refreshed = %v, age = %d; want a fresh scrape", metadata.Cached, metadata.Refreshed, metadata.CacheAgeSeconds)
+	}
+	if got := stub.requests.Load(); got != 1 {
+		t.Errorf("upstream requests = %d, want 1", got)
+	}
+	if content := result.Data.(*models.DevotionalContent); content.Title == "cached" {
+		t.Errorf("served the stale cached copy")
+	}
+}
+
+func TestForcedRefreshesWithinMinIntervalScrapeOnce(t *testing.T) {
+	stub := &upstreamStub{}
+	s := newStubbedScraperService(t, models.ScraperConfig{DisablePrintFallback: true, MinScrapeInterval: time.Minute}, stub)
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.ScrapeContent(context.Background(), target, ScrapeOptions{BypassCache: true}); err != nil {
+			t.Fatalf("ScrapeContent() #%d error = %v", i+1, err)
+		}
+	}
+	if got := stub.requests.Load(); got != 1 {
+		t.Errorf("upstream requests = %d, want 1", got)
+	}
+}
+
+func TestSourceURLIsDirectPermalinkAfterPrintFallback(t *testing.T) {
+	stub := &upstreamStub{notFound: "/e-sh/2025/"}
+	s := newStubbedScraperService(t, models.ScraperConfig{}, stub)
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+
+	result, err := s.ScrapeContent(context.Background(), target, ScrapeOptions{})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	metadata := result.Metadata.(models.ScrapingMetadata)
+	if !strings.Contains(metadata.URL, "/cetak/") {
+		t.Fatalf("scraped URL = %s, want the print page", metadata.URL)
+	}
+	content := result.Data.(*models.DevotionalContent)
+	if want := "https://www.sabda.org/publikasi/e-sh/2025/09/02"; content.SourceURL != want {
+		t.Errorf("SourceURL = %s, want %s", content.SourceURL, want)
+	}
+}
+
+func TestHotEntryIsRefreshedAheadOfExpiry(t *testing.T) {
+	stub := &upstreamStub{delay: 100 * time.Millisecond}
+	cache := NewCacheService(time.Hour, time.Hour, 10, 0, 0, 10*time.Minute, 3, true)
+	s := newStubbedScraperServiceWithCache(t, models.ScraperConfig{DisablePrintFallback: true}, stub, cache)
+	hot := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	cold := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0903"}
+	storedAt := time.Now().Add(-55 * time.Minute)
+	cache.Set(hot.CacheKey(), models.DevotionalContent{Title: "hot"}, storedAt)
+	cache.Set(cold.CacheKey(), models.DevotionalContent{Title: "cold"}, storedAt)
+
+	ctx := context.Background()
+	if _, err := s.ScrapeContent(ctx, cold, ScrapeOptions{}); err != nil {
+		t.Fatalf("ScrapeContent(cold) error = %v", err)
+	}
+	for i := 0; i < 6; i++ {
+		result, err := s.ScrapeContent(ctx, hot, ScrapeOptions{})
+		if err != nil {
+			t.Fatalf("ScrapeContent(hot) error = %v", err)
+		}
+		if metadata := result.Metadata.(models.ScrapingMetadata); !metadata.Cached {
+			t.Errorf("read %d was not served from the cache", i+1)
+		}
+	}
+
+	for deadline := time.Now().Add(15 * time.Second); ; time.Sleep(50 * time.Millisecond) {
+		if item, _ := cache.Peek(hot.CacheKey()); item.Timestamp.After(storedAt) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("hot entry was not refreshed in the background")
+		}
+	}
+	// One refresh per key however many reads found it due, and none for the cold entry
+	if got := stub.requests.Load(); got != 1 {
+		t.Errorf("upstream requests = %d, want 1", got)
+	}
+	if item, _ := cache.Peek(cold.CacheKey()); !item.Timestamp.Equal(storedAt) {
+		t.Errorf("cold entry was refreshed")
+	}
+}
+
+func TestScrapeTimeoutFollowsCallerDeadline(t *testing.T) {
+	stub := &upstreamStub{delay: time.Second}
+	s := newStubbedScraperService(t, models.ScraperConfig{DisablePrintFallback: true}, stub)
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+
+	interactive, cancel := WithScrapeTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := s.ScrapeContent(interactive, target, ScrapeOptions{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("interactive ScrapeContent() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("interactive scrape took %s, want it to fail at its 300ms deadline", elapsed)
+	}
+
+	background, cancel := WithScrapeTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := s.ScrapeContent(background, target, ScrapeOptions{}); err != nil {
+		t.Errorf("background ScrapeContent() error = %v, want the slow page scraped", err)
+	}
+}
+
+func TestForcedRefreshIsConditionalOnCachedEntry(t *testing.T) {
+	stub := &upstreamStub{notModified: true}
+	cache := NewCacheService(time.Hour, time.Hour, 100, 0, 0, 0, 0, false)
+	s := newStubbedScraperServiceWithCache(t, models.ScraperConfig{DisablePrintFallback: true, ConditionalScrapes: true}, stub, cache)
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+
+	if _, err := s.ScrapeContent(context.Background(), target, ScrapeOptions{}); err != nil {
+		t.Fatalf("first ScrapeContent() error = %v", err)
+	}
+	if got := stub.conditional.Load(); got != 0 {
+		t.Errorf("first scrape sent If-Modified-Since %d times, want an unconditional scrape", got)
+	}
+
+	scraped := time.Now().Add(-time.Hour)
+	cache.Set(target.CacheKey(), models.DevotionalContent{DevotionalTitle: "Dari cache", DevotionalContent: []string{"Isi yang tersimpan."}}, scraped)
+	response, err := s.ScrapeContent(context.Background(), target, ScrapeOptions{BypassCache: true})
+	if err != nil {
+		t.Fatalf("refresh ScrapeContent() error = %v", err)
+	}
+	if got := stub.conditional.Load(); got != 1 {
+		t.Errorf("refresh sent If-Modified-Since %d times, want 1", got)
+	}
+	if content := response.Data.(*models.DevotionalContent); content.DevotionalTitle != "Dari cache" {
+		t.Errorf("DevotionalTitle = %q, want the cached content kept on a 304", content.DevotionalTitle)
+	}
+	if entry, ok := cache.Peek(target.CacheKey()); !ok || !entry.Timestamp.After(scraped) {
+		t.Errorf("cache entry %v, %v; want its timestamp renewed", entry.Timestamp, ok)
+	}
+
+	stub.notModified = false
+	response, err = s.ScrapeContent(context.Background(), target, ScrapeOptions{BypassCache: true})
+	if err != nil {
+		t.Fatalf("ignored-header ScrapeContent() error = %v", err)
+	}
+	if content := response.Data.(*models.DevotionalContent); content.DevotionalTitle == "Dari cache" {
+		t.Errorf("upstream ignoring If-Modified-Since still served the cached content")
+	}
+}
+
+func TestCacheHitReportsBackend(t *testing.T) {
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	for _, report := range []bool{true, false} {
+		cache := NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+		s := NewScraperService(false, models.ScraperConfig{ReportCacheBackend: report}, cache, nil, nil, nil)
+		cache.Set(target.CacheKey(), models.DevotionalContent{Title: "cached"}, time.Now())
+
+		result, err := s.ScrapeContent(context.Background(), target, ScrapeOptions{})
+		if err != nil {
+			t.Fatalf("ScrapeContent() error = %v", err)
+		}
+		want := ""
+		if report {
+			want = CacheBackendMemory
+		}
+		if got := result.Metadata.(models.ScrapingMetadata).CacheBackend; got != want {
+			t.Errorf("report_cache_backend=%t: cache_backend = %q, want %q", report, got, want)
+		}
+	}
+}
+
+func TestExtractionMethodCountsFollowFreshScrapes(t *testing.T) {
+	stub := &upstreamStub{pages: map[string]string{"/2025/09/03": "esh_table.html", "/2025/09/04": "esh_text.html"}}
+	s := newStubbedScraperService(t, models.ScraperConfig{DisablePrintFallback: true}, stub)
+
+	for _, date := range []string{"0902", "0903", "0904", "0902"} {
+		target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: date}
+		if _, err := s.ScrapeContent(context.Background(), target, ScrapeOptions{}); err != nil {
+			t.Fatalf("ScrapeContent(%s) error = %v", date, err)
+		}
+	}
+	want := map[string]int64{
+		scraper.ParagraphsFromElements: 1,
+		scraper.ParagraphsFromCells:    1,
+		scraper.ParagraphsFromText:     1,
+		scraper.ParagraphsFromEntries:  0,
+	}
+	// The repeated 0902 is a cache hit and counts nothing
+	if got := s.ExtractionMethodCounts(); !maps.Equal(got, want) {
+		t.Errorf("ExtractionMethodCounts() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+// maxPassageBytes bounds how much of a Bible API response is read
+const maxPassageBytes = 1 << 20
+
+// ErrUnparsedReference is returned for scripture references the Bible API cannot be asked about
+var ErrUnparsedReference = errors.New("scripture reference could not be parsed")
+
+// ScriptureService fetches passage text from a Bible API for devotionals whose page carries
+// only the reference. Passages are cached by reference, apart from the devotional cache.
+type ScriptureService struct {
+	urlTemplate string
+	textField   string
+	client      *http.Client
+	ttl         time.Duration
+	mutex       sync.RWMutex
+	passages    map[string]cachedPassage
+}
+
+type cachedPassage struct {
+	text      string
+	expiresAt time.Time
+}
+
+// NewScriptureService creates a passage fetcher. urlTemplate is the Bible API URL with
+// {book}, {chapter}, {verses} and {reference} placeholders; textField names the string
+// field holding the passage in JSON responses, other responses are used as plain text.
+// Passages are kept for ttl; a non-positive cleanupInterval disables pruning expired ones.
+func NewScriptureService(urlTemplate, textField string, timeout, ttl, cleanupInterval, cleanupJitter time.Duration) *ScriptureService {
+	service := &ScriptureService{
+		urlTemplate: urlTemplate,
+		textField:   textField,
+		client:      &http.Client{Timeout: timeout},
+		ttl:         ttl,
+		passages:    make(map[string]cachedPassage),
+	}
+
+	go runPeriodic(cleanupInterval, cleanupJitter, service.cleanup)
+
+	return service
+}
+
+// Passage returns the text of a single scripture reference, from cache when possible
+func (s *ScriptureService) Passage(ctx context.Context, reference string) (string, error) {
+	ref, ok := scraper.ParseReference(reference)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnparsedReference, reference)
+	}
+	key := scraper.CanonicalReference(reference)
+
+	s.mutex.RLock()
+	cached, found := s.passages[key]
+	s.mutex.RUnlock()
+	if found && time.Now().Before(cached.expiresAt) {
+		return cached.text, nil
+	}
+
+	text, err := s.fetch(ctx, ref, key)
+	if err != nil {
+		return "", err
+	}
+
+	s.mutex.Lock()
+	s.passages[key] = cachedPassage{text: text, expiresAt: time.Now().Add(s.ttl)}
+	s.mutex.Unlock()
+	return text, nil
+}
+
+func (s *ScriptureService) fetch(ctx context.Context, ref scraper.Reference, reference string) (string, error) {
+	apiURL := strings.NewReplacer(
+		"{book}", url.PathEscape(ref.Book),
+		"{chapter}", url.PathEscape(ref.Chapter),
+		"{verses}", url.PathEscape(ref.Verses),
+		"{reference}", url.PathEscape(reference),
+	).Replace(s.urlTemplate)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid Bible API URL: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Bible API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Bible API returned status %d for %s", resp.StatusCode, reference)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPassageBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to read Bible API response: %w", err)
+	}
+
+	text := string(body)
+	if strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return "", fmt.Errorf("invalid Bible API response: %w", err)
+		}
+		text, _ = fields[s.textField].(string)
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return "", fmt.Errorf("Bible API returned no passage text for %s", reference)
+	}
+	return text, nil
+}
+
+func (s *ScriptureService) cleanup() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	for key, passage := range s.passages {
+		if !now.Before(passage.expiresAt) {
+			delete(s.passages, key)
+		}
+	}
+}
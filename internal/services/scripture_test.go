@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScripturePassageFromMockAPI(t *testing.T) {
+	var calls atomic.Int64
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		switch r.URL.Path {
+		case "/Yohanes/3/16-18":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"text": "Karena begitu besar kasih Allah\n akan dunia ini."}`))
+		case "/Roma/5/8":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("Akan tetapi Allah menunjukkan kasih-Nya kepada kita."))
+		default:
+			http.Error(w, "server error", http.StatusInternalServerError)
+		}
+	}))
+	defer api.Close()
+	s := NewScriptureService(api.URL+"/{book}/{chapter}/{verses}", "text", time.Second, time.Hour, 0, 0)
+	ctx := context.Background()
+
+	for _, reference := range []string{"Yohanes 3:16-18", "Yohanes3:16 - 18"} {
+		text, err := s.Passage(ctx, reference)
+		if err != nil || text != "Karena begitu besar kasih Allah akan dunia ini." {
+			t.Errorf("Passage(%q) = %q, %v; want the JSON text field", reference, text, err)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("API calls = %d, want 1 for two spellings of one reference", got)
+	}
+
+	if text, err := s.Passage(ctx, "Roma 5:8"); err != nil || text != "Akan tetapi Allah menunjukkan kasih-Nya kepada kita." {
+		t.Errorf("Passage(Roma 5:8) = %q, %v; want the plain-text body", text, err)
+	}
+	if _, err := s.Passage(ctx, "Kejadian 1:1"); err == nil {
+		t.Errorf("Passage() returned no error for an API failure")
+	}
+	if _, err := s.Passage(ctx, "Renungan pagi"); !errors.Is(err, ErrUnparsedReference) {
+		t.Errorf("Passage() error = %v, want ErrUnparsedReference", err)
+	}
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+// Self-test states
+const (
+	SelfTestDisabled = "disabled"
+	SelfTestPending  = "pending"
+	SelfTestPassed   = "passed"
+	SelfTestDegraded = "degraded"
+)
+
+// SelfTestStatus reports the latest extraction self-test. An upstream error leaves the state
+// of the previous check in place and is only reported in Error, since it says nothing about
+// whether extraction still works.
+type SelfTestStatus struct {
+	State        string            `json:"state"`
+	Date         string            `json:"date,omitempty"`
+	QualityScore int               `json:"quality_score"`
+	MinQuality   int               `json:"min_quality"`
+	Warnings     []string          `json:"warnings,omitempty"`
+	Error        string            `json:"error,omitempty"`
+	CheckedAt    *models.Timestamp `json:"checked_at,omitempty"`
+	LastPassedAt *models.Timestamp `json:"last_passed_at,omitempty"`
+}
+
+// SelfTestService periodically scrapes a known-good past issue without the cache and checks
+// that the extractor still gets quality content out of it, catching SABDA markup changes
+// that break extraction before clients report them. A nil service reports disabled and ready.
+type SelfTestService struct {
+	scraperService *ScraperService
+	target         scraper.Target
+	minQuality     int
+	timeout        time.Duration
+	mutex          sync.Mutex
+	status         SelfTestStatus
+}
+
+// NewSelfTestService creates a self-test of target that fails when the extracted content
+// scores below minQuality. It checks once right away and then every interval plus jitter,
+// each check allowed up to timeout.
+func NewSelfTestService(scraperService *ScraperService, target scraper.Target, minQuality int, interval, jitter, timeout time.Duration) *SelfTestService {
+	service := &SelfTestService{
+		scraperService: scraperService,
+		target:         target,
+		minQuality:     minQuality,
+		timeout:        timeout,
+		status: SelfTestStatus{
+			State:      SelfTestPending,
+			Date:       selfTestDate(target),
+			MinQuality: minQuality,
+		},
+	}
+
+	go func() {
+		service.Run()
+		runPeriodic(interval, jitter, service.Run)
+	}()
+
+	return service
+}
+
+// Status returns the latest self-test result
+func (s *SelfTestService) Status() SelfTestStatus {
+	if s == nil {
+		return SelfTestStatus{State: SelfTestDisabled}
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.status
+}
+
+// Ready reports false once a check has found extraction broken, until a later check passes
+func (s *SelfTestService) Ready() bool {
+	return s.Status().State != SelfTestDegraded
+}
+
+// Run scrapes the self-test issue and records the result
+func (s *SelfTestService) Run() {
+	ctx, cancel := WithScrapeTimeout(context.Background(), s.timeout)
+	defer cancel()
+	result, err := s.scraperService.ScrapeUncached(ctx, s.target)
+	now := models.Now()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	previous := s.status.State
+	s.status.CheckedAt = &now
+	switch {
+	case err == nil:
+		s.status.QualityScore = scraper.QualityScore(result.Content)
+		s.status.Warnings = scraper.QualityWarnings(result.Content)
+		s.status.Error = ""
+		if s.status.QualityScore >= s.minQuality {
+			s.status.State = SelfTestPassed
+			s.status.LastPassedAt = &now
+		} else {
+			s.status.State = SelfTestDegraded
+			s.status.Error = fmt.Sprintf("quality score %d is below %d", s.status.QualityScore, s.minQuality)
+		}
+	case errors.Is(err, scraper.ErrParseFailure), errors.Is(err, scraper.ErrNotFound):
+		// The issue is known to exist, so a page we cannot read means the markup changed
+		s.status.State = SelfTestDegraded
+		s.status.QualityScore = 0
+		s.status.Warnings = nil
+		s.status.Error = err.Error()
+	default:
+		s.status.Error = err.Error()
+		log.Printf("Extraction self-test of %s inconclusive: %v", s.status.Date, err)
+		return
+	}
+
+	if s.status.State != previous {
+		log.Printf("Extraction self-test of %s %s (quality %d, minimum %d)", s.status.Date, s.status.State, s.status.QualityScore, s.minQuality)
+	}
+}
+
+// selfTestDate formats target's date as YYYY-MM-DD
+func selfTestDate(target scraper.Target) string {
+	date, ok := scraper.NormalizeDate(target.Date)
+	if !ok {
+		return fmt.Sprintf("%d-%s", target.Year, target.Date)
+	}
+	return fmt.Sprintf("%d-%s-%s", target.Year, date[:2], date[2:])
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+func TestScrapeUncachedLeavesQuotaToClients(t *testing.T) {
+	stub := &upstreamStub{}
+	s := newStubbedScraperService(t, models.ScraperConfig{DailyQuota: 1, DisablePrintFallback: true}, stub)
+
+	if _, err := s.ScrapeUncached(context.Background(), scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}); err != nil {
+		t.Fatalf("ScrapeUncached() error = %v", err)
+	}
+	if status := s.QuotaStatus(); status.Used != 0 {
+		t.Errorf("quota used = %d after a self-test scrape, want 0", status.Used)
+	}
+	if stub.requests.Load() != 1 {
+		t.Errorf("upstream requests = %d, want 1", stub.requests.Load())
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+// defaultStopwords are common Indonesian function words excluded from word statistics
+var defaultStopwords = []string{
+	"ada", "adalah", "agar", "akan", "aku", "apa", "atau", "bagi", "bahwa", "banyak",
+	"begitu", "belum", "bisa", "dalam", "dan", "dapat", "dari", "demikian", "dengan", "di",
+	"dia", "engkau", "hanya", "ia", "ini", "itu", "jika", "juga", "kami", "karena",
+	"ke", "kepada", "ketika", "kita", "lebih", "maka", "masih", "mereka", "namun", "oleh",
+	"pada", "para", "pun", "saat", "sangat", "saya", "sebagai", "secara", "sedang", "seperti",
+	"serta", "setelah", "sudah", "supaya", "tersebut", "telah", "tentang", "tetapi", "tidak", "untuk",
+	"yaitu", "yakni", "yang",
+}
+
+// qualityBucketWidth is the width of the quality score buckets of corpus stats; the last
+// bucket also holds the maximum score of 100
+const qualityBucketWidth = 20
+
+// StatsService computes word statistics for devotional text
+type StatsService struct {
+	topN      int
+	stopwords map[string]bool
+}
+
+// NewStatsService creates a new stats service returning the topN most frequent words.
+// An empty stopwords list falls back to the built-in Indonesian list.
+func NewStatsService(topN int, stopwords []string) *StatsService {
+	if len(stopwords) == 0 {
+		stopwords = defaultStopwords
+	}
+	set := make(map[string]bool, len(stopwords))
+	for _, word := range stopwords {
+		set[strings.ToLower(strings.TrimSpace(word))] = true
+	}
+	return &StatsService{
+		topN:      topN,
+		stopwords: set,
+	}
+}
+
+// TopWords returns the most frequent non-stopwords in text, most frequent first and
+// alphabetically among equal counts. Words are split as for WordCount, then lowercased
+// with surrounding punctuation removed.
+func (s *StatsService) TopWords(text string) []models.WordFrequency {
+	counts := make(map[string]int)
+	for _, field := range strings.Fields(text) {
+		word := strings.ToLower(strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}))
+		if word == "" || s.stopwords[word] {
+			continue
+		}
+		counts[word]++
+	}
+
+	frequencies := make([]models.WordFrequency, 0, len(counts))
+	for word, count := range counts {
+		frequencies = append(frequencies, models.WordFrequency{Word: word, Count: count})
+	}
+	sort.Slice(frequencies, func(i, j int) bool {
+		if frequencies[i].Count != frequencies[j].Count {
+			return frequencies[i].Count > frequencies[j].Count
+		}
+		return frequencies[i].Word < frequencies[j].Word
+	})
+
+	if s.topN > 0 && len(frequencies) > s.topN {
+		frequencies = frequencies[:s.topN]
+	}
+	return frequencies
+}
+
+// Corpus aggregates the given devotionals: their number, average word count, the topN books
+// cited by the most devotionals and how their quality scores are distributed. Each book is
+// counted once per devotional, under its full name, from the parsable parts of its
+// scripture reference.
+func (s *StatsService) Corpus(contents []models.DevotionalContent) models.CorpusStats {
+	stats := models.CorpusStats{
+		TotalEntries: len(contents),
+		TopBooks:     []models.BookFrequency{},
+	}
+	for low := 0; low < 100; low += qualityBucketWidth {
+		high := low + qualityBucketWidth - 1
+		if high+qualityBucketWidth > 100 {
+			high = 100
+		}
+		stats.QualityDistribution = append(stats.QualityDistribution, models.QualityBucket{Min: low, Max: high})
+	}
+	if len(contents) == 0 {
+		return stats
+	}
+
+	words := 0
+	books := make(map[string]int)
+	for i := range contents {
+		content := &contents[i]
+		words += content.WordCount
+
+		cited := make(map[string]bool)
+		for _, ref := range strings.Split(content.ScriptureReference, ";") {
+			parsed, ok := scraper.ParseReference(ref)
+			if !ok {
+				continue
+			}
+			if book := scraper.FullBookName(parsed.Book); !cited[book] {
+				cited[book] = true
+				books[book]++
+			}
+		}
+
+		bucket := min(scraper.QualityScore(content)/qualityBucketWidth, len(stats.QualityDistribution)-1)
+		stats.QualityDistribution[bucket].Count++
+	}
+	stats.AverageWordCount = float64(words) / float64(len(contents))
+
+	for book, count := range books {
+		stats.TopBooks = append(stats.TopBooks, models.BookFrequency{Book: book, Count: count})
+	}
+	sort.Slice(stats.TopBooks, func(i, j int) bool {
+		if stats.TopBooks[i].Count != stats.TopBooks[j].Count {
+			return stats.TopBooks[i].Count > stats.TopBooks[j].Count
+		}
+		return stats.TopBooks[i].Book < stats.TopBooks[j].Book
+	})
+	if s.topN > 0 && len(stats.TopBooks) > s.topN {
+		stats.TopBooks = stats.TopBooks[:s.topN]
+	}
+	return stats
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+func TestTopWordsExcludesStopwords(t *testing.T) {
+	stats := NewStatsService(3, nil)
+	text := "Allah mengasihi dunia. Kasih Allah yang besar itu nyata; kasih-Nya dan kasih Allah tidak berubah. Dunia, dunia!"
+
+	want := []models.WordFrequency{{Word: "allah", Count: 3}, {Word: "dunia", Count: 3}, {Word: "kasih", Count: 2}}
+	if got := stats.TopWords(text); !slices.Equal(got, want) {
+		t.Errorf("TopWords() = %v, want %v", got, want)
+	}
+	for _, frequency := range NewStatsService(0, nil).TopWords(text) {
+		if slices.Contains(defaultStopwords, frequency.Word) {
+			t.Errorf("TopWords() counted stopword %q", frequency.Word)
+		}
+	}
+
+	custom := NewStatsService(1, []string{"Allah", "dunia"})
+	if got := custom.TopWords(text); !slices.Equal(got, []models.WordFrequency{{Word: "kasih", Count: 2}}) {
+		t.Errorf("TopWords() with custom stopwords = %v, want kasih 2", got)
+	}
+}
+
+func TestCorpusAggregatesCachedEntries(t *testing.T) {
+	stats := NewStatsService(2, nil)
+	cache := NewCacheService(time.Hour, time.Hour, 10, 0, 0, 0, 0, false)
+
+	empty := stats.Corpus(cache.Snapshot())
+	if empty.TotalEntries != 0 || empty.AverageWordCount != 0 || len(empty.TopBooks) != 0 || len(empty.QualityDistribution) == 0 {
+		t.Errorf("Corpus() of an empty cache = %+v, want zero counts over the buckets", empty)
+	}
+
+	for key, entry := range map[string]models.DevotionalContent{
+		"sabda_2025_0902": {ScriptureReference: "Yohanes 3:16", WordCount: 100},
+		"sabda_2025_0903": {ScriptureReference: "Yoh 10:11-15; Mzm 23:1-6", WordCount: 200},
+		"sabda_2025_0904": {ScriptureReference: "Mazmur 46:2-4", WordCount: 300},
+		"sabda_2025_0905": {ScriptureReference: "Roma 5:8", WordCount: 400},
+	} {
+		cache.Set(key, entry, time.Now())
+	}
+	corpus := stats.Corpus(cache.Snapshot())
+	if corpus.TotalEntries != 4 || corpus.AverageWordCount != 250 {
+		t.Errorf("TotalEntries = %d, AverageWordCount = %v; want 4 and 250", corpus.TotalEntries, corpus.AverageWordCount)
+	}
+	want := []models.BookFrequency{{Book: "Mazmur", Count: 2}, {Book: "Yohanes", Count: 2}}
+	if !slices.Equal(corpus.TopBooks, want) {
+		t.Errorf("TopBooks = %v, want %v", corpus.TopBooks, want)
+	}
+	total := 0
+	for _, bucket := range corpus.QualityDistribution {
+		total += bucket.Count
+	}
+	if total != 4 {
+		t.Errorf("quality distribution counts %d entries, want 4", total)
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+// ErrWarmBusy is returned when as many warm jobs as the warm concurrency are already running
+var ErrWarmBusy = errors.New("too many warm jobs running")
+
+// ErrWarmDisabled is returned when cache warming is turned off (a nil WarmService)
+var ErrWarmDisabled = errors.New("cache warming is disabled")
+
+// Warm job states
+const (
+	WarmJobRunning   = "running"
+	WarmJobCompleted = "completed"
+)
+
+// WarmJobStatus reports the progress of a background cache warm job
+type WarmJobStatus struct {
+	ID         string            `json:"id"`
+	State      string            `json:"state"`
+	Total      int               `json:"total"`
+	Completed  int               `json:"completed"`
+	Failures   int               `json:"failures"`
+	LastError  string            `json:"last_error,omitempty"`
+	StartedAt  models.Timestamp  `json:"started_at"`
+	FinishedAt *models.Timestamp `json:"finished_at,omitempty"`
+	ETASeconds *int64            `json:"eta_seconds,omitempty"`
+}
+
+// warmJob is the mutable state behind a WarmJobStatus, guarded by WarmService.mutex
+type warmJob struct {
+	status WarmJobStatus
+}
+
+// WarmService runs cache warm jobs in the background and tracks their progress. Scrapes
+// from all jobs share a fixed number of slots and go through ScraperService, so they obey
+// the scrape queue, quota and upstream politeness limits like any other scrape.
+type WarmService struct {
+	scraperService *ScraperService
+	slots          chan struct{}
+	timeout        time.Duration
+	retention      time.Duration
+	mutex          sync.Mutex
+	jobs           map[string]*warmJob
+	running        int
+}
+
+// NewWarmService creates a warm service running at most concurrency scrapes at once, each
+// allowed up to timeout. Finished jobs are reported for retention and then forgotten by a cleanup pass every
+// cleanupInterval; a non-positive cleanupInterval disables the cleanup.
+func NewWarmService(scraperService *ScraperService, concurrency int, timeout, retention, cleanupInterval, cleanupJitter time.Duration) *WarmService {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	service := &WarmService{
+		scraperService: scraperService,
+		slots:          make(chan struct{}, concurrency),
+		timeout:        timeout,
+		retention:      retention,
+		jobs:           make(map[string]*warmJob),
+	}
+
+	go runPeriodic(cleanupInterval, cleanupJitter, service.cleanup)
+
+	return service
+}
+
+// Start begins a fresh scrape of every target in the background and returns the new job's
+// status. ctx supplies the correlation IDs for the upstream requests; its cancellation is
+// ignored so the job outlives the request that started it.
+func (w *WarmService) Start(ctx context.Context, targets []scraper.Target) (WarmJobStatus, error) {
+	if w == nil {
+		return WarmJobStatus{}, ErrWarmDisabled
+	}
+	w.mutex.Lock()
+	if w.running >= cap(w.slots) {
+		w.mutex.Unlock()
+		return WarmJobStatus{}, ErrWarmBusy
+	}
+	job := &warmJob{status: WarmJobStatus{
+		ID:        newWarmJobID(),
+		State:     WarmJobRunning,
+		Total:     len(targets),
+		StartedAt: models.Now(),
+	}}
+	w.jobs[job.status.ID] = job
+	w.running++
+	status := job.status
+	w.mutex.Unlock()
+
+	ctx = scraper.WithCorrelation(context.Background(), scraper.CorrelationFromContext(ctx))
+	go w.run(ctx, job, targets)
+
+	return status, nil
+}
+
+// Status returns the progress of job id, reporting false for unknown or expired jobs
+func (w *WarmService) Status(id string) (WarmJobStatus, bool) {
+	if w == nil {
+		return WarmJobStatus{}, false
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	job, ok := w.jobs[id]
+	if !ok || w.expired(job, time.Now()) {
+		return WarmJobStatus{}, false
+	}
+
+	status := job.status
+	if status.State == WarmJobRunning && status.Completed > 0 {
+		elapsed := time.Since(status.StartedAt.Time)
+		eta := int64((elapsed / time.Duration(status.Completed) * time.Duration(status.Total-status.Completed)).Seconds())
+		status.ETASeconds = &eta
+	}
+	return status, true
+}
+
+func (w *WarmService) run(ctx context.Context, job *warmJob, targets []scraper.Target) {
+	workers := min(cap(w.slots), len(targets))
+	queue := make(chan scraper.Target)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range queue {
+				w.warm(ctx, job, target)
+			}
+		}()
+	}
+	for _, target := range targets {
+		queue <- target
+	}
+	close(queue)
+	wg.Wait()
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	finished := models.Now()
+	job.status.State = WarmJobCompleted
+	job.status.FinishedAt = &finished
+	w.running--
+	log.Printf("Warm job %s finished: %d of %d issues warmed", job.status.ID, job.status.Completed-job.status.Failures, job.status.Total)
+}
+
+func (w *WarmService) warm(ctx context.Context, job *warmJob, target scraper.Target) {
+	w.slots <- struct{}{}
+	scrapeCtx, cancel := WithScrapeTimeout(ctx, w.timeout)
+	_, err := w.scraperService.ScrapeContent(scrapeCtx, target, ScrapeOptions{BypassCache: true})
+	cancel()
+	<-w.slots
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	job.status.Completed++
+	if err != nil {
+		log.Printf("Cache warm for %s failed: %v", target.CacheKey(), err)
+		job.status.Failures++
+		job.status.LastError = err.Error()
+		return
+	}
+	log.Printf("Cache warmed for %s", target.CacheKey())
+}
+
+func (w *WarmService) expired(job *warmJob, now time.Time) bool {
+	return job.status.FinishedAt != nil && now.Sub(job.status.FinishedAt.Time) > w.retention
+}
+
+func (w *WarmService) cleanup() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	now := time.Now()
+	for id, job := range w.jobs {
+		if w.expired(job, now) {
+			delete(w.jobs, id)
+		}
+	}
+}
+
+func newWarmJobID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
+)
+
+func TestDisabledWarmServiceRejectsJobs(t *testing.T) {
+	var w *WarmService
+	target := scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: "0902"}
+	if _, err := w.Start(context.Background(), []scraper.Target{target}); !errors.Is(err, ErrWarmDisabled) {
+		t.Errorf("Start() error = %v, want ErrWarmDisabled", err)
+	}
+	if _, ok := w.Status("3f2a"); ok {
+		t.Errorf("Status() found a job on a disabled warm service")
+	}
+}
+
+func TestWarmJobReportsProgress(t *testing.T) {
+	stub := &upstreamStub{notFound: "2025/09/03"}
+	s := newStubbedScraperService(t, models.ScraperConfig{DisablePrintFallback: true}, stub)
+	w := NewWarmService(s, 2, time.Minute, time.Hour, 0, 0)
+
+	var targets []scraper.Target
+	for _, date := range []string{"0902", "0903", "0904"} {
+		targets = append(targets, scraper.Target{Publication: scraper.DefaultPublication, Year: 2025, Date: date})
+	}
+	started, err := w.Start(context.Background(), targets)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if started.State != WarmJobRunning || started.Total != 3 || started.Completed != 0 {
+		t.Errorf("started = %+v, want a running job of 3 with nothing completed", started)
+	}
+
+	deadline := time.Now().Add(30 * time.Second)
+	status, ok := w.Status(started.ID)
+	for ok && status.State == WarmJobRunning && time.Now().Before(deadline) {
+		if status.Completed > status.Total {
+			t.Fatalf("completed %d of %d", status.Completed, status.Total)
+		}
+		if status.Completed > 0 && status.ETASeconds == nil {
+			t.Errorf("running job with %d completed reported no ETA", status.Completed)
+		}
+		time.Sleep(50 * time.Millisecond)
+		status, ok = w.Status(started.ID)
+	}
+	if !ok || status.State != WarmJobCompleted {
+		t.Fatalf("Status() = %+v, %v; want the job completed", status, ok)
+	}
+	if status.Completed != 3 || status.Failures != 1 || status.LastError == "" {
+		t.Errorf("Completed = %d, Failures = %d, LastError = %q; want 3, 1 and the unpublished date's error", status.Completed, status.Failures, status.LastError)
+	}
+	if status.FinishedAt == nil || status.ETASeconds != nil {
+		t.Errorf("FinishedAt = %v, ETASeconds = %v; want a finish time and no ETA", status.FinishedAt, status.ETASeconds)
+	}
+	if _, ok := w.Status("unknown"); ok {
+		t.Errorf("Status() found an unknown job")
+	}
+}
@@ -3,52 +3,210 @@ package config
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"github.com/pranahonk/sabda-scraper-go/pkg/scraper"
 	"github.com/spf13/viper"
 )
 
+// loadedSecretKey is jwt.secret_key as configured at startup, before secret resolution
+var loadedSecretKey string
+
 // Load loads configuration from environment variables and config files
 func Load() *models.Config {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath(".")
 	viper.AddConfigPath("./config")
-	
+
 	// Set defaults
 	setDefaults()
-	
+
 	// Read from environment variables
 	viper.AutomaticEnv()
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	
+
 	// Try to read config file (optional)
 	if err := viper.ReadInConfig(); err != nil {
 		log.Printf("Config file not found, using environment variables and defaults: %v", err)
 	}
-	
+
+	config, err := decode()
+	if err != nil {
+		log.Fatalf("Unable to decode config: %v", err)
+	}
+	loadedSecretKey = config.JWT.SecretKey
+
+	// Resolve the JWT secret, generating an ephemeral one only when explicitly allowed
+	config.JWT.SecretKey = resolveSecretKey(config.JWT)
+
+	return config
+}
+
+// ErrImmutableSetting is returned by Reload when the config file changes a setting that
+// only takes effect on restart
+var ErrImmutableSetting = errors.New("setting cannot be changed without a restart")
+
+// Reload re-reads the config file and returns the configuration to put in effect: current
+// with the API keys and per-minute rate limits taken from the file, and the names of those
+// settings that changed. Every other setting keeps its current value until a restart.
+// Settings that cannot change at runtime (listen address, JWT secret) must match current;
+// otherwise ErrImmutableSetting is returned naming the first one that differs.
+func Reload(current *models.Config) (*models.Config, []string, error) {
+	if err := viper.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &notFound) {
+			return nil, nil, fmt.Errorf("read config file: %w", err)
+		}
+	}
+
+	config, err := decode()
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	immutable := []struct {
+		name             string
+		current, updated string
+	}{
+		{"server.port", current.Server.Port, config.Server.Port},
+		{"server.host", current.Server.Host, config.Server.Host},
+		{"jwt.secret_key", loadedSecretKey, config.JWT.SecretKey},
+		{"jwt.secret_file", current.JWT.SecretFile, config.JWT.SecretFile},
+		{"jwt.secret_env", current.JWT.SecretEnv, config.JWT.SecretEnv},
+	}
+	for _, setting := range immutable {
+		if setting.current != setting.updated {
+			return nil, nil, fmt.Errorf("%s: %w", setting.name, ErrImmutableSetting)
+		}
+	}
+
+	effective := *current
+	effective.API = config.API
+	effective.Rate.MaxRequestsPerMinute = config.Rate.MaxRequestsPerMinute
+	effective.Rate.BatchTokenRequestsPerMinute = config.Rate.BatchTokenRequestsPerMinute
+	effective.Rate.WarmRequestsPerMinute = config.Rate.WarmRequestsPerMinute
+
+	live := []struct {
+		name    string
+		changed bool
+	}{
+		{"api.flutter_key", current.API.FlutterKey != config.API.FlutterKey},
+		{"api.mobile_key", current.API.MobileKey != config.API.MobileKey},
+		{"api.admin_key", current.API.AdminKey != config.API.AdminKey},
+		{"api.warmer_key", current.API.WarmerKey != config.API.WarmerKey},
+		{"rate.max_requests_per_minute", current.Rate.MaxRequestsPerMinute != config.Rate.MaxRequestsPerMinute},
+		{"rate.batch_token_requests_per_minute", current.Rate.BatchTokenRequestsPerMinute != config.Rate.BatchTokenRequestsPerMinute},
+		{"rate.warm_requests_per_minute", current.Rate.WarmRequestsPerMinute != config.Rate.WarmRequestsPerMinute},
+	}
+	applied := []string{}
+	for _, setting := range live {
+		if setting.changed {
+			applied = append(applied, setting.name)
+		}
+	}
+	return &effective, applied, nil
+}
+
+// decode unmarshals the current viper state and fills in the computed fields
+func decode() (*models.Config, error) {
 	var config models.Config
 	if err := viper.Unmarshal(&config); err != nil {
-		log.Fatalf("Unable to decode config: %v", err)
+		return nil, err
 	}
-	
+
 	// Set computed fields
 	config.JWT.ExpirationDelta = time.Duration(config.JWT.ExpirationHours) * time.Hour
 	config.Cache.TTL = time.Duration(config.Cache.TTLSeconds) * time.Second
+	config.Cache.CleanupInterval = time.Duration(config.Cache.CleanupIntervalSeconds) * time.Second
+	config.Cache.CleanupJitter = time.Duration(config.Cache.CleanupJitterSeconds) * time.Second
 	config.Rate.WindowDuration = time.Minute
-	config.Rate.CleanupInterval = 5 * time.Minute
-	
-	// Generate secret key if not provided
-	if config.JWT.SecretKey == "" {
-		config.JWT.SecretKey = generateSecretKey()
-	}
-	
-	return &config
+	config.Rate.CleanupInterval = time.Duration(config.Rate.CleanupIntervalSeconds) * time.Second
+	config.Rate.CleanupJitter = time.Duration(config.Rate.CleanupJitterSeconds) * time.Second
+
+	policies, err := resolveCORSPolicies(config.CORS)
+	if err != nil {
+		return nil, err
+	}
+	config.CORS.Policies = policies
+
+	if err := validateTLS(config.Server); err != nil {
+		return nil, err
+	}
+
+	if dir := config.Server.StaticDir; dir != "" {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("invalid server.static_dir %q: not a readable directory", dir)
+		}
+	}
+
+	selectors, err := resolveContentSelectors(config.Scraper.ContentSelectors)
+	if err != nil {
+		return nil, err
+	}
+	config.Scraper.ContentSelectors = selectors
+
+	if err := resolveTrimPatterns(&config.Scraper); err != nil {
+		return nil, err
+	}
+
+	if pattern := config.Scraper.ScriptureBookPattern; pattern != "" {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid scraper.scripture_book_pattern: %w", err)
+		}
+	}
+
+	if config.SelfTest.Interval > 0 {
+		date, err := time.Parse("2006-01-02", config.SelfTest.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid self_test.date %q: must be YYYY-MM-DD", config.SelfTest.Date)
+		}
+		config.SelfTest.Year = date.Year()
+		config.SelfTest.MonthDay = date.Format("0102")
+	}
+
+	if config.SafeMode {
+		applySafeMode(&config)
+	}
+
+	return &config, nil
+}
+
+// applySafeMode turns off every feature that makes outbound requests other than the scrape
+// answering an API call, whatever their individual settings: Bible API enrichment, background
+// refresh-ahead scrapes, the extraction self-test and cache warm jobs.
+func applySafeMode(config *models.Config) {
+	var disabled []string
+	if config.Scripture.APIURL != "" {
+		config.Scripture.APIURL = ""
+		disabled = append(disabled, "scripture.api_url")
+	}
+	if config.Cache.RefreshAheadWindow > 0 {
+		config.Cache.RefreshAheadWindow = 0
+		disabled = append(disabled, "cache.refresh_ahead_window")
+	}
+	if config.SelfTest.Interval > 0 {
+		config.SelfTest.Interval = 0
+		disabled = append(disabled, "self_test.interval")
+	}
+	if config.Cache.WarmConcurrency > 0 {
+		config.Cache.WarmConcurrency = 0
+		disabled = append(disabled, "cache.warm_concurrency")
+	}
+	overridden := "none"
+	if len(disabled) > 0 {
+		overridden = strings.Join(disabled, ", ")
+	}
+	log.Printf("Safe mode active: outbound features disabled (overridden settings: %s)", overridden)
 }
 
 func setDefaults() {
@@ -58,22 +216,126 @@ func setDefaults() {
 	viper.SetDefault("server.debug", getEnvBoolOrDefault("GO_DEBUG", false))
 	viper.SetDefault("server.timeout", 30*time.Second)
 	viper.SetDefault("server.idle_timeout", 120*time.Second)
-	
+	viper.SetDefault("server.enabled_formats", splitNonEmpty(os.Getenv("ENABLED_FORMATS")))
+	viper.SetDefault("server.negotiation_order", splitNonEmpty(os.Getenv("NEGOTIATION_ORDER")))
+	viper.SetDefault("server.default_language", getEnvOrDefault("DEFAULT_LANGUAGE", "id"))
+	viper.SetDefault("server.static_dir", os.Getenv("STATIC_DIR"))
+	viper.SetDefault("server.request_id_header", getEnvOrDefault("REQUEST_ID_HEADER", "X-Request-ID"))
+	viper.SetDefault("server.include_metadata", getEnvBoolOrDefault("INCLUDE_METADATA", true))
+	viper.SetDefault("server.maintenance", getEnvBoolOrDefault("MAINTENANCE_MODE", false))
+	viper.SetDefault("server.maintenance_retry_after", time.Duration(getEnvIntOrDefault("MAINTENANCE_RETRY_AFTER", 300))*time.Second)
+	viper.SetDefault("server.time_format", getEnvOrDefault("TIME_FORMAT", "rfc3339"))
+	viper.SetDefault("server.strict_content_type", getEnvBoolOrDefault("STRICT_CONTENT_TYPE", false))
+	viper.SetDefault("server.max_response_bytes", getEnvIntOrDefault("MAX_RESPONSE_BYTES", 0))
+	viper.SetDefault("server.stream_threshold_bytes", getEnvIntOrDefault("STREAM_THRESHOLD_BYTES", 0))
+	viper.SetDefault("server.report_filters", getEnvBoolOrDefault("REPORT_FILTERS", false))
+	viper.SetDefault("server.vary_authorization", getEnvBoolOrDefault("VARY_AUTHORIZATION", true))
+	viper.SetDefault("server.tls_cert_file", os.Getenv("TLS_CERT_FILE"))
+	viper.SetDefault("server.tls_key_file", os.Getenv("TLS_KEY_FILE"))
+	viper.SetDefault("server.tls_min_version", getEnvOrDefault("TLS_MIN_VERSION", "1.2"))
+	viper.SetDefault("server.tls_cipher_suites", splitNonEmpty(os.Getenv("TLS_CIPHER_SUITES")))
+
 	// JWT defaults
 	viper.SetDefault("jwt.secret_key", os.Getenv("SECRET_KEY"))
+	viper.SetDefault("jwt.secret_file", os.Getenv("JWT_SECRET_FILE"))
+	viper.SetDefault("jwt.secret_env", os.Getenv("JWT_SECRET_ENV"))
+	viper.SetDefault("jwt.allow_ephemeral_secret", getEnvBoolOrDefault("ALLOW_EPHEMERAL_SECRET", getEnvBoolOrDefault("GO_DEBUG", false)))
 	viper.SetDefault("jwt.expiration_hours", getEnvIntOrDefault("JWT_EXPIRATION_HOURS", 24))
-	
+
 	// Cache defaults
 	viper.SetDefault("cache.ttl_seconds", getEnvIntOrDefault("CACHE_TTL", 3600))
 	viper.SetDefault("cache.max_size", getEnvIntOrDefault("CACHE_MAX_SIZE", 1000))
-	
+	viper.SetDefault("cache.negative_ttl", time.Duration(getEnvIntOrDefault("CACHE_NEGATIVE_TTL", 600))*time.Second)
+	viper.SetDefault("cache.cleanup_interval_seconds", getEnvIntOrDefault("CACHE_CLEANUP_INTERVAL", 300))
+	viper.SetDefault("cache.cleanup_jitter_seconds", getEnvIntOrDefault("CACHE_CLEANUP_JITTER", 30))
+	viper.SetDefault("cache.refresh_ahead_window", time.Duration(getEnvIntOrDefault("CACHE_REFRESH_AHEAD_WINDOW", 0))*time.Second)
+	viper.SetDefault("cache.refresh_ahead_min_hits", getEnvIntOrDefault("CACHE_REFRESH_AHEAD_MIN_HITS", 5))
+	viper.SetDefault("cache.skip_stale_writes", getEnvBoolOrDefault("CACHE_SKIP_STALE_WRITES", true))
+	viper.SetDefault("cache.warm_concurrency", getEnvIntOrDefault("WARM_CONCURRENCY", 4))
+	viper.SetDefault("cache.warm_job_retention", time.Duration(getEnvIntOrDefault("WARM_JOB_RETENTION", 3600))*time.Second)
+
 	// Rate limiting defaults
 	viper.SetDefault("rate.max_requests_per_minute", getEnvIntOrDefault("MAX_REQUESTS_PER_MINUTE", 60))
-	
+	viper.SetDefault("rate.warm_requests_per_minute", getEnvIntOrDefault("WARM_REQUESTS_PER_MINUTE", 30))
+	viper.SetDefault("rate.batch_token_requests_per_minute", getEnvIntOrDefault("BATCH_TOKEN_REQUESTS_PER_MINUTE", 5))
+	viper.SetDefault("rate.max_clients", getEnvIntOrDefault("RATE_MAX_CLIENTS", 100000))
+	viper.SetDefault("rate.cleanup_interval_seconds", getEnvIntOrDefault("RATE_CLEANUP_INTERVAL", 300))
+	viper.SetDefault("rate.cleanup_jitter_seconds", getEnvIntOrDefault("RATE_CLEANUP_JITTER", 30))
+
+	// Scraper defaults
+	viper.SetDefault("scraper.max_range_days", getEnvIntOrDefault("MAX_RANGE_DAYS", 366))
+	viper.SetDefault("scraper.request_id_header", getEnvOrDefault("UPSTREAM_REQUEST_ID_HEADER", "X-Request-ID"))
+	viper.SetDefault("scraper.propagate_traceparent", getEnvBoolOrDefault("PROPAGATE_TRACEPARENT", false))
+	viper.SetDefault("scraper.daily_quota", getEnvIntOrDefault("DAILY_SCRAPE_QUOTA", 0))
+	viper.SetDefault("scraper.non_admin_no_cache", getEnvOrDefault("NON_ADMIN_NO_CACHE", "reject"))
+	viper.SetDefault("scraper.min_client_max_age", time.Duration(getEnvIntOrDefault("MIN_CLIENT_MAX_AGE", 3600))*time.Second)
+	viper.SetDefault("scraper.min_scrape_interval", time.Duration(getEnvIntOrDefault("MIN_SCRAPE_INTERVAL", 60))*time.Second)
+	viper.SetDefault("scraper.max_concurrent_scrapes", getEnvIntOrDefault("MAX_CONCURRENT_SCRAPES", 4))
+	viper.SetDefault("scraper.max_inflight_per_client", getEnvIntOrDefault("MAX_INFLIGHT_PER_CLIENT", 0))
+	viper.SetDefault("scraper.queue_timeout", time.Duration(getEnvIntOrDefault("SCRAPE_QUEUE_TIMEOUT", 10))*time.Second)
+	viper.SetDefault("scraper.share_inflight_scrapes", getEnvBoolOrDefault("SHARE_INFLIGHT_SCRAPES", true))
+	viper.SetDefault("scraper.detect_duplicates", getEnvBoolOrDefault("DETECT_DUPLICATES", false))
+	viper.SetDefault("scraper.empty_response_min_words", getEnvIntOrDefault("EMPTY_RESPONSE_MIN_WORDS", 20))
+	viper.SetDefault("scraper.empty_response_retries", getEnvIntOrDefault("EMPTY_RESPONSE_RETRIES", 1))
+	viper.SetDefault("scraper.empty_response_retry_delay", time.Duration(getEnvIntOrDefault("EMPTY_RESPONSE_RETRY_DELAY", 5))*time.Second)
+	viper.SetDefault("scraper.conditional_scrapes", getEnvBoolOrDefault("CONDITIONAL_SCRAPES", true))
+	viper.SetDefault("scraper.report_cache_backend", getEnvBoolOrDefault("REPORT_CACHE_BACKEND", true))
+	viper.SetDefault("scraper.excerpt_length", getEnvIntOrDefault("EXCERPT_LENGTH", 160))
+	viper.SetDefault("scraper.disable_print_fallback", getEnvBoolOrDefault("DISABLE_PRINT_FALLBACK", false))
+	viper.SetDefault("scraper.canonical_refs", getEnvBoolOrDefault("CANONICAL_REFS", true))
+	viper.SetDefault("scraper.follow_print_link", getEnvBoolOrDefault("FOLLOW_PRINT_LINK", true))
+	viper.SetDefault("scraper.extract_images", getEnvBoolOrDefault("EXTRACT_IMAGES", true))
+	viper.SetDefault("scraper.split_print_reading", getEnvBoolOrDefault("SPLIT_PRINT_READING", true))
+	viper.SetDefault("scraper.leading_trim_patterns", scraper.DefaultLeadingTrimPatterns)
+	viper.SetDefault("scraper.trailing_trim_patterns", scraper.DefaultTrailingTrimPatterns)
+	viper.SetDefault("scraper.follow_redirects", getEnvBoolOrDefault("FOLLOW_REDIRECTS", true))
+	viper.SetDefault("scraper.max_redirects", getEnvIntOrDefault("MAX_REDIRECTS", 5))
+	viper.SetDefault("scraper.merge_short_paragraphs", getEnvBoolOrDefault("MERGE_SHORT_PARAGRAPHS", false))
+	viper.SetDefault("scraper.short_paragraph_length", getEnvIntOrDefault("SHORT_PARAGRAPH_LENGTH", 80))
+	viper.SetDefault("scraper.merged_paragraph_max_length", getEnvIntOrDefault("MERGED_PARAGRAPH_MAX_LENGTH", 600))
+	viper.SetDefault("scraper.interactive_timeout", time.Duration(getEnvIntOrDefault("INTERACTIVE_SCRAPE_TIMEOUT", 15))*time.Second)
+	viper.SetDefault("scraper.background_timeout", time.Duration(getEnvIntOrDefault("BACKGROUND_SCRAPE_TIMEOUT", 120))*time.Second)
+	viper.SetDefault("scraper.scripture_book_pattern", os.Getenv("SCRIPTURE_BOOK_PATTERN"))
+	viper.SetDefault("scraper.enabled_publications", splitNonEmpty(os.Getenv("ENABLED_PUBLICATIONS")))
+
+	// Stats defaults
+	viper.SetDefault("stats.top_n", getEnvIntOrDefault("STATS_TOP_N", 10))
+	viper.SetDefault("stats.stopwords", splitNonEmpty(os.Getenv("STATS_STOPWORDS")))
+
+	viper.SetDefault("safe_mode", getEnvBoolOrDefault("SAFE_MODE", false))
+
+	// Audit defaults
+	viper.SetDefault("audit.destination", getEnvOrDefault("AUDIT_LOG", "stderr"))
+
+	// Extraction self-test defaults
+	viper.SetDefault("self_test.date", getEnvOrDefault("SELF_TEST_DATE", "2025-09-02"))
+	viper.SetDefault("self_test.interval", time.Duration(getEnvIntOrDefault("SELF_TEST_INTERVAL", 0))*time.Second)
+	viper.SetDefault("self_test.jitter", time.Duration(getEnvIntOrDefault("SELF_TEST_JITTER", 60))*time.Second)
+	viper.SetDefault("self_test.min_quality", getEnvIntOrDefault("SELF_TEST_MIN_QUALITY", 50))
+
+	// Privacy defaults
+	viper.SetDefault("privacy.mask_client_ip", getEnvBoolOrDefault("MASK_CLIENT_IP", false))
+	viper.SetDefault("privacy.ip_hash_salt", os.Getenv("CLIENT_IP_HASH_SALT"))
+
+	// Analytics defaults
+	viper.SetDefault("analytics.store", os.Getenv("ANALYTICS_STORE"))
+	viper.SetDefault("analytics.buffer_size", getEnvIntOrDefault("ANALYTICS_BUFFER_SIZE", 1024))
+
+	// Diagnostics defaults
+	viper.SetDefault("diagnostics.history_size", getEnvIntOrDefault("DIAGNOSTICS_HISTORY_SIZE", 100))
+
+	// Scripture text defaults
+	viper.SetDefault("scripture.api_url", os.Getenv("SCRIPTURE_API_URL"))
+	viper.SetDefault("scripture.text_field", getEnvOrDefault("SCRIPTURE_TEXT_FIELD", "text"))
+	viper.SetDefault("scripture.timeout", time.Duration(getEnvIntOrDefault("SCRIPTURE_TIMEOUT", 5))*time.Second)
+	viper.SetDefault("scripture.cache_ttl", time.Duration(getEnvIntOrDefault("SCRIPTURE_CACHE_TTL", 86400))*time.Second)
+
 	// API keys defaults
 	viper.SetDefault("api.flutter_key", getEnvOrDefault("FLUTTER_API_KEY", "sabda_flutter_2025_secure_key"))
 	viper.SetDefault("api.mobile_key", getEnvOrDefault("MOBILE_API_KEY", "sabda_mobile_2025_secure_key"))
-	
+	viper.SetDefault("api.admin_key", getEnvOrDefault("ADMIN_API_KEY", ""))
+	viper.SetDefault("api.warmer_key", getEnvOrDefault("WARMER_API_KEY", ""))
+
 	// CORS defaults
 	allowedOrigins := strings.Split(getEnvOrDefault("ALLOWED_ORIGINS", "*"), ",")
 	viper.SetDefault("cors.allowed_origins", allowedOrigins)
@@ -81,6 +343,75 @@ func setDefaults() {
 	viper.SetDefault("cors.allowed_headers", []string{"Content-Type", "Authorization"})
 }
 
+// resolveCORSPolicies returns the configured CORS policies. CORS_POLICIES (a JSON array) takes
+// precedence over policies from the config file; with neither, the global settings form one policy.
+func resolveCORSPolicies(cfg models.CORSConfig) ([]models.CORSPolicy, error) {
+	if raw := os.Getenv("CORS_POLICIES"); raw != "" {
+		var policies []models.CORSPolicy
+		if err := json.Unmarshal([]byte(raw), &policies); err != nil {
+			return nil, fmt.Errorf("invalid CORS_POLICIES: %w", err)
+		}
+		return policies, nil
+	}
+	if len(cfg.Policies) > 0 {
+		return cfg.Policies, nil
+	}
+	return []models.CORSPolicy{{
+		AllowedOrigins: cfg.AllowedOrigins,
+		AllowedMethods: cfg.AllowedMethods,
+		AllowedHeaders: cfg.AllowedHeaders,
+	}}, nil
+}
+
+// resolveContentSelectors returns the per-publication content selectors. CONTENT_SELECTORS (a JSON
+// object of publication code to selector list) takes precedence over scraper.content_selectors
+// from the config file. Publications without an entry keep the built-in e-SH selectors.
+func resolveContentSelectors(configured map[string][]string) (map[string][]string, error) {
+	if raw := os.Getenv("CONTENT_SELECTORS"); raw != "" {
+		configured = nil
+		if err := json.Unmarshal([]byte(raw), &configured); err != nil {
+			return nil, fmt.Errorf("invalid CONTENT_SELECTORS: %w", err)
+		}
+	}
+	for code, selectors := range configured {
+		if _, ok := scraper.LookupPublication(code); !ok {
+			return nil, fmt.Errorf("invalid scraper.content_selectors: unknown publication %q", code)
+		}
+		if len(selectors) == 0 {
+			return nil, fmt.Errorf("invalid scraper.content_selectors: no selectors for %s", code)
+		}
+		for _, selector := range selectors {
+			if err := scraper.ValidateSelector(selector); err != nil {
+				return nil, fmt.Errorf("invalid scraper.content_selectors for %s: selector %q: %w", code, selector, err)
+			}
+		}
+	}
+	return configured, nil
+}
+
+// resolveTrimPatterns applies LEADING_TRIM_PATTERNS and TRAILING_TRIM_PATTERNS (JSON arrays of
+// regular expressions, [] to disable) over the configured trim patterns and validates them
+func resolveTrimPatterns(cfg *models.ScraperConfig) error {
+	for _, setting := range []struct {
+		env, key string
+		patterns *[]string
+	}{
+		{"LEADING_TRIM_PATTERNS", "scraper.leading_trim_patterns", &cfg.LeadingTrimPatterns},
+		{"TRAILING_TRIM_PATTERNS", "scraper.trailing_trim_patterns", &cfg.TrailingTrimPatterns},
+	} {
+		if raw := os.Getenv(setting.env); raw != "" {
+			*setting.patterns = nil
+			if err := json.Unmarshal([]byte(raw), setting.patterns); err != nil {
+				return fmt.Errorf("invalid %s: %w", setting.env, err)
+			}
+		}
+		if err := scraper.ValidateTrimPatterns(*setting.patterns); err != nil {
+			return fmt.Errorf("invalid %s: %w", setting.key, err)
+		}
+	}
+	return nil
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -88,6 +419,16 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+func splitNonEmpty(value string) []string {
+	var result []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
 func getEnvIntOrDefault(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
@@ -109,10 +450,72 @@ func getEnvBoolOrDefault(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// resolveSecretKey picks the JWT secret in order of precedence:
+// jwt.secret_file, then the env var named by jwt.secret_env, then jwt.secret_key (SECRET_KEY).
+// Without any of these a random secret is generated only if jwt.allow_ephemeral_secret is set.
+func resolveSecretKey(cfg models.JWTConfig) string {
+	if cfg.SecretFile != "" {
+		data, err := os.ReadFile(cfg.SecretFile)
+		if err != nil {
+			log.Fatalf("Unable to read JWT secret file %s: %v", cfg.SecretFile, err)
+		}
+		secret := strings.TrimSpace(string(data))
+		if secret == "" {
+			log.Fatalf("JWT secret file %s is empty", cfg.SecretFile)
+		}
+		log.Printf("JWT secret loaded from file %s", cfg.SecretFile)
+		return secret
+	}
+
+	if cfg.SecretEnv != "" {
+		secret := os.Getenv(cfg.SecretEnv)
+		if secret == "" {
+			log.Fatalf("JWT secret env var %s is not set or empty", cfg.SecretEnv)
+		}
+		log.Printf("JWT secret loaded from env var %s", cfg.SecretEnv)
+		return secret
+	}
+
+	if cfg.SecretKey != "" {
+		return cfg.SecretKey
+	}
+
+	if !cfg.AllowEphemeralSecret {
+		log.Fatalf("No JWT secret configured. Set SECRET_KEY, JWT_SECRET_FILE or JWT_SECRET_ENV, " +
+			"or set ALLOW_EPHEMERAL_SECRET=true for local development")
+	}
+
+	if looksLikeProduction() {
+		log.Println("WARNING: ==========================================================")
+		log.Println("WARNING: Using a randomly generated, ephemeral JWT secret in what looks")
+		log.Println("WARNING: like a production environment. All tokens become invalid on")
+		log.Println("WARNING: restart and replicas will reject each other's tokens.")
+		log.Println("WARNING: Set SECRET_KEY, JWT_SECRET_FILE or JWT_SECRET_ENV.")
+		log.Println("WARNING: ==========================================================")
+	} else {
+		log.Println("Using an ephemeral JWT secret (development mode); tokens will not survive restarts")
+	}
+	return generateSecretKey()
+}
+
+// looksLikeProduction reports whether the process appears to run on a hosting platform
+// or with debug mode off
+func looksLikeProduction() bool {
+	if !getEnvBoolOrDefault("GO_DEBUG", false) {
+		return true
+	}
+	for _, key := range []string{"RENDER", "FLY_APP_NAME", "RAILWAY_ENVIRONMENT", "WEBSITE_SITE_NAME"} {
+		if os.Getenv(key) != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func generateSecretKey() string {
 	bytes := make([]byte, 32)
 	if _, err := rand.Read(bytes); err != nil {
 		log.Fatalf("Failed to generate secret key: %v", err)
 	}
 	return hex.EncodeToString(bytes)
-}
\ No newline at end of file
+}
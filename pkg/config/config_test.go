@@ -0,0 +1,66 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+func TestApplySafeModeOverridesEachSetting(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      func(*models.Config)
+		disabled func(models.Config) bool
+	}{
+		{
+			name:     "scripture.api_url",
+			set:      func(c *models.Config) { c.Scripture.APIURL = "https://bible.example/api" },
+			disabled: func(c models.Config) bool { return c.Scripture.APIURL == "" },
+		},
+		{
+			name:     "cache.refresh_ahead_window",
+			set:      func(c *models.Config) { c.Cache.RefreshAheadWindow = 5 * time.Minute },
+			disabled: func(c models.Config) bool { return c.Cache.RefreshAheadWindow == 0 },
+		},
+		{
+			name:     "self_test.interval",
+			set:      func(c *models.Config) { c.SelfTest.Interval = time.Hour },
+			disabled: func(c models.Config) bool { return c.SelfTest.Interval == 0 },
+		},
+		{
+			name:     "cache.warm_concurrency",
+			set:      func(c *models.Config) { c.Cache.WarmConcurrency = 4 },
+			disabled: func(c models.Config) bool { return c.Cache.WarmConcurrency == 0 },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config models.Config
+			tt.set(&config)
+			applySafeMode(&config)
+			if !tt.disabled(config) {
+				t.Errorf("%s still enabled in safe mode: %+v", tt.name, config)
+			}
+		})
+	}
+}
+
+func TestResolveContentSelectorsRejectsInvalidEntries(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured map[string][]string
+	}{
+		{"unknown publication", map[string][]string{"e-unknown": {"div.isi"}}},
+		{"no selectors", map[string][]string{"e-konsel": {}}},
+		{"invalid selector", map[string][]string{"e-konsel": {"div[class"}}},
+	}
+	for _, tt := range tests {
+		if _, err := resolveContentSelectors(tt.configured); err == nil {
+			t.Errorf("%s: resolveContentSelectors() accepted %v", tt.name, tt.configured)
+		}
+	}
+	if _, err := resolveContentSelectors(map[string][]string{"e-konsel": {"div.isi", "td.wj"}}); err != nil {
+		t.Errorf("resolveContentSelectors() error = %v for valid selectors", err)
+	}
+}
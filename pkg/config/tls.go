@@ -0,0 +1,66 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+// tlsVersions maps the accepted server.tls_min_version values to TLS versions. TLS 1.0 and
+// 1.1 are deliberately absent.
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSConfig builds the tls.Config for serving TLS from the server settings, without the
+// certificate. Versions below TLS 1.2, insecure cipher suites and unknown names are rejected.
+func TLSConfig(cfg models.ServerConfig) (*tls.Config, error) {
+	minVersion, ok := tlsVersions[cfg.TLSMinVersion]
+	if cfg.TLSMinVersion == "1.0" || cfg.TLSMinVersion == "1.1" {
+		return nil, fmt.Errorf("insecure server.tls_min_version %s: must be 1.2 or 1.3", cfg.TLSMinVersion)
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid server.tls_min_version %q: must be 1.2 or 1.3", cfg.TLSMinVersion)
+	}
+	tlsConfig := &tls.Config{MinVersion: minVersion}
+	if len(cfg.TLSCipherSuites) == 0 {
+		return tlsConfig, nil
+	}
+
+	secure := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		secure[suite.Name] = suite.ID
+	}
+	insecure := make(map[string]bool)
+	for _, suite := range tls.InsecureCipherSuites() {
+		insecure[suite.Name] = true
+	}
+	for _, name := range cfg.TLSCipherSuites {
+		name = strings.TrimSpace(name)
+		id, ok := secure[name]
+		if insecure[name] {
+			return nil, fmt.Errorf("insecure cipher suite %s in server.tls_cipher_suites", name)
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite %q in server.tls_cipher_suites", name)
+		}
+		tlsConfig.CipherSuites = append(tlsConfig.CipherSuites, id)
+	}
+	if minVersion == tls.VersionTLS13 {
+		log.Printf("server.tls_cipher_suites has no effect with TLS 1.3 only; its cipher suites are not configurable")
+	}
+	return tlsConfig, nil
+}
+
+// validateTLS checks the TLS serving settings so mistakes fail at startup
+func validateTLS(cfg models.ServerConfig) error {
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return fmt.Errorf("server.tls_cert_file and server.tls_key_file must be set together")
+	}
+	_, err := TLSConfig(cfg)
+	return err
+}
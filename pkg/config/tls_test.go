@@ -0,0 +1,40 @@
+package config
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+func TestTLSConfig(t *testing.T) {
+	tlsConfig, err := TLSConfig(models.ServerConfig{TLSMinVersion: "1.2", TLSCipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}})
+	if err != nil {
+		t.Fatalf("TLSConfig() error = %v", err)
+	}
+	if tlsConfig.MinVersion != tls.VersionTLS12 {
+		t.Errorf("MinVersion = %x, want TLS 1.2", tlsConfig.MinVersion)
+	}
+	if len(tlsConfig.CipherSuites) != 1 || tlsConfig.CipherSuites[0] != tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 {
+		t.Errorf("CipherSuites = %v, want only the allowed suite", tlsConfig.CipherSuites)
+	}
+	if tlsConfig, err := TLSConfig(models.ServerConfig{TLSMinVersion: "1.3"}); err != nil || tlsConfig.MinVersion != tls.VersionTLS13 {
+		t.Errorf("TLSConfig(1.3) = %v, %v; want TLS 1.3", tlsConfig, err)
+	}
+
+	invalid := []struct {
+		name string
+		cfg  models.ServerConfig
+	}{
+		{"TLS 1.1", models.ServerConfig{TLSMinVersion: "1.1"}},
+		{"unknown version", models.ServerConfig{TLSMinVersion: "2.0"}},
+		{"insecure suite", models.ServerConfig{TLSMinVersion: "1.2", TLSCipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}},
+		{"unknown suite", models.ServerConfig{TLSMinVersion: "1.2", TLSCipherSuites: []string{"TLS_MADE_UP"}}},
+		{"cert without key", models.ServerConfig{TLSMinVersion: "1.2", TLSCertFile: "server.crt"}},
+	}
+	for _, tt := range invalid {
+		if err := validateTLS(tt.cfg); err == nil {
+			t.Errorf("%s: validateTLS() accepted the settings", tt.name)
+		}
+	}
+}
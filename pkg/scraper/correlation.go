@@ -0,0 +1,47 @@
+package scraper
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+)
+
+// Correlation carries identifiers that tie an upstream fetch back to the API request that caused it
+type Correlation struct {
+	RequestID   string
+	TraceParent string
+}
+
+type correlationKey struct{}
+
+// WithCorrelation returns a context carrying the given correlation identifiers
+func WithCorrelation(ctx context.Context, correlation Correlation) context.Context {
+	return context.WithValue(ctx, correlationKey{}, correlation)
+}
+
+// CorrelationFromContext returns the correlation identifiers stored in ctx, if any
+func CorrelationFromContext(ctx context.Context) Correlation {
+	correlation, _ := ctx.Value(correlationKey{}).(Correlation)
+	return correlation
+}
+
+// traceParentRegex matches a W3C trace context traceparent header (version 00)
+var traceParentRegex = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})$`)
+
+// childTraceParent returns a traceparent for an outbound call. It keeps the trace ID and
+// flags of a valid incoming traceparent with a fresh parent ID, or starts a new sampled trace.
+func childTraceParent(incoming string) string {
+	if match := traceParentRegex.FindStringSubmatch(incoming); match != nil && match[1] != "00000000000000000000000000000000" {
+		return "00-" + match[1] + "-" + randomHex(8) + "-" + match[3]
+	}
+	return "00-" + randomHex(16) + "-" + randomHex(8) + "-01"
+}
+
+func randomHex(n int) string {
+	bytes := make([]byte, n)
+	if _, err := rand.Read(bytes); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(bytes)
+}
@@ -0,0 +1,747 @@
+package scraper
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+// Extractor pulls devotional fields out of a fetched page. Extract reports false when the page
+// does not match what the strategy understands, so the next extractor in the list is tried.
+// Derived fields (full text, counts, content hash) are filled in by the scraper afterwards.
+type Extractor interface {
+	Name() string
+	Extract(page *goquery.Selection) (*models.DevotionalContent, bool)
+}
+
+// DefaultBookPattern matches the book name of a scripture reference: a single word, optionally
+// preceded by a book number as in "1 Korintus" or "2 Samuel"
+const DefaultBookPattern = `(?:[1-3]\s?)?[A-Za-z]+`
+
+// DefaultContentSelectors are the content containers of e-SH's current markup, in the order
+// they are tried: the article aside, also when it wraps the devotional in tables, the print
+// page's text cell, then any table cell
+var DefaultContentSelectors = []string{"aside.w:has(p)", "aside.w:has(td)", "td.wj", "table td"}
+
+// defaultExtractor applies the heuristics that handle the current sabda.org layouts
+type defaultExtractor struct {
+	// containers are the content container selectors, tried in order
+	containers []cascadia.Selector
+	// headingRef finds a reference, with or without verses, in the page heading
+	headingRef *regexp.Regexp
+	// bodyRef finds a chapter:verse reference in the body text
+	bodyRef *regexp.Regexp
+	// headingPrefix splits a heading that starts with a reference into reference and title
+	headingPrefix *regexp.Regexp
+}
+
+// DefaultExtractor returns the built-in extraction strategy. It accepts every page,
+// so it belongs last in a prioritized extractor list.
+func DefaultExtractor() Extractor {
+	extractor, _ := NewDefaultExtractor(DefaultBookPattern)
+	return extractor
+}
+
+// NewDefaultExtractor returns the built-in extraction strategy recognizing scripture references
+// whose book name matches bookPattern, so other locales' naming can be supported
+func NewDefaultExtractor(bookPattern string) (Extractor, error) {
+	return NewSelectorExtractor(bookPattern, DefaultContentSelectors)
+}
+
+// NewSelectorExtractor returns the built-in extraction strategy reading the devotional from the
+// first of selectors that matches the page, for publications whose layout differs from e-SH.
+// Among the matches of a selector the element with the most text is used; the page body is
+// used when no selector matches.
+func NewSelectorExtractor(bookPattern string, selectors []string) (Extractor, error) {
+	headingRef, err := regexp.Compile(`\b((?:` + bookPattern + `)\s+\d+(?::\d+(?:-\d+)?)?)`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scripture book pattern: %w", err)
+	}
+	containers := make([]cascadia.Selector, 0, len(selectors))
+	for _, selector := range selectors {
+		compiled, err := cascadia.Compile(selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid content selector %q: %w", selector, err)
+		}
+		containers = append(containers, compiled)
+	}
+	return &defaultExtractor{
+		containers:    containers,
+		headingRef:    headingRef,
+		bodyRef:       regexp.MustCompile(`\b((?:` + bookPattern + `)\s+\d+:\d+(?:-\d+)?)`),
+		headingPrefix: regexp.MustCompile(`^((?:` + bookPattern + `)\s+\d+(?::\d+(?:-\d+)?)?)(.*)`),
+	}, nil
+}
+
+// ValidateSelector reports whether selector is a CSS selector the extractor can use
+func ValidateSelector(selector string) error {
+	_, err := cascadia.Compile(selector)
+	return err
+}
+
+func (x *defaultExtractor) Name() string {
+	return "default"
+}
+
+func (x *defaultExtractor) Extract(page *goquery.Selection) (*models.DevotionalContent, bool) {
+	content := &models.DevotionalContent{}
+
+	title := strings.TrimSpace(page.Find("title").Text())
+	if title == "" {
+		title = "SABDA Devotional"
+	}
+	content.Title = strings.TrimSpace(title)
+	content.Edition = x.extractEdition(page)
+
+	mainContent := x.findContainer(page)
+
+	allText := mainContent.Text()
+	log.Printf("Raw text length: %d", len(allText))
+	if len(allText) > 0 {
+		log.Printf("First 500 chars: %s", allText[:min(500, len(allText))])
+	}
+
+	htmlContent, _ := mainContent.Html()
+	log.Printf("HTML content length: %d", len(htmlContent))
+
+	cleanText := x.cleanText(allText)
+	log.Printf("Clean text length: %d", len(cleanText))
+
+	if len(cleanText) < 100 {
+		log.Printf("Warning: Very little content extracted, page might not have loaded properly")
+	}
+
+	content.Images = contentImages(mainContent)
+
+	// Weekend editions combine several devotionals on one page, each under its own h1
+	if x.extractEntries(content, page.Find("h1")) {
+		return content, true
+	}
+
+	scriptureRef := ""
+	if h1 := page.Find("h1"); h1.Length() > 0 {
+		h1Text := h1.Text()
+
+		if match := x.headingRef.FindStringSubmatch(h1Text); len(match) > 1 {
+			scriptureRef = match[1]
+		}
+	}
+
+	if scriptureRef == "" {
+		if match := x.bodyRef.FindStringSubmatch(cleanText); len(match) > 1 {
+			scriptureRef = match[1]
+		}
+	}
+
+	content.ScriptureReference = scriptureRef
+
+	devotionalTitle := ""
+	if h1 := page.Find("h1"); h1.Length() > 0 {
+		h1Text := strings.TrimSpace(h1.Text())
+
+		if scriptureRef == "" {
+			if match := x.headingPrefix.FindStringSubmatch(h1Text); len(match) > 2 {
+				scriptureRef = strings.TrimSpace(match[1])
+				devotionalTitle = strings.TrimSpace(match[2])
+			}
+		} else {
+
+			h1Text = strings.ReplaceAll(h1Text, scriptureRef, "")
+			devotionalTitle = strings.TrimSpace(h1Text)
+		}
+
+		if devotionalTitle != "" {
+
+			devotionalTitle = regexp.MustCompile(`^-\d+`).ReplaceAllString(devotionalTitle, "")
+			devotionalTitle = strings.TrimSpace(devotionalTitle)
+		}
+
+		if devotionalTitle != "" && len(devotionalTitle) > 3 {
+
+		} else if h1Text != "" && len(h1Text) > 3 {
+
+			h1Text = regexp.MustCompile(`^-\d+`).ReplaceAllString(h1Text, "")
+			devotionalTitle = strings.TrimSpace(h1Text)
+		}
+	}
+
+	if devotionalTitle == "" {
+		devotionalTitle = x.extractDevotionalTitle(cleanText, scriptureRef)
+	}
+	content.DevotionalTitle = devotionalTitle
+
+	content.ScriptureReference = scriptureRef
+	content.ScriptureText = x.extractScriptureText(mainContent, cleanText, scriptureRef)
+
+	body := x.extractParagraphs(mainContent)
+	content.DevotionalContent, content.DevotionalHTML = body.texts, body.html
+	content.SourceTag, content.Author = body.sourceTag, body.author
+	content.FiltersApplied = &body.filters
+	content.ParagraphSource = body.source
+	content.ScriptureReading, content.Reflection = splitReading(body)
+
+	if len(content.DevotionalContent) == 0 {
+		content.DevotionalContent = x.extractParagraphsFromText(cleanText, content.FiltersApplied)
+		content.DevotionalHTML = escapeParagraphs(content.DevotionalContent)
+		content.ParagraphSource = ParagraphsFromText
+	}
+
+	return content, true
+}
+
+// cleanText returns the non-empty lines of text, trimmed, without site header lines
+func (x *defaultExtractor) cleanText(text string) string {
+	var cleanLines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !x.isHeaderContent(strings.ToLower(line)) {
+			cleanLines = append(cleanLines, line)
+		}
+	}
+	return strings.Join(cleanLines, "\n")
+}
+
+// leadingVerseSuffixRegex matches the rest of a verse range left at the start of a title
+// when the heading reference was matched without it
+var leadingVerseSuffixRegex = regexp.MustCompile(`^-\d+`)
+
+// findContainer returns the element holding the devotional: the match with the most text of
+// the first container selector matching anything with text, or else the page body
+func (x *defaultExtractor) findContainer(page *goquery.Selection) *goquery.Selection {
+	for _, container := range x.containers {
+		var largest *goquery.Selection
+		maxLength := 0
+		page.FindMatcher(container).Each(func(_ int, candidate *goquery.Selection) {
+			if length := len(strings.TrimSpace(candidate.Text())); length > maxLength {
+				maxLength = length
+				largest = candidate
+			}
+		})
+		if largest != nil {
+			return largest
+		}
+	}
+	return page.Find("body").First()
+}
+
+// extractEntries splits a page combining several devotionals at its h1 headings, each
+// section running up to the next heading, and fills content with the entries and their
+// concatenation. It reports false, leaving content alone, unless at least two sections
+// have paragraphs.
+func (x *defaultExtractor) extractEntries(content *models.DevotionalContent, headings *goquery.Selection) bool {
+	if headings.Length() < 2 {
+		return false
+	}
+
+	var entries []models.DevotionalEntry
+	var sets []paragraphSet
+	var filters models.FilterCounts
+	headings.Each(func(_ int, h1 *goquery.Selection) {
+		var sectionHTML strings.Builder
+		h1.NextUntil("h1").Each(func(_ int, node *goquery.Selection) {
+			if outer, err := goquery.OuterHtml(node); err == nil {
+				sectionHTML.WriteString(outer)
+			}
+		})
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader("<div>" + sectionHTML.String() + "</div>"))
+		if err != nil {
+			return
+		}
+		section := doc.Find("div").First()
+
+		body := x.extractParagraphs(section)
+		filters.Add(body.filters)
+		if len(body.texts) == 0 {
+			return
+		}
+		sectionText := x.cleanText(section.Text())
+
+		heading := strings.TrimSpace(h1.Text())
+		ref := ""
+		if match := x.headingRef.FindStringSubmatch(heading); len(match) > 1 {
+			ref = match[1]
+		} else if match := x.bodyRef.FindStringSubmatch(sectionText); len(match) > 1 {
+			ref = match[1]
+		}
+		title := strings.TrimSpace(strings.Replace(heading, ref, "", 1))
+		title = strings.TrimSpace(leadingVerseSuffixRegex.ReplaceAllString(title, ""))
+
+		entries = append(entries, models.DevotionalEntry{
+			ScriptureReference: ref,
+			ScriptureText:      x.extractScriptureText(section, sectionText, ref),
+			DevotionalTitle:    title,
+			DevotionalContent:  body.texts,
+			DevotionalHTML:     body.html,
+		})
+		sets = append(sets, body)
+	})
+	if len(entries) < 2 {
+		return false
+	}
+
+	var refs, titles, texts []string
+	for _, entry := range entries {
+		if entry.ScriptureReference != "" {
+			refs = append(refs, entry.ScriptureReference)
+		}
+		if entry.DevotionalTitle != "" {
+			titles = append(titles, entry.DevotionalTitle)
+		}
+		if entry.ScriptureText != "" {
+			texts = append(texts, entry.ScriptureText)
+		}
+	}
+	content.Combined = true
+	content.Entries = entries
+	content.ParagraphSource = ParagraphsFromEntries
+	content.FiltersApplied = &filters
+	content.ScriptureReference = strings.Join(refs, "; ")
+	content.DevotionalTitle = strings.Join(titles, " / ")
+	content.ScriptureText = strings.Join(texts, " ")
+	content.DevotionalContent, content.DevotionalHTML = flattenEntries(entries)
+	last := sets[len(sets)-1]
+	content.SourceTag, content.Author = last.sourceTag, last.author
+	return true
+}
+
+// splitReading separates the paragraphs of body set in the scripture reading block from the
+// reflection. Both are nil unless the page has paragraphs on either side of that divide.
+func splitReading(body paragraphSet) ([]string, []string) {
+	var reading, reflection []string
+	for i, inReading := range body.reading {
+		if inReading {
+			reading = append(reading, body.texts[i])
+		} else {
+			reflection = append(reflection, body.texts[i])
+		}
+	}
+	if len(reading) == 0 || len(reflection) == 0 {
+		return nil, nil
+	}
+	return reading, reflection
+}
+
+// flattenEntries concatenates the paragraphs and paragraph HTML of entries in page order
+func flattenEntries(entries []models.DevotionalEntry) ([]string, []string) {
+	var paragraphs, html []string
+	for _, entry := range entries {
+		paragraphs = append(paragraphs, entry.DevotionalContent...)
+		html = append(html, entry.DevotionalHTML...)
+	}
+	return paragraphs, html
+}
+
+func (x *defaultExtractor) extractDevotionalTitle(text, scriptureRef string) string {
+
+	if scriptureRef != "" {
+
+		scripturePattern := regexp.MustCompile(regexp.QuoteMeta(scriptureRef) + `([A-Za-z][^,.\n]*?)(?:\s|$)`)
+		match := scripturePattern.FindStringSubmatch(text)
+		if len(match) > 1 {
+			title := strings.TrimSpace(match[1])
+
+			title = regexp.MustCompile(`^-?\d*`).ReplaceAllString(title, "")
+			title = regexp.MustCompile(`\s{2,}`).ReplaceAllString(title, " ")
+			title = strings.TrimSpace(title)
+
+			if len(title) > 2 && len(title) < 100 {
+				return title
+			}
+		}
+	}
+
+	lines := strings.Split(text, "\n")
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if len(line) < 3 || len(line) > 50 ||
+			strings.HasPrefix(strings.ToLower(line), "ketika") ||
+			strings.Contains(strings.ToLower(line), "diperhadapkan") ||
+			strings.Contains(strings.ToLower(line), "sabda") ||
+			strings.Contains(strings.ToLower(line), "publikasi") ||
+			strings.Contains(strings.ToLower(line), "http") ||
+			strings.Contains(line, scriptureRef) {
+			continue
+		}
+
+		if regexp.MustCompile(`^[A-Z][a-zA-Z\s!?]*$`).MatchString(line) {
+			return line
+		}
+	}
+
+	return ""
+}
+
+// whitespaceRegex matches runs of whitespace, collapsed to one space in verse candidates
+var whitespaceRegex = regexp.MustCompile(`\s+`)
+
+// verseContainerSelector matches the block elements one of which carries the scripture
+// reference; italics quoting the verse are only looked for within it, since elsewhere in the
+// body they mark emphasis or titles
+const verseContainerSelector = "p, div, td, li"
+
+func (x *defaultExtractor) extractScriptureText(selection *goquery.Selection, text, scriptureRef string) string {
+	candidates := selection.Find(readingBlockSelector)
+	if container := referenceContainer(selection, scriptureRef); container != nil {
+		candidates = candidates.AddSelection(container.Find("i, em"))
+	}
+
+	verseText := ""
+	candidates.EachWithBreak(func(i int, el *goquery.Selection) bool {
+		candidate := whitespaceRegex.ReplaceAllString(strings.TrimSpace(el.Text()), " ")
+		if len(candidate) < 30 || x.isDonationContent(candidate) || x.isHeaderContent(strings.ToLower(candidate)) {
+			return true
+		}
+		verseText = candidate
+		return false
+	})
+	if verseText != "" {
+		return strings.Trim(verseText, "\"“” ")
+	}
+
+	if scriptureRef == "" {
+		return ""
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if !strings.Contains(line, scriptureRef) || i+1 >= len(lines) {
+			continue
+		}
+		next := strings.TrimSpace(lines[i+1])
+		if strings.HasPrefix(next, "\"") || strings.HasPrefix(next, "“") {
+			return strings.Trim(next, "\"“” ")
+		}
+		break
+	}
+
+	return ""
+}
+
+// referenceContainer returns the innermost block element of selection whose text contains
+// scriptureRef, or nil when there is none
+func referenceContainer(selection *goquery.Selection, scriptureRef string) *goquery.Selection {
+	if scriptureRef == "" {
+		return nil
+	}
+	var container *goquery.Selection
+	selection.Find(verseContainerSelector).EachWithBreak(func(i int, el *goquery.Selection) bool {
+		if !strings.Contains(el.Text(), scriptureRef) {
+			return true
+		}
+		container = el
+		return el.Find(verseContainerSelector).FilterFunction(func(i int, inner *goquery.Selection) bool {
+			return strings.Contains(inner.Text(), scriptureRef)
+		}).Length() > 0
+	})
+	return container
+}
+
+// editionRegex matches the issue shown on a page, e.g. "Edisi 2 Sep 2025" or "Edisi: 150"
+var editionRegex = regexp.MustCompile(`(?i)\bedisi\s*(?:no\.?\s*)?:?\s*(\d{1,2}\s+[A-Za-z]{3,9}\s+\d{4}|\d+)`)
+
+// extractEdition returns the edition shown in the page title or headings, if any
+func (x *defaultExtractor) extractEdition(page *goquery.Selection) string {
+	for _, text := range []string{page.Find("title").Text(), page.Find("h1, h2, h3").Text()} {
+		if match := editionRegex.FindStringSubmatch(text); match != nil {
+			return strings.Join(strings.Fields(match[1]), " ")
+		}
+	}
+	return ""
+}
+
+// trailingTagRegex matches a closing bracketed tag such as "[SH]" at the end of a paragraph
+var trailingTagRegex = regexp.MustCompile(`\s*\[([\w\s]+)\]\s*$`)
+
+// authorLineRegex matches a standalone attribution line such as "-- Pdt. Yohanes" or "Penulis: Yohanes"
+var authorLineRegex = regexp.MustCompile(`(?i)^(?:-{2,}|—|–|(?:penulis|oleh|ditulis oleh|kontributor)\s*:)\s*(\S.{1,78})$`)
+
+// trailingAuthorRegex matches an attribution appended to the final paragraph, e.g. "... hari. -- Yohanes"
+var trailingAuthorRegex = regexp.MustCompile(`\s+(?:-{2,}|—)\s*([A-Z][\w.,']*(?:\s+[\w.,']+){0,5})\s*$`)
+
+// paragraphSet is the body text of a page along with the attribution stripped from it and
+// the counts of what the filters dropped. reading flags the paragraphs found inside the
+// scripture reading block; it is nil when the paragraphs were not read from elements.
+type paragraphSet struct {
+	texts     []string
+	html      []string
+	reading   []bool
+	sourceTag string
+	author    string
+	filters   models.FilterCounts
+	source    string
+}
+
+// readingBlockSelector matches the block some layouts, notably the print page, set the
+// scripture reading in apart from the reflection
+const readingBlockSelector = "blockquote, .ayat, .nas, .verse"
+
+// Paragraph sources: where the default extractor found a devotional's paragraphs. The text
+// splitter is the last resort and yields the poorest paragraphs.
+const (
+	ParagraphsFromElements = "paragraphs"
+	ParagraphsFromCells    = "table_cells"
+	ParagraphsFromText     = "text_splitter"
+	ParagraphsFromEntries  = "entries"
+)
+
+// ParagraphSources lists every paragraph source in reporting order
+var ParagraphSources = []string{
+	ParagraphsFromElements,
+	ParagraphsFromCells,
+	ParagraphsFromText,
+	ParagraphsFromEntries,
+}
+
+// cellSkipSelector matches what a table cell shows besides body text: headings and the
+// scripture quote, which are extracted separately
+const cellSkipSelector = "h1, h2, h3, h4, h5, h6, blockquote"
+
+// extractParagraphs returns the paragraph texts and their sanitized HTML, with the closing
+// bracketed tag and any author attribution removed and reported separately. Pages without <p>
+// elements read each innermost table cell as a paragraph before the text splitter is used.
+func (x *defaultExtractor) extractParagraphs(selection *goquery.Selection) paragraphSet {
+	var paragraphs []string
+	var htmlParagraphs []string
+	var reading []bool
+	var filters models.FilterCounts
+	author := ""
+	source := ParagraphsFromElements
+
+	elements := selection.Find("p, P")
+	if elements.Length() == 0 {
+		source = ParagraphsFromCells
+		// Copies of the innermost cells, so leaving out headings and the quote keeps the page intact
+		elements = selection.Find("td, th").FilterFunction(func(_ int, cell *goquery.Selection) bool {
+			return cell.Find("td, th").Length() == 0
+		}).Clone()
+		elements.Find(cellSkipSelector).Remove()
+		if elements.Length() > 0 {
+			log.Printf("No <p> elements, reading %d table cells", elements.Length())
+		}
+	}
+	elements.Each(func(i int, p *goquery.Selection) {
+		text := strings.TrimSpace(p.Text())
+
+		if text == "" || text == "\u00a0" {
+			return
+		}
+
+		if align, exists := p.Attr("align"); exists && align == "center" {
+			filters.Centered++
+			return
+		}
+
+		if x.isDonationContent(text) {
+			filters.Donation++
+			return
+		}
+
+		if match := authorLineRegex.FindStringSubmatch(text); match != nil {
+			author = strings.TrimSpace(match[1])
+			return
+		}
+
+		if len(text) < 50 {
+			filters.TooShort++
+			return
+		}
+
+		text = regexp.MustCompile(`\s{2,}`).ReplaceAllString(text, " ")
+		paragraphs = append(paragraphs, text)
+		reading = append(reading, p.Closest(readingBlockSelector).Length() > 0)
+
+		innerHTML, err := p.Html()
+		if err != nil {
+			innerHTML = html.EscapeString(text)
+		}
+		htmlParagraphs = append(htmlParagraphs, sanitizeHTML(innerHTML))
+	})
+
+	if len(paragraphs) <= 1 {
+		log.Println("Using text-based paragraph extraction")
+		paragraphs = x.extractParagraphsFromText(selection.Text(), &filters)
+		htmlParagraphs = escapeParagraphs(paragraphs)
+		reading = nil
+		source = ParagraphsFromText
+	}
+
+	var cleanedParagraphs []string
+	var cleanedHTML []string
+	var cleanedReading []bool
+	sourceTag := ""
+	for i, para := range paragraphs {
+
+		if match := trailingTagRegex.FindStringSubmatch(para); match != nil {
+			sourceTag = strings.TrimSpace(match[1])
+		}
+		para = trailingTagRegex.ReplaceAllString(para, "")
+		para = strings.TrimSpace(para)
+
+		if len(para) > 50 {
+			cleanedParagraphs = append(cleanedParagraphs, para)
+			paraHTML := trailingTagRegex.ReplaceAllString(htmlParagraphs[i], "")
+			cleanedHTML = append(cleanedHTML, strings.TrimSpace(paraHTML))
+			if reading != nil {
+				cleanedReading = append(cleanedReading, reading[i])
+			}
+		} else {
+			filters.TooShort++
+		}
+	}
+
+	if last := len(cleanedParagraphs) - 1; author == "" && last >= 0 {
+		if match := trailingAuthorRegex.FindStringSubmatch(cleanedParagraphs[last]); match != nil {
+			author = strings.TrimSpace(match[1])
+			cleanedParagraphs[last] = strings.TrimSpace(trailingAuthorRegex.ReplaceAllString(cleanedParagraphs[last], ""))
+			cleanedHTML[last] = strings.TrimSpace(trailingAuthorRegex.ReplaceAllString(cleanedHTML[last], ""))
+		}
+	}
+
+	return paragraphSet{
+		texts:     cleanedParagraphs,
+		html:      cleanedHTML,
+		reading:   cleanedReading,
+		sourceTag: sourceTag,
+		author:    author,
+		filters:   filters,
+		source:    source,
+	}
+}
+
+// extractParagraphsFromText splits unstructured page text into paragraphs, counting the lines
+// it drops in filters
+func (x *defaultExtractor) extractParagraphsFromText(text string, filters *models.FilterCounts) []string {
+	var paragraphs []string
+
+	lines := strings.Split(text, "\n")
+	var textLines []string
+	foundContentStart := false
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		lineLower := strings.ToLower(line)
+
+		if !foundContentStart {
+			if strings.Contains(lineLower, "lukas") || strings.Contains(lineLower, "matius") ||
+				strings.Contains(lineLower, "markus") || strings.Contains(lineLower, "yohanes") {
+				foundContentStart = true
+			}
+			continue
+		}
+
+		if x.isDonationContent(line) {
+			filters.Donation++
+			break
+		}
+
+		if x.isHeaderContent(lineLower) {
+			filters.Header++
+			continue
+		}
+
+		if len(line) > 15 {
+			textLines = append(textLines, line)
+		} else if line != "" {
+			filters.TooShort++
+		}
+	}
+
+	contentText := strings.Join(textLines, " ")
+
+	if len(contentText) > 300 {
+
+		sentences := regexp.MustCompile(`(?:[.!?])\s+(?=[A-Z])`).Split(contentText, -1)
+		var currentPara []string
+
+		for _, sentence := range sentences {
+			sentence = strings.TrimSpace(sentence)
+			if sentence == "" {
+				continue
+			}
+
+			currentPara = append(currentPara, sentence)
+
+			if len(strings.Join(currentPara, " ")) > 200 {
+				paraText := strings.Join(currentPara, " ")
+				if len(paraText) > 100 {
+					paragraphs = append(paragraphs, paraText)
+					currentPara = []string{}
+				}
+			}
+		}
+
+		if len(currentPara) > 0 {
+			paraText := strings.Join(currentPara, " ")
+			if len(paraText) > 100 {
+				paragraphs = append(paragraphs, paraText)
+			}
+		}
+	}
+
+	if len(paragraphs) <= 1 && len(contentText) > 0 {
+		words := strings.Fields(contentText)
+		if len(words) > 150 {
+			third := len(words) / 3
+			para1 := strings.Join(words[:third], " ")
+			para2 := strings.Join(words[third:2*third], " ")
+			para3 := strings.Join(words[2*third:], " ")
+
+			paragraphs = []string{
+				strings.TrimSpace(para1),
+				strings.TrimSpace(para2),
+				strings.TrimSpace(para3),
+			}
+		} else if contentText != "" {
+			paragraphs = []string{strings.TrimSpace(contentText)}
+		}
+	}
+
+	return paragraphs
+}
+
+func (x *defaultExtractor) isDonationContent(text string) bool {
+	textLower := strings.ToLower(text)
+	donationPatterns := []string{
+		"mari memberkati",
+		"pancar pijar alkitab",
+		"bca 106.30066.22",
+		"yayasan lembaga sabda",
+		"webmaster@",
+		"ylsa.org",
+		"copyright",
+		"© ",
+		"santapan harian",
+	}
+
+	for _, pattern := range donationPatterns {
+		if strings.Contains(textLower, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func (x *defaultExtractor) isHeaderContent(text string) bool {
+	headerPatterns := []string{
+		"sabda.org",
+		"publikasi",
+		"versi cetak",
+		"http://",
+		"https://",
+		"halaman ini adalah versi",
+	}
+
+	for _, pattern := range headerPatterns {
+		if strings.Contains(text, pattern) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,265 @@
+package scraper
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+// extractPage runs the default extractor over page, failing the test if it declines
+func extractPage(t *testing.T, page string) *models.DevotionalContent {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	content, ok := DefaultExtractor().Extract(doc.Find("html"))
+	if !ok {
+		t.Fatalf("default extractor declined the page")
+	}
+	return content
+}
+
+func TestExtractScriptureText(t *testing.T) {
+	x := DefaultExtractor().(*defaultExtractor)
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "highlighted block",
+			body: `<p>Bacaan: Yohanes 3:16</p>
+				<blockquote>"Karena begitu besar kasih Allah akan dunia ini, sehingga Ia telah mengaruniakan Anak-Nya."</blockquote>
+				<p>Renungan tentang kasih yang tidak bersyarat dan tidak pernah berubah.</p>`,
+			want: "Karena begitu besar kasih Allah akan dunia ini, sehingga Ia telah mengaruniakan Anak-Nya.",
+		},
+		{
+			name: "italics beside the reference",
+			body: `<p>Kita sering membaca <em>buku-buku rohani yang populer dan laris di toko</em> tanpa merenungkannya.</p>
+				<div><p>Yohanes 3:16 <i>Karena begitu besar kasih Allah akan dunia ini</i></p></div>`,
+			want: "Karena begitu besar kasih Allah akan dunia ini",
+		},
+		{
+			name: "italics elsewhere only",
+			body: `<p>Yohanes 3:16</p>
+				<p>Kita sering membaca <em>buku-buku rohani yang populer dan laris di toko</em> tanpa merenungkannya.</p>`,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader("<html><body>" + tt.body + "</body></html>"))
+		if err != nil {
+			t.Fatalf("%s: parse failed: %v", tt.name, err)
+		}
+		body := doc.Find("body")
+		if got := x.extractScriptureText(body, body.Text(), "Yohanes 3:16"); got != tt.want {
+			t.Errorf("%s: ScriptureText = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExtractKeepsSafeInlineHTML(t *testing.T) {
+	content := extractPage(t, `<html><body><aside class="w">
+		<h1>Lukas 13:18-21 Allah Bekerja Memakai Hal Kecil</h1>
+		<p>Ketika kita diperhadapkan dengan <b>hal-hal besar</b>, kita lupa bahwa <a href="https://alkitab.sabda.org/">Allah bekerja</a> melalui hal kecil.</p>
+		<p>Biji sesawi adalah benih yang sangat kecil<script>alert("x")</script>, tetapi ketika tumbuh ia menjadi <em onclick="steal()">pohon</em> yang besar.</p>
+		</aside></body></html>`)
+
+	if len(content.DevotionalHTML) != 2 {
+		t.Fatalf("DevotionalHTML = %q, want 2 paragraphs", content.DevotionalHTML)
+	}
+	first, second := content.DevotionalHTML[0], content.DevotionalHTML[1]
+	for _, want := range []string{"<b>hal-hal besar</b>", `<a href="https://alkitab.sabda.org/"`, ">Allah bekerja</a>"} {
+		if !strings.Contains(first, want) {
+			t.Errorf("DevotionalHTML[0] = %q, want it to contain %q", first, want)
+		}
+	}
+	for _, unsafe := range []string{"<script", "alert", "onclick"} {
+		if strings.Contains(second, unsafe) {
+			t.Errorf("DevotionalHTML[1] = %q, still contains %q", second, unsafe)
+		}
+	}
+	if !strings.Contains(second, "<em>pohon</em>") {
+		t.Errorf("DevotionalHTML[1] = %q, want the emphasis kept", second)
+	}
+}
+
+func TestExtractCapturesClosingSourceTag(t *testing.T) {
+	content := extractPage(t, `<html><body><aside class="w">
+		<h1>Lukas 13:18-21 Allah Bekerja Memakai Hal Kecil</h1>
+		<p>Ketika kita diperhadapkan dengan hal-hal besar, sering kali kita lupa bahwa Allah bekerja melalui hal kecil.</p>
+		<p>Biji sesawi adalah benih yang sangat kecil, tetapi ketika tumbuh ia menjadi pohon yang besar. [SH]</p>
+		</aside></body></html>`)
+
+	if content.SourceTag != "SH" {
+		t.Errorf("SourceTag = %q, want SH", content.SourceTag)
+	}
+	last := content.DevotionalContent[len(content.DevotionalContent)-1]
+	if strings.Contains(last, "[SH]") || !strings.HasSuffix(last, "pohon yang besar.") {
+		t.Errorf("last paragraph = %q, want the tag removed", last)
+	}
+	if html := content.DevotionalHTML[len(content.DevotionalHTML)-1]; strings.Contains(html, "[SH]") {
+		t.Errorf("last HTML paragraph = %q, want the tag removed", html)
+	}
+}
+
+func TestExtractAuthor(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/esh_attributed.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		page   string
+		author string
+	}{
+		{"attribution line", string(fixture), "Pdt. Yohanes Susanto"},
+		{"appended to the last paragraph", `<html><body><aside class="w"><h1>Mazmur 23:1-6 Gembala yang Baik</h1>
+			<p>Daud mengenal Tuhan sebagai gembala yang memelihara domba-dombanya setiap hari tanpa lelah.</p>
+			<p>Di padang yang berumput hijau dan di air yang tenang, Tuhan menyegarkan jiwa kita. -- Yohanes</p>
+			</aside></body></html>`, "Yohanes"},
+		{"absent", `<html><body><aside class="w"><h1>Mazmur 23:1-6 Gembala yang Baik</h1>
+			<p>Daud mengenal Tuhan sebagai gembala yang memelihara domba-dombanya setiap hari tanpa lelah.</p>
+			<p>Di padang yang berumput hijau dan di air yang tenang, Tuhan menyegarkan jiwa kita kembali.</p>
+			</aside></body></html>`, ""},
+	}
+	for _, tt := range tests {
+		content := extractPage(t, tt.page)
+		if content.Author != tt.author {
+			t.Errorf("%s: Author = %q, want %q", tt.name, content.Author, tt.author)
+		}
+		if len(content.DevotionalContent) != 2 {
+			t.Errorf("%s: paragraphs = %q, want 2", tt.name, content.DevotionalContent)
+		}
+		for _, para := range content.DevotionalContent {
+			if tt.author != "" && strings.Contains(para, tt.author) {
+				t.Errorf("%s: attribution left in paragraph %q", tt.name, para)
+			}
+		}
+	}
+}
+
+func TestExtractNumberedBookReferences(t *testing.T) {
+	paragraphs := `<p>Kasih itu sabar; kasih itu murah hati; ia tidak cemburu dan tidak memegahkan diri.</p>
+		<p>Paulus menulis kepada jemaat yang terpecah supaya mereka kembali mengasihi satu sama lain.</p>`
+	tests := []struct {
+		name string
+		page string
+		want string
+	}{
+		{"heading", `<html><body><aside class="w"><h1>1 Korintus 13:4-7 Kasih Itu Sabar</h1>` + paragraphs + `</aside></body></html>`, "1 Korintus 13:4-7"},
+		{"heading without space", `<html><body><aside class="w"><h1>2 Samuel 7:1-17Rumah bagi Tuhan</h1>` + paragraphs + `</aside></body></html>`, "2 Samuel 7:1-17"},
+		{"body", `<html><body><aside class="w"><h1>Kasih Itu Sabar</h1><p>Bacaan: 3 Yohanes 1:2-4</p>` + paragraphs + `</aside></body></html>`, "3 Yohanes 1:2-4"},
+	}
+	for _, tt := range tests {
+		if got := extractPage(t, tt.page).ScriptureReference; got != tt.want {
+			t.Errorf("%s: ScriptureReference = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewDefaultExtractorBookPattern(t *testing.T) {
+	extractor, err := NewDefaultExtractor(`(?:[1-3]\s?)?[A-Za-z]+(?:\s+of\s+[A-Za-z]+)?`)
+	if err != nil {
+		t.Fatalf("NewDefaultExtractor() error = %v", err)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><body><aside class="w"><h1>Song of Songs 2:1-4 The Rose of Sharon</h1>
+		<p>I am a rose of Sharon, a lily of the valleys, says the beloved in the opening verse.</p>
+		<p>Like a lily among thorns is my darling among the young women of the city.</p>
+		</aside></body></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, ok := extractor.Extract(doc.Find("html"))
+	if !ok || content.ScriptureReference != "Song of Songs 2:1-4" {
+		t.Errorf("ScriptureReference = %q, want Song of Songs 2:1-4", content.ScriptureReference)
+	}
+
+	if _, err := NewDefaultExtractor(`[A-Z`); err == nil {
+		t.Errorf("NewDefaultExtractor() accepted an invalid pattern")
+	}
+}
+
+func TestExtractCombinedWeekendPage(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/esh_weekend.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := extractPage(t, string(fixture))
+	if !content.Combined || len(content.Entries) != 2 {
+		t.Fatalf("Combined = %v with %d entries, want two entries", content.Combined, len(content.Entries))
+	}
+	want := []struct{ reference, title string }{
+		{"Mazmur 23:1-6", "Tuhan Gembalaku"},
+		{"Yohanes 10:11-15", "Gembala yang Baik"},
+	}
+	for i, entry := range content.Entries {
+		if entry.ScriptureReference != want[i].reference || entry.DevotionalTitle != want[i].title {
+			t.Errorf("entry %d = %q %q, want %q %q", i, entry.ScriptureReference, entry.DevotionalTitle, want[i].reference, want[i].title)
+		}
+		if len(entry.DevotionalContent) != 2 {
+			t.Errorf("entry %d paragraphs = %q, want 2", i, entry.DevotionalContent)
+		}
+	}
+	if got := len(content.DevotionalContent); got != 4 {
+		t.Errorf("top-level paragraphs = %d, want both entries' 4", got)
+	}
+	if content.ScriptureReference != "Mazmur 23:1-6; Yohanes 10:11-15" {
+		t.Errorf("ScriptureReference = %q, want the entries' references joined", content.ScriptureReference)
+	}
+
+	single, err := os.ReadFile("testdata/esh_short_ending.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content := extractPage(t, string(single)); content.Combined || len(content.Entries) != 0 {
+		t.Errorf("single-day page: Combined = %v with %d entries, want neither", content.Combined, len(content.Entries))
+	}
+}
+
+func TestExtractCountsFilteredParagraphs(t *testing.T) {
+	content := extractPage(t, `<html><body><aside class="w"><h1>Mazmur 23:1-6 Gembala yang Baik</h1>
+		<p align="center">e-SH edisi akhir pekan</p>
+		<p>Daud mengenal Tuhan sebagai gembala yang memelihara domba-dombanya setiap hari tanpa lelah.</p>
+		<p>Amin.</p>
+		<p>Di padang yang berumput hijau dan di air yang tenang, Tuhan menyegarkan jiwa kita kembali.</p>
+		<p>Mari memberkati pelayanan kami melalui rekening BCA 106.30066.22 atas nama Yayasan.</p>
+		<p>Copyright 2025 Yayasan Lembaga SABDA. Semua hak dilindungi undang-undang.</p>
+		</aside></body></html>`)
+	if content.FiltersApplied == nil {
+		t.Fatalf("FiltersApplied = nil, want the filter counts")
+	}
+	want := models.FilterCounts{Donation: 2, TooShort: 1, Centered: 1}
+	if got := *content.FiltersApplied; got != want {
+		t.Errorf("FiltersApplied = %+v, want %+v", got, want)
+	}
+	if len(content.DevotionalContent) != 2 {
+		t.Errorf("paragraphs = %q, want the 2 kept", content.DevotionalContent)
+	}
+}
+
+func TestExtractReadsTableCellsWithoutParagraphs(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/esh_table.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := extractPage(t, string(fixture))
+	want := []string{
+		"Ketika kita diperhadapkan dengan hal-hal besar, sering kali kita lupa bahwa Allah bekerja melalui hal kecil.",
+		"Biji sesawi adalah benih yang sangat kecil, tetapi ketika tumbuh ia menjadi pohon yang besar.",
+		"Ragi yang sedikit mengkhamiri seluruh adonan; demikian pula kesetiaan kecil kita dipakai Tuhan.",
+	}
+	if strings.Join(content.DevotionalContent, "|") != strings.Join(want, "|") {
+		t.Errorf("paragraphs = %q, want the three cells", content.DevotionalContent)
+	}
+	if content.ParagraphSource == ParagraphsFromText {
+		t.Errorf("ParagraphSource = %q, want the cells read before the text splitter", content.ParagraphSource)
+	}
+	if content.ScriptureReference != "Lukas 13:18-21" || content.DevotionalTitle != "Allah Bekerja Memakai Hal Kecil!" {
+		t.Errorf("reference %q, title %q; want the heading's", content.ScriptureReference, content.DevotionalTitle)
+	}
+}
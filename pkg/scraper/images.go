@@ -0,0 +1,66 @@
+package scraper
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// layoutImagePatterns are URL fragments of images that belong to the page layout or a
+// donation appeal rather than to the devotional
+var layoutImagePatterns = []string{
+	"spacer", "blank.", "pixel", "transparent", "1x1", "clear.gif",
+	"logo", "icon", "button", "btn", "bullet", "arrow",
+	"donasi", "donate", "qris", "bca",
+}
+
+// minImageDimension is the width or height hint below which an image is taken for a spacer,
+// bullet or icon
+const minImageDimension = 50
+
+// contentImages returns the src of every image in container that looks like part of the
+// devotional, in page order without duplicates. Images with a width or height hint below
+// minImageDimension, inline data URIs and URLs matching layoutImagePatterns are left out.
+// The sources are returned as written in the page, possibly relative.
+func contentImages(container *goquery.Selection) []string {
+	var images []string
+	seen := make(map[string]bool)
+	container.Find("img[src]").Each(func(_ int, img *goquery.Selection) {
+		src := strings.TrimSpace(img.AttrOr("src", ""))
+		if src == "" || seen[src] || strings.HasPrefix(strings.ToLower(src), "data:") {
+			return
+		}
+		if isSmallImage(img) || isLayoutImage(src) {
+			return
+		}
+		seen[src] = true
+		images = append(images, src)
+	})
+	return images
+}
+
+// isSmallImage reports whether img declares a width or height below minImageDimension
+func isSmallImage(img *goquery.Selection) bool {
+	for _, attr := range []string{"width", "height"} {
+		value, ok := img.Attr(attr)
+		if !ok {
+			continue
+		}
+		size, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(value), "px"))
+		if err == nil && size < minImageDimension {
+			return true
+		}
+	}
+	return false
+}
+
+func isLayoutImage(src string) bool {
+	lower := strings.ToLower(src)
+	for _, pattern := range layoutImagePatterns {
+		if strings.Contains(lower, pattern) {
+			return true
+		}
+	}
+	return false
+}
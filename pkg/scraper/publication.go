@@ -0,0 +1,186 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Jakarta is the time zone SABDA publishes in
+var Jakarta = time.FixedZone("WIB", 7*60*60)
+
+// Indexing describes how a publication addresses its issues
+type Indexing string
+
+const (
+	// IndexByDate addresses issues by year and MMDD date
+	IndexByDate Indexing = "date"
+	// IndexByEdition addresses issues by edition number
+	IndexByEdition Indexing = "edition"
+)
+
+// DefaultPublication is the publication served when a request does not name one
+const DefaultPublication = "e-sh"
+
+// Publication describes a SABDA publication the scraper knows how to fetch.
+// URL formats use {year}, {month}, {day}, {date} (MMDD) and {edition} placeholders.
+type Publication struct {
+	Code            string
+	Name            string
+	Indexing        Indexing
+	DirectURLFormat string
+	PrintURLFormat  string
+	EditionPattern  *regexp.Regexp
+}
+
+var publications = map[string]Publication{
+	"e-sh": {
+		Code:            "e-sh",
+		Name:            "e-Santapan Harian",
+		Indexing:        IndexByDate,
+		DirectURLFormat: "https://www.sabda.org/publikasi/e-sh/{year}/{month}/{day}",
+		PrintURLFormat:  "https://www.sabda.org/publikasi/e-sh/cetak/?tahun={year}&edisi={date}",
+	},
+	"e-konsel": {
+		Code:            "e-konsel",
+		Name:            "e-Konsel",
+		Indexing:        IndexByEdition,
+		DirectURLFormat: "https://www.sabda.org/publikasi/e-konsel/{edition}",
+		PrintURLFormat:  "https://www.sabda.org/publikasi/e-konsel/cetak/?edisi={edition}",
+		EditionPattern:  regexp.MustCompile(`^\d{1,4}$`),
+	},
+}
+
+// LookupPublication returns the publication registered under code
+func LookupPublication(code string) (Publication, bool) {
+	publication, ok := publications[strings.ToLower(code)]
+	return publication, ok
+}
+
+// PublicationCodes returns the codes of all registered publications in sorted order
+func PublicationCodes() []string {
+	codes := make([]string, 0, len(publications))
+	for code := range publications {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// ValidEdition reports whether edition matches the publication's edition format
+func (p Publication) ValidEdition(edition string) bool {
+	return p.Indexing == IndexByEdition && p.EditionPattern != nil && p.EditionPattern.MatchString(edition)
+}
+
+// Target identifies a single issue of a publication to scrape
+type Target struct {
+	Publication string
+	Year        int
+	Date        string
+	Edition     string
+}
+
+// CacheKey returns a stable cache key for the target. Date-indexed e-SH keys keep
+// the historical sabda_<year>_<MMDD> form so existing cache entries stay valid.
+func (t Target) CacheKey() string {
+	publication := t.publicationCode()
+	if t.Edition != "" {
+		return fmt.Sprintf("sabda_%s_edition_%s", publication, t.Edition)
+	}
+	formattedDate, _ := NormalizeDate(t.Date)
+	if publication == DefaultPublication {
+		return fmt.Sprintf("sabda_%d_%s", t.Year, formattedDate)
+	}
+	return fmt.Sprintf("sabda_%s_%d_%s", publication, t.Year, formattedDate)
+}
+
+// ParseCacheKey returns the target whose CacheKey is key, reporting false for keys CacheKey
+// does not produce
+func ParseCacheKey(key string) (Target, bool) {
+	parts := strings.Split(strings.TrimPrefix(key, "sabda_"), "_")
+	if len(parts) == 2 {
+		parts = append([]string{DefaultPublication}, parts...)
+	}
+	if !strings.HasPrefix(key, "sabda_") || len(parts) != 3 {
+		return Target{}, false
+	}
+	if parts[1] == "edition" {
+		return Target{Publication: parts[0], Edition: parts[2]}, true
+	}
+	year, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Target{}, false
+	}
+	return Target{Publication: parts[0], Year: year, Date: parts[2]}, true
+}
+
+// URLs returns the direct and print URLs for the target
+func (t Target) URLs() (string, string, error) {
+	publication, ok := LookupPublication(t.publicationCode())
+	if !ok {
+		return "", "", fmt.Errorf("unknown publication %q", t.Publication)
+	}
+
+	replacements := []string{"{edition}", t.Edition, "{year}", strconv.Itoa(t.Year)}
+	if publication.Indexing == IndexByDate {
+		formattedDate, ok := NormalizeDate(t.Date)
+		if !ok {
+			return "", "", fmt.Errorf("date must be in MMDD format")
+		}
+		replacements = append(replacements,
+			"{date}", formattedDate,
+			"{month}", formattedDate[:2],
+			"{day}", formattedDate[2:],
+		)
+	} else if !publication.ValidEdition(t.Edition) {
+		return "", "", fmt.Errorf("invalid edition %q for publication %s", t.Edition, publication.Code)
+	}
+
+	replacer := strings.NewReplacer(replacements...)
+	return replacer.Replace(publication.DirectURLFormat), replacer.Replace(publication.PrintURLFormat), nil
+}
+
+// NormalizeDate zero-pads a numeric MMDD date to four digits, so "902" becomes "0902".
+// It reports false, returning date unchanged, for anything other than three or four digits.
+func NormalizeDate(date string) (string, bool) {
+	if len(date) < 3 || len(date) > 4 {
+		return date, false
+	}
+	for _, r := range date {
+		if r < '0' || r > '9' {
+			return date, false
+		}
+	}
+	return strings.Repeat("0", 4-len(date)) + date, true
+}
+
+// InferYear picks the year for a normalized MMDD date that is omitted by the client: the
+// current year of now, or the previous year when the date has not yet occurred this year
+func InferYear(date string, now time.Time) int {
+	month, _ := strconv.Atoi(date[:2])
+	day, _ := strconv.Atoi(date[2:])
+	if time.Month(month) > now.Month() || (time.Month(month) == now.Month() && day > now.Day()) {
+		return now.Year() - 1
+	}
+	return now.Year()
+}
+
+// Issue returns the identifier SABDA addresses the issue by: the edition number, or the MMDD
+// date passed as edisi for date-indexed publications
+func (t Target) Issue() string {
+	if t.Edition != "" {
+		return t.Edition
+	}
+	date, _ := NormalizeDate(t.Date)
+	return date
+}
+
+func (t Target) publicationCode() string {
+	if t.Publication == "" {
+		return DefaultPublication
+	}
+	return strings.ToLower(t.Publication)
+}
@@ -0,0 +1,70 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeDate(t *testing.T) {
+	tests := []struct {
+		date string
+		want string
+		ok   bool
+	}{
+		{"902", "0902", true},
+		{"0902", "0902", true},
+		{"1231", "1231", true},
+		{"92", "92", false},
+		{"09021", "09021", false},
+		{"9 2", "9 2", false},
+		{" 902", " 902", false},
+	}
+	for _, tt := range tests {
+		got, ok := NormalizeDate(tt.date)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("NormalizeDate(%q) = %q, %v; want %q, %v", tt.date, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTargetURLsPadDate(t *testing.T) {
+	for _, date := range []string{"902", "0902"} {
+		target := Target{Year: 2025, Date: date}
+		direct, print, err := target.URLs()
+		if err != nil {
+			t.Fatalf("URLs() for %q error = %v", date, err)
+		}
+		if want := "https://www.sabda.org/publikasi/e-sh/2025/09/02"; direct != want {
+			t.Errorf("direct URL for %q = %s, want %s", date, direct, want)
+		}
+		if want := "https://www.sabda.org/publikasi/e-sh/cetak/?tahun=2025&edisi=0902"; print != want {
+			t.Errorf("print URL for %q = %s, want %s", date, print, want)
+		}
+		if key := target.CacheKey(); key != "sabda_2025_0902" {
+			t.Errorf("CacheKey() for %q = %s, want sabda_2025_0902", date, key)
+		}
+	}
+}
+
+func TestInferYearAroundYearBoundary(t *testing.T) {
+	newYear := time.Date(2026, 1, 1, 0, 30, 0, 0, Jakarta)
+	newYearsEve := time.Date(2025, 12, 31, 23, 30, 0, 0, Jakarta)
+	tests := []struct {
+		date string
+		now  time.Time
+		want int
+	}{
+		{"1231", newYear, 2025},
+		{"0101", newYear, 2026},
+		{"0102", newYear, 2025},
+		{"1231", newYearsEve, 2025},
+		{"0101", newYearsEve, 2025},
+		{"0902", time.Date(2025, 9, 2, 12, 0, 0, 0, Jakarta), 2025},
+		{"0903", time.Date(2025, 9, 2, 12, 0, 0, 0, Jakarta), 2024},
+	}
+	for _, tt := range tests {
+		if got := InferYear(tt.date, tt.now); got != tt.want {
+			t.Errorf("InferYear(%q, %s) = %d, want %d", tt.date, tt.now.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
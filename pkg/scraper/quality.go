@@ -0,0 +1,62 @@
+package scraper
+
+import (
+	"strings"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+// minQualityScore is the score below which extracted content is considered low quality
+const minQualityScore = 50
+
+// QualityScore rates extracted content from 0 to 100 based on the presence of a
+// scripture reference and title and the amount of body text found
+func QualityScore(content *models.DevotionalContent) int {
+	if content == nil {
+		return 0
+	}
+
+	score := 0
+	if content.ScriptureReference != "" {
+		score += 25
+	}
+	if content.DevotionalTitle != "" {
+		score += 15
+	}
+
+	words := 0
+	for _, para := range content.DevotionalContent {
+		words += len(strings.Fields(para))
+	}
+	score += min(40, words/5)
+	score += min(20, len(content.DevotionalContent)*5)
+
+	return score
+}
+
+// QualityWarnings explains why content scores below the quality threshold, listing each
+// missing part. It returns nil for content that meets the threshold.
+func QualityWarnings(content *models.DevotionalContent) []string {
+	if QualityScore(content) >= minQualityScore {
+		return nil
+	}
+	if content == nil || len(content.DevotionalContent) == 0 {
+		return []string{"no content"}
+	}
+
+	var warnings []string
+	if content.ScriptureReference == "" {
+		warnings = append(warnings, "no scripture reference")
+	}
+	if content.DevotionalTitle == "" {
+		warnings = append(warnings, "no devotional title")
+	}
+	words := 0
+	for _, para := range content.DevotionalContent {
+		words += len(strings.Fields(para))
+	}
+	if words < 200 {
+		warnings = append(warnings, "low content")
+	}
+	return warnings
+}
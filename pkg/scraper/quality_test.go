@@ -0,0 +1,29 @@
+package scraper
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+func TestQualityWarnings(t *testing.T) {
+	long := strings.Repeat("Allah bekerja melalui hal kecil dalam hidup kita. ", 40)
+	tests := []struct {
+		name    string
+		content *models.DevotionalContent
+		want    []string
+	}{
+		{"nil", nil, []string{"no content"}},
+		{"no paragraphs", &models.DevotionalContent{ScriptureReference: "Lukas 13:18-21"}, []string{"no content"}},
+		{"short, untitled, unreferenced", &models.DevotionalContent{DevotionalContent: []string{"Renungan singkat hari ini."}}, []string{"no scripture reference", "no devotional title", "low content"}},
+		{"short with title", &models.DevotionalContent{DevotionalTitle: "Hal Kecil", DevotionalContent: []string{"Renungan singkat hari ini."}}, []string{"no scripture reference", "low content"}},
+		{"good", &models.DevotionalContent{ScriptureReference: "Lukas 13:18-21", DevotionalTitle: "Hal Kecil", DevotionalContent: []string{long, long, long, long}}, nil},
+	}
+	for _, tt := range tests {
+		if got := QualityWarnings(tt.content); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: QualityWarnings() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package scraper
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// bibleBooks lists the Indonesian (Terjemahan Baru) book names and their common abbreviations.
+// Numbered books are listed without their number; an optional 1-3 prefix is matched separately.
+var bibleBooks = []string{
+	"Kejadian", "Kej", "Keluaran", "Kel", "Imamat", "Im", "Bilangan", "Bil", "Ulangan", "Ul",
+	"Yosua", "Yos", "Hakim-hakim", "Hak", "Rut", "Samuel", "Sam", "Raja-raja", "Raj",
+	"Tawarikh", "Taw", "Ezra", "Ezr", "Nehemia", "Neh", "Ester", "Est", "Ayub", "Ayb",
+	"Mazmur", "Mzm", "Amsal", "Ams", "Pengkhotbah", "Pkh", "Kidung Agung", "Kid",
+	"Yesaya", "Yes", "Yeremia", "Yer", "Ratapan", "Rat", "Yehezkiel", "Yeh", "Daniel", "Dan",
+	"Hosea", "Hos", "Yoel", "Yl", "Amos", "Am", "Obaja", "Ob", "Yunus", "Yun", "Mikha", "Mi",
+	"Nahum", "Nah", "Habakuk", "Hab", "Zefanya", "Zef", "Hagai", "Hag", "Zakharia", "Za",
+	"Maleakhi", "Mal", "Matius", "Mat", "Markus", "Mrk", "Lukas", "Luk", "Yohanes", "Yoh",
+	"Kisah Para Rasul", "Kis", "Roma", "Rm", "Korintus", "Kor", "Galatia", "Gal", "Efesus", "Ef",
+	"Filipi", "Flp", "Kolose", "Kol", "Tesalonika", "Tes", "Timotius", "Tim", "Titus", "Tit",
+	"Filemon", "Flm", "Ibrani", "Ibr", "Yakobus", "Yak", "Petrus", "Ptr", "Yudas", "Yud",
+	"Wahyu", "Why",
+}
+
+// bookAbbreviations maps each abbreviation in bibleBooks to its full book name
+var bookAbbreviations = map[string]string{
+	"Kej": "Kejadian", "Kel": "Keluaran", "Im": "Imamat", "Bil": "Bilangan", "Ul": "Ulangan",
+	"Yos": "Yosua", "Hak": "Hakim-hakim", "Sam": "Samuel", "Raj": "Raja-raja",
+	"Taw": "Tawarikh", "Ezr": "Ezra", "Neh": "Nehemia", "Est": "Ester", "Ayb": "Ayub",
+	"Mzm": "Mazmur", "Ams": "Amsal", "Pkh": "Pengkhotbah", "Kid": "Kidung Agung",
+	"Yes": "Yesaya", "Yer": "Yeremia", "Rat": "Ratapan", "Yeh": "Yehezkiel", "Dan": "Daniel",
+	"Hos": "Hosea", "Yl": "Yoel", "Am": "Amos", "Ob": "Obaja", "Yun": "Yunus", "Mi": "Mikha",
+	"Nah": "Nahum", "Hab": "Habakuk", "Zef": "Zefanya", "Hag": "Hagai", "Za": "Zakharia",
+	"Mal": "Maleakhi", "Mat": "Matius", "Mrk": "Markus", "Luk": "Lukas", "Yoh": "Yohanes",
+	"Kis": "Kisah Para Rasul", "Rm": "Roma", "Kor": "Korintus", "Gal": "Galatia", "Ef": "Efesus",
+	"Flp": "Filipi", "Kol": "Kolose", "Tes": "Tesalonika", "Tim": "Timotius", "Tit": "Titus",
+	"Flm": "Filemon", "Ibr": "Ibrani", "Yak": "Yakobus", "Ptr": "Petrus", "Yud": "Yudas",
+	"Why": "Wahyu",
+}
+
+// parentheticalRefRegex matches a parenthesized or bracketed group made up only of scripture
+// references, e.g. "(Yohanes 3:16)", "(Yoh. 3:16-18; Rm. 5:8)" or "[1 Kor 13:4,7]"
+var parentheticalRefRegex = buildParentheticalRefRegex()
+
+// scriptureRefRegex matches a single reference inside such a group
+var scriptureRefRegex = regexp.MustCompile(scriptureRefPattern())
+
+func scriptureRefPattern() string {
+	books := append([]string(nil), bibleBooks...)
+	sort.Slice(books, func(i, j int) bool { return len(books[i]) > len(books[j]) })
+	for i, book := range books {
+		books[i] = regexp.QuoteMeta(book)
+	}
+	verses := `\d+(?:\s*[-–]\s*\d+)?`
+	return `(?:[1-3]\s*)?(?:` + strings.Join(books, "|") + `)\.?\s*\d+(?::` + verses + `(?:\s*,\s*` + verses + `)*)?`
+}
+
+func buildParentheticalRefRegex() *regexp.Regexp {
+	ref := scriptureRefPattern()
+	list := ref + `(?:\s*[;,]\s*` + ref + `)*`
+	return regexp.MustCompile(`\s*(?:\(\s*` + list + `\s*\)|\[\s*` + list + `\s*\])`)
+}
+
+// StripScriptureReferences removes parenthesized scripture citations from text and returns the
+// cleaned text with the references found, in order. References in running prose are left alone.
+func StripScriptureReferences(text string) (string, []string) {
+	var refs []string
+	for _, group := range parentheticalRefRegex.FindAllString(text, -1) {
+		refs = append(refs, scriptureRefRegex.FindAllString(group, -1)...)
+	}
+	if len(refs) == 0 {
+		return text, nil
+	}
+
+	cleaned := parentheticalRefRegex.ReplaceAllString(text, "")
+	return strings.TrimSpace(cleaned), refs
+}
+
+// referenceRegex splits one reference into its optional book number, book name and the
+// chapter/verse part, tolerating missing or extra spacing, e.g. "1Kor 13 : 4" or "Yohanes3:16"
+var referenceRegex = regexp.MustCompile(`^(?:([1-3])\s*)?(\p{L}[\p{L}\s.\-]*?)\s*(\d.*)$`)
+
+// referenceSeparatorRegex matches the colon, range hyphen and verse comma of the chapter/verse part
+var referenceSeparatorRegex = regexp.MustCompile(`\s*([:,-])\s*`)
+
+// CanonicalReference rewrites a scripture reference in canonical form: single spaces between
+// book number, book and chapter, and no spaces around ":", "-" or ",", with en and em dashes
+// as "-". "Yohanes3:16 - 18" becomes "Yohanes 3:16-18". Multiple references separated by ";"
+// are canonicalized individually. Text that does not look like a reference is only trimmed.
+func CanonicalReference(ref string) string {
+	parts := strings.Split(ref, ";")
+	for i, part := range parts {
+		part = strings.Join(strings.Fields(part), " ")
+		match := referenceRegex.FindStringSubmatch(part)
+		if match == nil {
+			parts[i] = part
+			continue
+		}
+
+		verses := strings.NewReplacer("–", "-", "—", "-").Replace(match[3])
+		canonical := match[2] + " " + referenceSeparatorRegex.ReplaceAllString(verses, "$1")
+		if match[1] != "" {
+			canonical = match[1] + " " + canonical
+		}
+		parts[i] = canonical
+	}
+	return strings.Join(parts, "; ")
+}
+
+// chapterVersesRegex matches the canonical chapter/verse part of a single reference, e.g.
+// "3", "3:16" or "3:16-18,20"
+var chapterVersesRegex = regexp.MustCompile(`^(\d+)(?::(\d+(?:[-,]\d+)*))?$`)
+
+// Reference is a single scripture reference split into its parts
+type Reference struct {
+	// Book includes the book number, e.g. "1 Korintus"
+	Book    string
+	Chapter string
+	// Verses is empty for a whole chapter, e.g. "16-18" or "4,7"
+	Verses string
+}
+
+// ParseReference splits a single scripture reference such as "Yohanes 3:16-18" into book,
+// chapter and verses, tolerating the spacing CanonicalReference fixes. Lists of references
+// and text that is not a reference are rejected.
+func ParseReference(ref string) (Reference, bool) {
+	canonical := CanonicalReference(ref)
+	if strings.Contains(canonical, ";") {
+		return Reference{}, false
+	}
+	match := referenceRegex.FindStringSubmatch(canonical)
+	if match == nil {
+		return Reference{}, false
+	}
+	chapterVerses := chapterVersesRegex.FindStringSubmatch(match[3])
+	if chapterVerses == nil {
+		return Reference{}, false
+	}
+
+	book := strings.TrimRight(match[2], ".")
+	if match[1] != "" {
+		book = match[1] + " " + book
+	}
+	return Reference{Book: book, Chapter: chapterVerses[1], Verses: chapterVerses[2]}, true
+}
+
+// FullBookName expands an abbreviated book as returned in Reference.Book, keeping its book
+// number, e.g. "1 Kor" becomes "1 Korintus". Full and unknown names are returned unchanged.
+func FullBookName(book string) string {
+	number, name := "", book
+	if len(book) > 2 && book[0] >= '1' && book[0] <= '3' && book[1] == ' ' {
+		number, name = book[:2], book[2:]
+	}
+	if full, ok := bookAbbreviations[name]; ok {
+		return number + full
+	}
+	return book
+}
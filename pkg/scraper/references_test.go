@@ -0,0 +1,23 @@
+package scraper
+
+import "testing"
+
+func TestCanonicalReference(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"Yohanes3:16 - 18", "Yohanes 3:16-18"},
+		{"Yohanes 3 : 16–18", "Yohanes 3:16-18"},
+		{"1Kor 13:4 , 7", "1 Kor 13:4,7"},
+		{"1 Korintus 13:4-7", "1 Korintus 13:4-7"},
+		{"Yoh. 3:16; Rm.5:8", "Yoh. 3:16; Rm. 5:8"},
+		{"  Mazmur   23  ", "Mazmur 23"},
+		{"Renungan  harian", "Renungan harian"},
+	}
+	for _, tt := range tests {
+		if got := CanonicalReference(tt.ref); got != tt.want {
+			t.Errorf("CanonicalReference(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
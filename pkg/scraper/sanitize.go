@@ -0,0 +1,34 @@
+package scraper
+
+import (
+	"html"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// htmlPolicy keeps inline formatting and links while dropping scripts, styles and layout markup
+var htmlPolicy = newHTMLPolicy()
+
+func newHTMLPolicy() *bluemonday.Policy {
+	policy := bluemonday.NewPolicy()
+	policy.AllowElements("b", "strong", "i", "em", "u", "br", "sup", "sub", "span")
+	policy.AllowAttrs("href").OnElements("a")
+	policy.AllowStandardURLs()
+	policy.RequireNoFollowOnLinks(true)
+	policy.AddTargetBlankToFullyQualifiedLinks(true)
+	return policy
+}
+
+// sanitizeHTML reduces scraped paragraph markup to the safe inline subset
+func sanitizeHTML(raw string) string {
+	return htmlPolicy.Sanitize(raw)
+}
+
+// escapeParagraphs renders plain-text paragraphs as HTML for the text-based extraction path
+func escapeParagraphs(paragraphs []string) []string {
+	escaped := make([]string, len(paragraphs))
+	for i, para := range paragraphs {
+		escaped[i] = html.EscapeString(para)
+	}
+	return escaped
+}
@@ -1,18 +1,24 @@
 package scraper
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
 	"log"
 	"math/rand"
-	"regexp"
+	"net/http"
+	neturl "net/url"
+	"runtime/debug"
 	"strings"
 	"time"
-	"github.com/PuerkitoBio/goquery"
-	"github.com/gocolly/colly/v2"
-	"github.com/pranahonk/sabda-scraper-go/internal/models"
+	"unicode/utf8"
 )
 
-
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -20,30 +26,117 @@ func min(a, b int) int {
 	return b
 }
 
+// contentKey is the colly context key holding the content being extracted for a request
+const contentKey = "content"
+
+// statusKey is the colly context key holding the upstream HTTP status of a request
+const statusKey = "status"
+
+// extractErrKey is the colly context key holding the error from a panic during extraction
+const extractErrKey = "extract_error"
+
+// printLinkKey is the colly context key holding the absolute URL of the page's own print link
+const printLinkKey = "print_link"
+
+// rawKey is the colly context key marking a request whose response body should be kept unparsed
+const rawKey = "raw"
+
+// Colly context keys holding the correlation identifiers of the originating API request
+const (
+	requestIDKey   = "request_id"
+	traceParentKey = "traceparent"
+)
+
+// finalURLKey is the colly context key holding the URL a request ended at after redirects
+const finalURLKey = "final_url"
+
+// publicationKey is the colly context key holding the code of the publication being scraped
+const publicationKey = "publication"
+
+// scrapeContextKey is the colly context key holding the context of the scrape that issued a request
+const scrapeContextKey = "scrape_context"
+
+// ifModifiedSinceKey is the colly context key holding the time sent as If-Modified-Since
+const ifModifiedSinceKey = "if_modified_since"
+
+// ErrNotFound is returned when SABDA has no devotional published for the requested date
+var ErrNotFound = errors.New("devotional not found")
+
+// ErrParseFailure is returned when SABDA served a page but no devotional could be extracted from it
+var ErrParseFailure = errors.New("devotional could not be extracted")
+
+// ErrNotModified is returned by a conditional scrape when SABDA reports the page unchanged
+var ErrNotModified = errors.New("devotional not modified")
 
 type SABDAScraper struct {
-	collector *colly.Collector
+	transport       *contextTransport
+	collector       *colly.Collector
+	extractors      []Extractor
+	printFallback   bool
+	excerptLength   int
+	canonicalRefs   bool
+	followPrintLink bool
+	extractImages   bool
+	// splitReading keeps the scripture reading and reflection apart on print-layout pages
+	splitReading bool
+	// trimRules drop boilerplate paragraphs from the ends of each devotional
+	trimRules trimRules
+	// Paragraphs shorter than shortParagraph runes are merged with the next while the result
+	// stays within maxMergedParagraph; zero shortParagraph disables merging
+	shortParagraph     int
+	maxMergedParagraph int
+	// publicationExtractors replace extractors for publications with configured content selectors
+	publicationExtractors map[string][]Extractor
+	// 200 responses yielding fewer than emptyMinWords words, such as anti-bot interstitials, are
+	// fetched again up to emptyRetries times, emptyRetryDelay apart; zero emptyMinWords disables this
+	emptyMinWords   int
+	emptyRetries    int
+	emptyRetryDelay time.Duration
 }
 
+// SetExtractors replaces the extraction strategies, tried in order until one accepts the page.
+// Publications with configured content selectors keep their own extractor. It must be called
+// before scraping starts.
+func (s *SABDAScraper) SetExtractors(extractors ...Extractor) {
+	s.extractors = extractors
+}
 
-func New(debug bool) *SABDAScraper {
+// Result describes the outcome of a single scrape
+type Result struct {
+	Content      *models.DevotionalContent
+	URL          string
+	UsedFallback bool
+	LowQuality   bool
+}
+
+func New(debug bool, cfg models.ScraperConfig) *SABDAScraper {
 	c := colly.NewCollector(
 		colly.UserAgent("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"),
+		colly.AllowURLRevisit(),
 	)
 
-	
-
-	
 	c.Limit(&colly.LimitRule{
 		DomainGlob:  "*",
 		Parallelism: 1,
 		Delay:       1 * time.Second,
 	})
 
-	
+	// Redirects from restructured URL schemes are followed up to the configured number of hops
+	c.SetRedirectHandler(func(req *http.Request, via []*http.Request) error {
+		from := via[len(via)-1].URL
+		if !cfg.FollowRedirects || len(via) > cfg.MaxRedirects {
+			log.Printf("Not following redirect from %s to %s (follow_redirects=%t, max_redirects=%d)", from, req.URL, cfg.FollowRedirects, cfg.MaxRedirects)
+			return http.ErrUseLastResponse
+		}
+		log.Printf("Following redirect from %s to %s", from, req.URL)
+		return nil
+	})
+
+	// Caps each upstream request; callers bound whole scrapes with a context deadline
 	c.SetRequestTimeout(30 * time.Second)
+	transport := &contextTransport{base: http.DefaultTransport}
+	c.WithTransport(transport)
 
-	
 	userAgents := []string{
 		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
 		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
@@ -51,12 +144,10 @@ func New(debug bool) *SABDAScraper {
 		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.1.1 Safari/605.1.15",
 	}
 
-	
 	c.OnRequest(func(r *colly.Request) {
-		
+
 		r.Headers.Set("User-Agent", userAgents[rand.Intn(len(userAgents))])
-		
-		
+
 		r.Headers.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
 		r.Headers.Set("Accept-Language", "id-ID,id;q=0.9,en-US;q=0.8,en;q=0.7")
 		r.Headers.Set("Accept-Encoding", "gzip, deflate, br")
@@ -67,454 +158,571 @@ func New(debug bool) *SABDAScraper {
 		r.Headers.Set("Sec-Fetch-Mode", "navigate")
 		r.Headers.Set("Sec-Fetch-Site", "none")
 		r.Headers.Set("Cache-Control", "max-age=0")
+		if since, ok := r.Ctx.GetAny(ifModifiedSinceKey).(time.Time); ok {
+			r.Headers.Set("If-Modified-Since", since.UTC().Format(http.TimeFormat))
+		}
+
+		if requestID := r.Ctx.Get(requestIDKey); requestID != "" && cfg.RequestIDHeader != "" {
+			r.Headers.Set(cfg.RequestIDHeader, requestID)
+		}
+		if cfg.PropagateTraceparent {
+			traceParent := childTraceParent(r.Ctx.Get(traceParentKey))
+			r.Headers.Set("traceparent", traceParent)
+			log.Printf("Upstream request %s (request %s, traceparent %s)", r.URL, r.Ctx.Get(requestIDKey), traceParent)
+		}
 
-		
-		delay := time.Duration(rand.Intn(2000)+1000) * time.Millisecond
-		time.Sleep(delay)
+		delay := politenessDelay()
+		if ctx, ok := r.Ctx.GetAny(scrapeContextKey).(context.Context); ok {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				r.Abort()
+			}
+		} else {
+			time.Sleep(delay)
+		}
+	})
+
+	c.OnResponse(func(r *colly.Response) {
+		r.Ctx.Put(statusKey, r.StatusCode)
+		r.Ctx.Put(finalURLKey, r.Request.URL.String())
+		if r.Ctx.GetAny(rawKey) != nil {
+			r.Ctx.Put(rawKey, r.Body)
+		}
 	})
 
-	
 	c.OnError(func(r *colly.Response, err error) {
-		log.Printf("Error scraping %s: %v", r.Request.URL, err)
+		if r.StatusCode != http.StatusNotModified {
+			log.Printf("Error scraping %s: %v", r.Request.URL, err)
+		}
+		r.Ctx.Put(statusKey, r.StatusCode)
+		if r.Ctx.GetAny(rawKey) != nil {
+			r.Ctx.Put(rawKey, r.Body)
+		}
 	})
 
-	return &SABDAScraper{
-		collector: c,
+	extractor := DefaultExtractor()
+	bookPattern := DefaultBookPattern
+	if cfg.ScriptureBookPattern != "" {
+		custom, err := NewDefaultExtractor(cfg.ScriptureBookPattern)
+		if err != nil {
+			log.Printf("Ignoring scripture book pattern %q: %v", cfg.ScriptureBookPattern, err)
+		} else {
+			extractor = custom
+			bookPattern = cfg.ScriptureBookPattern
+		}
+	}
+
+	// Publications laid out differently from e-SH read their content from configured containers
+	publicationExtractors := make(map[string][]Extractor)
+	for code, selectors := range cfg.ContentSelectors {
+		custom, err := NewSelectorExtractor(bookPattern, selectors)
+		if err != nil {
+			log.Printf("Ignoring content selectors for %s: %v", code, err)
+			continue
+		}
+		publicationExtractors[strings.ToLower(code)] = []Extractor{custom}
+	}
+
+	s := &SABDAScraper{
+		transport:       transport,
+		collector:       c,
+		extractors:      []Extractor{extractor},
+		printFallback:   !cfg.DisablePrintFallback,
+		excerptLength:   cfg.ExcerptLength,
+		canonicalRefs:   cfg.CanonicalRefs,
+		followPrintLink: cfg.FollowPrintLink,
+		extractImages:   cfg.ExtractImages,
+		splitReading:    cfg.SplitPrintReading,
+		trimRules:       newTrimRules(cfg.LeadingTrimPatterns, cfg.TrailingTrimPatterns),
+
+		publicationExtractors: publicationExtractors,
+		emptyMinWords:         cfg.EmptyResponseMinWords,
+		emptyRetries:          cfg.EmptyResponseRetries,
+		emptyRetryDelay:       cfg.EmptyResponseRetryDelay,
+	}
+	if cfg.MergeShortParagraphs {
+		s.shortParagraph = cfg.ShortParagraphLength
+		s.maxMergedParagraph = cfg.MergedParagraphMaxLength
 	}
+	c.OnHTML("html", s.handleHTML)
+
+	return s
 }
 
+func (s *SABDAScraper) ScrapeContent(ctx context.Context, target Target) (*Result, error) {
+	return s.ScrapeContentSince(ctx, target, time.Time{})
+}
 
-func (s *SABDAScraper) ScrapeContent(year int, date string) (*models.DevotionalContent, error) {
-	
-	formattedDate := fmt.Sprintf("%04s", date)
-	if len(formattedDate) != 4 {
-		return nil, fmt.Errorf("date must be in MMDD format")
-	}
-
-	
-	url := fmt.Sprintf("https://www.sabda.org/publikasi/e-sh/%d/%s/%s", year, formattedDate[:2], formattedDate[2:])
-	printURL := fmt.Sprintf("https://www.sabda.org/publikasi/e-sh/cetak/?tahun=%d&edisi=%s", year, formattedDate)
-	log.Printf("Scraping URL: %s", url)
-
-	var content models.DevotionalContent
-	var scrapingError error
-
-	s.collector.OnHTML("html", func(e *colly.HTMLElement) {
-		
-		title := e.ChildText("title")
-		if title == "" {
-			title = "SABDA Devotional"
-		}
-		content.Title = strings.TrimSpace(title)
-
-		
-		var mainContent *goquery.Selection
-		
-		
-		if sel := e.DOM.Find("aside.w"); sel.Length() > 0 {
-			
-			sel.Each(func(i int, aside *goquery.Selection) {
-				if aside.Find("P").Length() > 0 {
-					mainContent = aside
-					return
-				}
-			})
-		}
-		
-		
-		if mainContent == nil {
-			if sel := e.DOM.Find("td.wj"); sel.Length() > 0 {
-				mainContent = sel.First()
-			} else if sel := e.DOM.Find("table td"); sel.Length() > 0 {
-				
-				var largestCell *goquery.Selection
-				maxLength := 0
-				sel.Each(func(i int, cell *goquery.Selection) {
-					text := strings.TrimSpace(cell.Text())
-					if len(text) > maxLength {
-						maxLength = len(text)
-						largestCell = cell
-					}
-				})
-				if largestCell != nil {
-					mainContent = largestCell
-				}
-			} else {
-				mainContent = e.DOM.Find("body").First()
-			}
-		}
+// ScrapeContentSince scrapes target like ScrapeContent, asking SABDA for the direct page only
+// if it changed after since. It returns ErrNotModified when SABDA answers 304; an upstream
+// that ignores If-Modified-Since gets a full scrape. A zero since makes an unconditional scrape.
+func (s *SABDAScraper) ScrapeContentSince(ctx context.Context, target Target, since time.Time) (*Result, error) {
+	url, printURL, err := target.URLs()
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Scraping URL: %s (request %s)", url, CorrelationFromContext(ctx).RequestID)
 
-		
-		allText := mainContent.Text()
-		log.Printf("Raw text length: %d", len(allText))
-		if len(allText) > 0 {
-			log.Printf("First 500 chars: %s", allText[:min(500, len(allText))])
-		}
-		
-		
-		htmlContent, _ := mainContent.Html()
-		log.Printf("HTML content length: %d", len(htmlContent))
-		
-		lines := strings.Split(allText, "\n")
-		var cleanLines []string
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if line != "" && !s.isHeaderContent(strings.ToLower(line)) {
-				cleanLines = append(cleanLines, line)
-			}
-		}
-		cleanText := strings.Join(cleanLines, "\n")
-		log.Printf("Clean text length: %d", len(cleanText))
-		
-		
-		if len(cleanText) < 100 {
-			log.Printf("Warning: Very little content extracted, page might not have loaded properly")
-		}
-
-		
-		scriptureRef := ""
-		if h1 := e.DOM.Find("h1"); h1.Length() > 0 {
-			h1Text := h1.Text()
-			
-			scriptureRegex := regexp.MustCompile(`\b([A-Za-z]+\s+\d+(?::\d+(?:-\d+)?)?)\b`)
-			if match := scriptureRegex.FindStringSubmatch(h1Text); len(match) > 1 {
-				scriptureRef = match[1]
-			}
-		}
-		
-		
-		if scriptureRef == "" {
-			scriptureRegex := regexp.MustCompile(`\b([A-Za-z]+\s+\d+:\d+(?:-\d+)?)\b`)
-			if match := scriptureRegex.FindStringSubmatch(cleanText); len(match) > 1 {
-				scriptureRef = match[1]
-			}
+	publication := target.publicationCode()
+	direct, directStatus, printLink, err := s.fetch(ctx, publication, url, since)
+	if !since.IsZero() && directStatus == http.StatusNotModified {
+		return nil, fmt.Errorf("%s not modified since %s: %w", url, since.UTC().Format(http.TimeFormat), ErrNotModified)
+	}
+	result := &Result{Content: direct, URL: url}
+	// warnings collects the degraded conditions of this scrape, logged once and reported to clients
+	var warnings []string
+
+	if !s.printFallback {
+		switch {
+		case directStatus == http.StatusNotFound:
+			return nil, fmt.Errorf("no devotional published at %s: %w", url, ErrNotFound)
+		case err != nil:
+			return nil, fmt.Errorf("failed to scrape %s (print fallback disabled): %w", url, err)
+		case len(direct.DevotionalContent) == 0:
+			return nil, fmt.Errorf("no content extracted from %s (print fallback disabled): %w", url, ErrParseFailure)
 		}
-		
-		
-		content.ScriptureReference = scriptureRef
-
-		
-		devotionalTitle := ""
-		if h1 := e.DOM.Find("h1"); h1.Length() > 0 {
-			h1Text := strings.TrimSpace(h1.Text())
-			
-			
-			if scriptureRef == "" {
-				scriptureRegex := regexp.MustCompile(`^([A-Za-z]+\s+\d+(?::\d+(?:-\d+)?)?)(.*)`)
-				if match := scriptureRegex.FindStringSubmatch(h1Text); len(match) > 2 {
-					scriptureRef = strings.TrimSpace(match[1])
-					devotionalTitle = strings.TrimSpace(match[2])
-				}
-			} else {
-				
-				h1Text = strings.ReplaceAll(h1Text, scriptureRef, "")
-				devotionalTitle = strings.TrimSpace(h1Text)
-			}
-			
-			
-			if devotionalTitle != "" {
-				
-				devotionalTitle = regexp.MustCompile(`^-\d+`).ReplaceAllString(devotionalTitle, "")
-				devotionalTitle = strings.TrimSpace(devotionalTitle)
-			}
-			
-			if devotionalTitle != "" && len(devotionalTitle) > 3 {
-				
-			} else if h1Text != "" && len(h1Text) > 3 {
-				
-				h1Text = regexp.MustCompile(`^-\d+`).ReplaceAllString(h1Text, "")
-				devotionalTitle = strings.TrimSpace(h1Text)
-			}
+	} else if err != nil || QualityScore(direct) < minQualityScore {
+		log.Printf("Direct URL failed or low quality content, trying print URL: %s", printURL)
+		printContent, printStatus, usedPrintURL, printErr := s.fetchPrint(ctx, publication, printLink, printURL)
+		switch {
+		case directStatus == http.StatusNotFound &&
+			(printStatus == http.StatusNotFound || (printErr == nil && len(printContent.DevotionalContent) == 0)):
+			return nil, fmt.Errorf("no devotional published at %s: %w", url, ErrNotFound)
+		case printErr != nil && err != nil:
+			return nil, fmt.Errorf("failed to scrape both URLs %s and %s: %w", url, printURL, err)
+		case printErr != nil && len(direct.DevotionalContent) == 0:
+			return nil, fmt.Errorf("no content extracted from %s and print URL %s failed (%v): %w", url, printURL, printErr, ErrParseFailure)
+		case printErr != nil:
+			warnings = append(warnings, "print page failed, partial content kept")
+		case err != nil || QualityScore(printContent) > QualityScore(direct):
+			result.Content = printContent
+			result.URL = usedPrintURL
+			result.UsedFallback = true
+			warnings = append(warnings, "served from print page")
 		}
-		
-		
-		if devotionalTitle == "" {
-			devotionalTitle = s.extractDevotionalTitle(cleanText, scriptureRef)
+		if printLink != "" && printLink != printURL && usedPrintURL != printLink {
+			warnings = append(warnings, "on-page print link failed")
 		}
-		content.DevotionalTitle = devotionalTitle
-		
-		
-		content.ScriptureReference = scriptureRef
+	}
 
-		
-		content.DevotionalContent = s.extractParagraphs(mainContent)
+	// A page that loaded but yielded no paragraphs is a parse failure, not an unpublished date
+	if len(result.Content.DevotionalContent) == 0 {
+		return nil, fmt.Errorf("no content extracted from %s: %w", result.URL, ErrParseFailure)
+	}
 
-		
-		if len(content.DevotionalContent) == 0 {
-			content.DevotionalContent = s.extractParagraphsFromText(cleanText)
+	// Record which page the chosen extractor read, e.g. "default/print"
+	if result.Content.ExtractionMethod != "" {
+		page := "direct"
+		if result.UsedFallback {
+			page = "print"
 		}
+		result.Content.ExtractionMethod += "/" + page
+	}
 
-		
-		content.FullText = s.buildFullText(content.DevotionalContent)
-		content.WordCount = len(strings.Fields(content.FullText))
-		content.ParagraphCount = len(content.DevotionalContent)
-
-		log.Printf("Extracted %d paragraphs from %s", content.ParagraphCount, url)
-	})
-
-	
-	err := s.collector.Visit(url)
-	if err != nil || len(content.DevotionalContent) == 0 {
-		log.Printf("Direct URL failed or no content, trying print URL: %s", printURL)
-		if err := s.collector.Visit(printURL); err != nil {
-			return nil, fmt.Errorf("failed to scrape both URLs %s and %s: %w", url, printURL, err)
-		}
+	// Fall back to the issue the URL addressed when the page shows no edition
+	if result.Content.Edition == "" {
+		result.Content.Edition = target.Issue()
 	}
 
-	if scrapingError != nil {
-		return nil, scrapingError
+	if QualityScore(result.Content) < minQualityScore {
+		result.LowQuality = true
+		warnings = append(warnings, QualityWarnings(result.Content)...)
 	}
 
-	
-	if content.ScriptureReference == "" && len(content.DevotionalContent) == 0 {
-		log.Printf("Warning: Low quality content extracted from %s", url)
+	if len(warnings) > 0 {
+		log.Printf("Degraded scrape of %s: warnings=%q", result.URL, warnings)
 	}
+	result.Content.Warnings = warnings
 
-	return &content, nil
+	return result, nil
 }
 
-func (s *SABDAScraper) extractDevotionalTitle(text, scriptureRef string) string {
-	
-	if scriptureRef != "" {
-		
-		
-		scripturePattern := regexp.MustCompile(regexp.QuoteMeta(scriptureRef) + `([A-Za-z][^,.\n]*?)(?:\s|$)`)
-		match := scripturePattern.FindStringSubmatch(text)
-		if len(match) > 1 {
-			title := strings.TrimSpace(match[1])
-			
-			title = regexp.MustCompile(`^-?\d*`).ReplaceAllString(title, "")  
-			title = regexp.MustCompile(`\s{2,}`).ReplaceAllString(title, " ") 
-			title = strings.TrimSpace(title)
-			
-			if len(title) > 2 && len(title) < 100 {
-				return title
-			}
+// fetchPrint fetches the print version of a page. The page's own print link, when one was
+// found, is tried first so a change in SABDA's URL scheme does not break the fallback; the
+// guessed printURL is used when there is no link or following it fails. It returns the URL
+// the content came from.
+func (s *SABDAScraper) fetchPrint(ctx context.Context, publication, printLink, printURL string) (*models.DevotionalContent, int, string, error) {
+	if printLink != "" && printLink != printURL {
+		log.Printf("Following on-page print link: %s", printLink)
+		content, status, _, err := s.fetch(ctx, publication, printLink, time.Time{})
+		if err == nil && len(content.DevotionalContent) > 0 {
+			return content, status, printLink, nil
 		}
 	}
-	
-	lines := strings.Split(text, "\n")
-	
-	
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		
-		
-		if len(line) < 3 || len(line) > 50 ||
-		   strings.HasPrefix(strings.ToLower(line), "ketika") ||
-		   strings.Contains(strings.ToLower(line), "diperhadapkan") ||
-		   strings.Contains(strings.ToLower(line), "sabda") ||
-		   strings.Contains(strings.ToLower(line), "publikasi") ||
-		   strings.Contains(strings.ToLower(line), "http") ||
-		   strings.Contains(line, scriptureRef) {
-			continue
+	content, status, _, err := s.fetch(ctx, publication, printURL, time.Time{})
+	return content, status, printURL, err
+}
+
+// fetch requests url and extracts its content with the extractors for publication. It also
+// returns the page's print link, if following print links is enabled and the page has one.
+// A 200 response that stays near-empty after the configured retries is returned without
+// content, so it is treated like a page nothing could be extracted from and never cached.
+// A non-zero since is sent as If-Modified-Since.
+func (s *SABDAScraper) fetch(ctx context.Context, publication, url string, since time.Time) (*models.DevotionalContent, int, string, error) {
+	for attempt := 0; ; attempt++ {
+		content, status, printLink, err := s.fetchOnce(ctx, publication, url, since)
+		if err != nil || status != http.StatusOK || s.emptyMinWords <= 0 || !nearEmpty(content, s.emptyMinWords) {
+			return content, status, printLink, err
 		}
-		
-		
-		if regexp.MustCompile(`^[A-Z][a-zA-Z\s!?]*$`).MatchString(line) {
-			return line
+		words := countWords(content.DevotionalContent)
+		if attempt >= s.emptyRetries {
+			log.Printf("Near-empty page from %s (%d words) after %d retries, discarding it", url, words, attempt)
+			return &models.DevotionalContent{FinalURL: content.FinalURL}, status, printLink, nil
+		}
+
+		log.Printf("Near-empty page from %s (%d words), retrying in %s (%d/%d)", url, words, s.emptyRetryDelay, attempt+1, s.emptyRetries)
+		timer := time.NewTimer(s.emptyRetryDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return content, status, printLink, fmt.Errorf("retry of near-empty page %s abandoned: %w", url, ctx.Err())
 		}
 	}
-	
-	return ""
 }
 
-func (s *SABDAScraper) extractParagraphs(selection *goquery.Selection) []string {
-	var paragraphs []string
+// politenessDelay returns the random pause before each upstream request, so scrapes do not
+// hammer sabda.org
+var politenessDelay = func() time.Duration {
+	return time.Duration(rand.Intn(2000)+1000) * time.Millisecond
+}
 
-	
-	selection.Find("p, P").Each(func(i int, p *goquery.Selection) {
-		text := strings.TrimSpace(p.Text())
-		
-		
-		if text == "" || text == "\u00a0" {
-			return
-		}
+// nearEmpty reports whether content looks like an interstitial rather than a devotional: fewer
+// than minWords words across all its paragraphs. A page with a devotional title and several
+// paragraphs is a devotional however short it is.
+func nearEmpty(content *models.DevotionalContent, minWords int) bool {
+	if content.DevotionalTitle != "" && len(content.DevotionalContent) > 1 {
+		return false
+	}
+	return countWords(content.DevotionalContent) < minWords
+}
 
-		
-		if align, exists := p.Attr("align"); exists && align == "center" {
-			return
-		}
+// countWords counts the words across paragraphs
+func countWords(paragraphs []string) int {
+	_, words := FullText(paragraphs)
+	return words
+}
 
-		
-		if s.isDonationContent(text) {
-			return
-		}
+// fetchOnce makes a single request for fetch
+func (s *SABDAScraper) fetchOnce(ctx context.Context, publication, url string, since time.Time) (*models.DevotionalContent, int, string, error) {
+	content := &models.DevotionalContent{}
+	correlation := CorrelationFromContext(ctx)
+	collyCtx := colly.NewContext()
+	collyCtx.Put(contentKey, content)
+	collyCtx.Put(publicationKey, publication)
+	if !since.IsZero() {
+		collyCtx.Put(ifModifiedSinceKey, since)
+	}
+	collyCtx.Put(requestIDKey, correlation.RequestID)
+	collyCtx.Put(traceParentKey, correlation.TraceParent)
 
-		
-		if len(text) < 50 {
-			return
-		}
+	err := s.request(ctx, url, collyCtx)
+	status, _ := collyCtx.GetAny(statusKey).(int)
+	if extractErr, ok := collyCtx.GetAny(extractErrKey).(error); ok && err == nil {
+		err = extractErr
+	}
+	printLink, _ := collyCtx.GetAny(printLinkKey).(string)
+	if finalURL := collyCtx.Get(finalURLKey); finalURL != "" && finalURL != url {
+		content.FinalURL = finalURL
+	}
+	return content, status, printLink, err
+}
 
-		
-		text = regexp.MustCompile(`\s{2,}`).ReplaceAllString(text, " ")
-		paragraphs = append(paragraphs, text)
-	})
+// request sends a GET for url through the collector, bound to ctx: once ctx is done the
+// politeness delay is cut short and the upstream request aborted. Requests still waiting
+// for colly's per-domain limiter only notice when their turn comes.
+func (s *SABDAScraper) request(ctx context.Context, url string, collyCtx *colly.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("scrape of %s not started: %w", url, err)
+	}
+	hdr, release := s.transport.register(ctx)
+	defer release()
+	collyCtx.Put(scrapeContextKey, ctx)
+
+	err := s.collector.Request("GET", url, nil, collyCtx, hdr)
+	if ctxErr := ctx.Err(); ctxErr != nil && err == nil && collyCtx.GetAny(statusKey) == nil {
+		// Aborted during the politeness delay, which colly does not report as an error
+		err = fmt.Errorf("scrape of %s abandoned: %w", url, ctxErr)
+	}
+	return err
+}
+
+// RawPage is an upstream page as fetched, before extraction
+type RawPage struct {
+	URL        string
+	StatusCode int
+	Body       []byte
+}
 
-	
-	if len(paragraphs) <= 1 {
-		log.Println("Using text-based paragraph extraction")
-		paragraphs = s.extractParagraphsFromText(selection.Text())
+// FetchRaw fetches the target's page through the same collector and politeness limits as
+// ScrapeContent, trying the print URL when the direct URL fails unless the print fallback
+// is disabled, and returns it unparsed
+func (s *SABDAScraper) FetchRaw(ctx context.Context, target Target) (*RawPage, error) {
+	url, printURL, err := target.URLs()
+	if err != nil {
+		return nil, err
 	}
 
-	
-	var cleanedParagraphs []string
-	for _, para := range paragraphs {
-		
-		para = regexp.MustCompile(`\s*\[[\w\s]+\]\s*$`).ReplaceAllString(para, "")
-		para = strings.TrimSpace(para)
+	page, err := s.fetchRaw(ctx, url)
+	if err == nil && page.StatusCode == http.StatusOK {
+		return page, nil
+	}
+	if !s.printFallback {
+		if page != nil && page.StatusCode != 0 {
+			return page, nil
+		}
+		return nil, fmt.Errorf("failed to fetch %s (print fallback disabled): %w", url, err)
+	}
 
-		if len(para) > 50 {
-			cleanedParagraphs = append(cleanedParagraphs, para)
+	log.Printf("Raw fetch of %s failed, trying print URL: %s", url, printURL)
+	printPage, printErr := s.fetchRaw(ctx, printURL)
+	if printErr != nil {
+		if page != nil && page.StatusCode != 0 {
+			return page, nil
 		}
+		return nil, fmt.Errorf("failed to fetch both URLs %s and %s: %w", url, printURL, printErr)
 	}
+	return printPage, nil
+}
 
-	return cleanedParagraphs
+func (s *SABDAScraper) fetchRaw(ctx context.Context, url string) (*RawPage, error) {
+	correlation := CorrelationFromContext(ctx)
+	collyCtx := colly.NewContext()
+	collyCtx.Put(rawKey, []byte{})
+	collyCtx.Put(requestIDKey, correlation.RequestID)
+	collyCtx.Put(traceParentKey, correlation.TraceParent)
+
+	err := s.request(ctx, url, collyCtx)
+	status, _ := collyCtx.GetAny(statusKey).(int)
+	body, _ := collyCtx.GetAny(rawKey).([]byte)
+	return &RawPage{URL: url, StatusCode: status, Body: body}, err
 }
 
-func (s *SABDAScraper) extractParagraphsFromText(text string) []string {
-	var paragraphs []string
-	
-	lines := strings.Split(text, "\n")
-	var textLines []string
-	foundContentStart := false
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		lineLower := strings.ToLower(line)
-
-		
-		if !foundContentStart {
-			if strings.Contains(lineLower, "lukas") || strings.Contains(lineLower, "matius") || 
-			   strings.Contains(lineLower, "markus") || strings.Contains(lineLower, "yohanes") {
-				foundContentStart = true
-			}
-			continue
+func (s *SABDAScraper) handleHTML(e *colly.HTMLElement) {
+	content, ok := e.Request.Ctx.GetAny(contentKey).(*models.DevotionalContent)
+	if !ok {
+		return
+	}
+	if s.followPrintLink {
+		if link := findPrintLink(e); link != "" {
+			e.Request.Ctx.Put(printLinkKey, link)
 		}
+	}
 
-		
-		if s.isDonationContent(line) {
-			break
+	// A malformed page must fail this scrape, not crash the process or leave partial results
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic extracting %s: %v\n%s", e.Request.URL, r, debug.Stack())
+			*content = models.DevotionalContent{}
+			e.Request.Ctx.Put(extractErrKey, fmt.Errorf("extraction of %s panicked (%v): %w", e.Request.URL, r, ErrParseFailure))
 		}
+	}()
 
-		
-		if s.isHeaderContent(lineLower) {
+	extractors := s.extractors
+	if custom, ok := s.publicationExtractors[e.Request.Ctx.Get(publicationKey)]; ok {
+		extractors = custom
+	}
+	for _, extractor := range extractors {
+		extracted, ok := extractor.Extract(e.DOM)
+		if !ok {
+			log.Printf("Extractor %s declined %s", extractor.Name(), e.Request.URL)
 			continue
 		}
+		*content = *extracted
+		content.ExtractionMethod = extractor.Name()
+		break
+	}
 
-		
-		if len(line) > 15 {
-			textLines = append(textLines, line)
+	// Image sources are resolved against the page URL as a browser would
+	var images []string
+	if s.extractImages {
+		for _, src := range content.Images {
+			if image := e.Request.AbsoluteURL(src); image != "" {
+				images = append(images, image)
+			}
 		}
 	}
+	content.Images = images
 
-	
-	contentText := strings.Join(textLines, " ")
+	// Only the print layout sets the reading apart reliably; elsewhere a quote in the body
+	// would be mistaken for it
+	if !s.splitReading || !isPrintLayout(e.Request.URL) {
+		content.ScriptureReading, content.Reflection = nil, nil
+	}
 
-	if len(contentText) > 300 {
-		
-		sentences := regexp.MustCompile(`(?:[.!?])\s+(?=[A-Z])`).Split(contentText, -1)
-		var currentPara []string
+	// Canonicalize the reference, keeping the scraped form when that changes it
+	if s.canonicalRefs {
+		if canonical := CanonicalReference(content.ScriptureReference); canonical != content.ScriptureReference {
+			content.ScriptureReferenceRaw = content.ScriptureReference
+			content.ScriptureReference = canonical
+		}
+	}
 
-		for _, sentence := range sentences {
-			sentence = strings.TrimSpace(sentence)
-			if sentence == "" {
-				continue
+	// Combined pages are post-processed per entry, so no paragraph spans two devotionals
+	if len(content.Entries) > 0 {
+		for i := range content.Entries {
+			entry := &content.Entries[i]
+			entry.DevotionalContent, entry.DevotionalHTML = s.trimBoilerplate(content, entry.DevotionalContent, entry.DevotionalHTML)
+			if s.canonicalRefs {
+				entry.ScriptureReference = CanonicalReference(entry.ScriptureReference)
 			}
-
-			currentPara = append(currentPara, sentence)
-
-			
-			if len(strings.Join(currentPara, " ")) > 200 {
-				paraText := strings.Join(currentPara, " ")
-				if len(paraText) > 100 {
-					paragraphs = append(paragraphs, paraText)
-					currentPara = []string{}
-				}
+			if s.shortParagraph > 0 {
+				entry.DevotionalContent, entry.DevotionalHTML = mergeShortParagraphs(entry.DevotionalContent, entry.DevotionalHTML, s.shortParagraph, s.maxMergedParagraph)
 			}
+			entry.WordCount = countWords(entry.DevotionalContent)
+			entry.ParagraphCount = len(entry.DevotionalContent)
 		}
-
-		
-		if len(currentPara) > 0 {
-			paraText := strings.Join(currentPara, " ")
-			if len(paraText) > 100 {
-				paragraphs = append(paragraphs, paraText)
+		content.DevotionalContent, content.DevotionalHTML = flattenEntries(content.Entries)
+	} else {
+		content.DevotionalContent, content.DevotionalHTML = s.trimBoilerplate(content, content.DevotionalContent, content.DevotionalHTML)
+		// The reading only has a beginning and the reflection only an end to trim
+		if content.Reflection != nil {
+			content.ScriptureReading, _, _ = s.trimRules.trim(content.ScriptureReading, nil, true, false)
+			content.Reflection, _, _ = s.trimRules.trim(content.Reflection, nil, false, true)
+		}
+		if s.shortParagraph > 0 {
+			content.DevotionalContent, content.DevotionalHTML = mergeShortParagraphs(content.DevotionalContent, content.DevotionalHTML, s.shortParagraph, s.maxMergedParagraph)
+			if content.Reflection != nil {
+				content.ScriptureReading, _ = mergeShortParagraphs(content.ScriptureReading, nil, s.shortParagraph, s.maxMergedParagraph)
+				content.Reflection, _ = mergeShortParagraphs(content.Reflection, nil, s.shortParagraph, s.maxMergedParagraph)
 			}
 		}
 	}
 
-	
-	if len(paragraphs) <= 1 && len(contentText) > 0 {
-		words := strings.Fields(contentText)
-		if len(words) > 150 {
-			third := len(words) / 3
-			para1 := strings.Join(words[:third], " ")
-			para2 := strings.Join(words[third:2*third], " ")
-			para3 := strings.Join(words[2*third:], " ")
-			
-			paragraphs = []string{
-				strings.TrimSpace(para1),
-				strings.TrimSpace(para2),
-				strings.TrimSpace(para3),
-			}
-		} else if contentText != "" {
-			paragraphs = []string{strings.TrimSpace(contentText)}
-		}
+	content.FullText, content.WordCount = FullText(content.DevotionalContent)
+	content.ParagraphCount = len(content.DevotionalContent)
+	content.ContentHash = contentHash(content.DevotionalContent)
+	if len(content.DevotionalContent) > 0 {
+		content.Excerpt = excerpt(content.DevotionalContent[0], s.excerptLength)
 	}
 
-	return paragraphs
+	log.Printf("Extracted %d paragraphs from %s", content.ParagraphCount, e.Request.URL)
 }
 
+// trimBoilerplate drops navigation and teaser paragraphs from the ends of one devotional of
+// content, counting them in its filters
+func (s *SABDAScraper) trimBoilerplate(content *models.DevotionalContent, paragraphs, html []string) ([]string, []string) {
+	paragraphs, html, dropped := s.trimRules.trim(paragraphs, html, true, true)
+	if dropped > 0 && content.FiltersApplied != nil {
+		content.FiltersApplied.Boilerplate += dropped
+	}
+	return paragraphs, html
+}
 
-func (s *SABDAScraper) buildFullText(paragraphs []string) string {
-	if len(paragraphs) == 0 {
-		return ""
+// isPrintLayout reports whether u is a print-version page, which SABDA serves under /cetak
+// paths such as /publikasi/e-sh/cetak/ and /versi-cetak/
+func isPrintLayout(u *neturl.URL) bool {
+	return strings.Contains(strings.ToLower(u.Path), "cetak")
+}
+
+// printLinkTexts are link texts SABDA uses for the print version of a page, most specific first
+var printLinkTexts = []string{"versi cetak", "cetak", "print"}
+
+// findPrintLink returns the absolute URL of the page's print-version link on the same host:
+// an <a> whose text names the print version or, failing that, whose href points at /cetak/
+func findPrintLink(e *colly.HTMLElement) string {
+	var candidates []string
+	byText := make(map[string]string)
+	e.DOM.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		link := e.Request.AbsoluteURL(href)
+		parsed, err := neturl.Parse(link)
+		if link == "" || err != nil || parsed.Host != e.Request.URL.Host {
+			return
+		}
+		text := strings.ToLower(strings.TrimSpace(a.Text()))
+		for _, want := range printLinkTexts {
+			if strings.Contains(text, want) && byText[want] == "" {
+				byText[want] = link
+			}
+		}
+		if strings.Contains(strings.ToLower(parsed.Path), "/cetak") {
+			candidates = append(candidates, link)
+		}
+	})
+
+	for _, want := range printLinkTexts {
+		if link := byText[want]; link != "" {
+			return link
+		}
 	}
-	
-	
-	if len(paragraphs) > 0 {
-		return paragraphs[len(paragraphs)-1]
+	if len(candidates) > 0 {
+		return candidates[0]
 	}
-	
-	return strings.Join(paragraphs, " ")
+	return ""
 }
 
-func (s *SABDAScraper) isDonationContent(text string) bool {
-	textLower := strings.ToLower(text)
-	donationPatterns := []string{
-		"mari memberkati",
-		"pancar pijar alkitab",
-		"bca 106.30066.22",
-		"yayasan lembaga sabda",
-		"webmaster@",
-		"ylsa.org",
-		"copyright",
-		"© ",
-		"santapan harian",
-	}
+// FullText joins paragraphs into a devotional's full text and counts its words, so full_text
+// and word_count always describe the same paragraphs
+func FullText(paragraphs []string) (string, int) {
+	text := strings.Join(paragraphs, " ")
+	return text, len(strings.Fields(text))
+}
 
-	for _, pattern := range donationPatterns {
-		if strings.Contains(textLower, pattern) {
-			return true
+// mergeShortParagraphs joins each paragraph shorter than minLength runes with the paragraphs
+// that follow it, one at a time, while it stays short and the merged paragraph stays within
+// maxLength runes. html is merged the same way when it is aligned with paragraphs.
+func mergeShortParagraphs(paragraphs, html []string, minLength, maxLength int) ([]string, []string) {
+	aligned := len(html) == len(paragraphs)
+	merged := make([]string, 0, len(paragraphs))
+	var mergedHTML []string
+	for i := 0; i < len(paragraphs); i++ {
+		text, length := paragraphs[i], utf8.RuneCountInString(paragraphs[i])
+		markup := ""
+		if aligned {
+			markup = html[i]
+		}
+		for length < minLength && i+1 < len(paragraphs) {
+			next := utf8.RuneCountInString(paragraphs[i+1])
+			if length+1+next > maxLength {
+				break
+			}
+			i++
+			text += " " + paragraphs[i]
+			length += 1 + next
+			if aligned {
+				markup += " " + html[i]
+			}
+		}
+		merged = append(merged, text)
+		if aligned {
+			mergedHTML = append(mergedHTML, markup)
 		}
 	}
-	return false
+	if !aligned {
+		mergedHTML = html
+	}
+	return merged, mergedHTML
 }
 
-func (s *SABDAScraper) isHeaderContent(text string) bool {
-	headerPatterns := []string{
-		"sabda.org",
-		"publikasi",
-		"versi cetak",
-		"http://",
-		"https://",
-		"halaman ini adalah versi",
+// excerpt shortens text to at most limit characters, cutting at a word boundary, and marks
+// the cut with an ellipsis. Characters are counted as runes so multibyte text is never split. A
+// non-positive limit disables excerpts.
+func excerpt(text string, limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	if len(runes) <= limit {
+		return string(runes)
 	}
 
-	for _, pattern := range headerPatterns {
-		if strings.Contains(text, pattern) {
-			return true
+	cut := runes[:limit]
+	if runes[limit] != ' ' {
+		if space := strings.LastIndex(string(cut), " "); space > 0 {
+			cut = []rune(string(cut)[:space])
 		}
 	}
-	return false
-}
\ No newline at end of file
+	return strings.TrimRight(string(cut), " ,;:-") + "…"
+}
+
+// contentHash fingerprints the devotional body so identical text yields the same hash
+// regardless of whitespace differences between scrapes
+func contentHash(paragraphs []string) string {
+	normalized := strings.Join(strings.Fields(strings.Join(paragraphs, " ")), " ")
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
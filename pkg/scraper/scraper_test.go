@@ -0,0 +1,531 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/pranahonk/sabda-scraper-go/internal/models"
+)
+
+func TestMain(m *testing.M) {
+	politenessDelay = func() time.Duration { return 0 }
+	os.Exit(m.Run())
+}
+
+// stubTransport serves testdata files by URL substring and counts the requests per URL
+type stubTransport struct {
+	mutex    sync.Mutex
+	pages    map[string]string
+	requests map[string]int
+}
+
+func newStubTransport(pages map[string]string) *stubTransport {
+	return &stubTransport{pages: pages, requests: make(map[string]int)}
+}
+
+func (t *stubTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.mutex.Lock()
+	t.requests[r.URL.String()]++
+	t.mutex.Unlock()
+	for fragment, file := range t.pages {
+		if strings.Contains(r.URL.String(), fragment) {
+			body, err := os.ReadFile("testdata/" + file)
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": {"text/html"}}, Body: io.NopCloser(strings.NewReader(string(body))), Request: r}, nil
+		}
+	}
+	return &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("not found")), Request: r}, nil
+}
+
+func (t *stubTransport) count(fragment string) int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	total := 0
+	for url, n := range t.requests {
+		if strings.Contains(url, fragment) {
+			total += n
+		}
+	}
+	return total
+}
+
+func newTestScraper(cfg models.ScraperConfig, transport http.RoundTripper) *SABDAScraper {
+	s := New(false, cfg)
+	s.transport.base = transport
+	return s
+}
+
+var emptyRetryConfig = models.ScraperConfig{
+	EmptyResponseMinWords:   20,
+	EmptyResponseRetries:    1,
+	EmptyResponseRetryDelay: time.Millisecond,
+	DisablePrintFallback:    true,
+}
+
+func TestFetchKeepsDevotionalWithShortLastParagraph(t *testing.T) {
+	transport := newStubTransport(map[string]string{"/e-sh/2025/09/02": "esh_short_ending.html"})
+	s := newTestScraper(emptyRetryConfig, transport)
+
+	result, err := s.ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	if got := len(result.Content.DevotionalContent); got < 2 {
+		t.Errorf("paragraphs = %d, want at least 2", got)
+	}
+	if got := transport.count("/e-sh/2025/09/02"); got != 1 {
+		t.Errorf("requests = %d, want 1 (no near-empty retry)", got)
+	}
+}
+
+func TestScrapeContentFullTextCoversAllParagraphs(t *testing.T) {
+	transport := newStubTransport(map[string]string{"/e-sh/2025/09/02": "esh_short_ending.html"})
+	s := newTestScraper(models.ScraperConfig{DisablePrintFallback: true}, transport)
+
+	result, err := s.ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	content := result.Content
+	if want := strings.Join(content.DevotionalContent, " "); content.FullText != want {
+		t.Errorf("FullText = %q, want all paragraphs %q", content.FullText, want)
+	}
+	if want := len(strings.Fields(content.FullText)); content.WordCount != want {
+		t.Errorf("WordCount = %d, want %d", content.WordCount, want)
+	}
+}
+
+func TestFetchRetriesNearEmptyPage(t *testing.T) {
+	transport := newStubTransport(map[string]string{"/e-sh/2025/09/02": "interstitial.html"})
+	s := newTestScraper(emptyRetryConfig, transport)
+
+	_, err := s.ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+	if !errors.Is(err, ErrParseFailure) {
+		t.Fatalf("ScrapeContent() error = %v, want ErrParseFailure", err)
+	}
+	if got := transport.count("/e-sh/2025/09/02"); got != 2 {
+		t.Errorf("requests = %d, want 2 (one retry)", got)
+	}
+}
+
+func TestNearEmpty(t *testing.T) {
+	tests := []struct {
+		name    string
+		content models.DevotionalContent
+		want    bool
+	}{
+		{"no paragraphs", models.DevotionalContent{}, true},
+		{"one short paragraph", models.DevotionalContent{DevotionalContent: []string{"Mohon tunggu sebentar."}}, true},
+		{"titled devotional with short ending", models.DevotionalContent{DevotionalTitle: "Judul", DevotionalContent: []string{"Satu dua tiga.", "Amin."}}, false},
+		{"words spread over paragraphs", models.DevotionalContent{DevotionalContent: []string{strings.Repeat("kata ", 15), strings.Repeat("kata ", 10)}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nearEmpty(&tt.content, 20); got != tt.want {
+				t.Errorf("nearEmpty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrapeContentWarnings(t *testing.T) {
+	tests := []struct {
+		name  string
+		pages map[string]string
+		want  []string
+	}{
+		{
+			name:  "clean page",
+			pages: map[string]string{"/e-sh/2025/09/02": "esh_short_ending.html"},
+		},
+		{
+			name:  "direct page failed",
+			pages: map[string]string{"/e-sh/cetak/": "esh_short_ending.html"},
+			want:  []string{"served from print page"},
+		},
+		{
+			name:  "degraded page and print page failed",
+			pages: map[string]string{"/e-sh/2025/09/02": "esh_degraded.html"},
+			want:  []string{"print page failed, partial content kept", "no scripture reference", "no devotional title", "low content"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScraper(models.ScraperConfig{}, newStubTransport(tt.pages))
+			result, err := s.ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+			if err != nil {
+				t.Fatalf("ScrapeContent() error = %v", err)
+			}
+			if got := result.Content.Warnings; strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("warnings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrapeContentPrefersBetterPrintPage(t *testing.T) {
+	transport := newStubTransport(map[string]string{
+		"/e-sh/2025/09/02": "esh_degraded.html",
+		"/e-sh/cetak/":     "esh_short_ending.html",
+	})
+	s := newTestScraper(models.ScraperConfig{}, transport)
+
+	result, err := s.ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	if !result.UsedFallback || !strings.Contains(result.URL, "/cetak/") {
+		t.Errorf("URL = %s, UsedFallback = %v; want the print page", result.URL, result.UsedFallback)
+	}
+	if got := result.Content.ScriptureReference; got != "Lukas 13:18-21" {
+		t.Errorf("ScriptureReference = %q, want the print page's Lukas 13:18-21", got)
+	}
+	if result.LowQuality {
+		t.Errorf("LowQuality = true, want the good print content")
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	paragraphs := []string{"Allah bekerja melalui hal kecil.", "Tuhan, ajarlah kami setia. Amin."}
+	hash := contentHash(paragraphs)
+	if len(hash) != 64 {
+		t.Fatalf("contentHash() = %q, want a hex SHA-256", hash)
+	}
+	if again := contentHash([]string{"Allah bekerja melalui hal kecil.", "Tuhan, ajarlah kami setia. Amin."}); again != hash {
+		t.Errorf("identical text hashed to %s and %s", hash, again)
+	}
+	if spaced := contentHash([]string{"  Allah bekerja\tmelalui hal kecil. ", "Tuhan,  ajarlah kami setia.\nAmin."}); spaced != hash {
+		t.Errorf("whitespace-only change altered the hash: %s, want %s", spaced, hash)
+	}
+	if changed := contentHash([]string{"Allah bekerja melalui hal besar.", "Tuhan, ajarlah kami setia. Amin."}); changed == hash {
+		t.Errorf("changed text kept the hash %s", hash)
+	}
+}
+
+// panickingExtractor fails on every page the way a nil dereference in extraction would
+type panickingExtractor struct{}
+
+func (panickingExtractor) Name() string { return "panicking" }
+
+func (panickingExtractor) Extract(*goquery.Selection) (*models.DevotionalContent, bool) {
+	var content *models.DevotionalContent
+	return content, content.Title != ""
+}
+
+func TestScrapeContentFailsCleanlyOnMalformedPages(t *testing.T) {
+	tests := []struct {
+		name       string
+		extractors []Extractor
+	}{
+		{"no body or tables", nil},
+		{"panicking extractor", []Extractor{panickingExtractor{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScraper(models.ScraperConfig{DisablePrintFallback: true}, newStubTransport(map[string]string{"/e-sh/2025/09/02": "malformed.html"}))
+			if tt.extractors != nil {
+				s.extractors = tt.extractors
+			}
+			_, err := s.ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+			if !errors.Is(err, ErrParseFailure) {
+				t.Errorf("ScrapeContent() error = %v, want ErrParseFailure", err)
+			}
+		})
+	}
+}
+
+func TestDisabledPrintFallbackNeverScrapesPrintPage(t *testing.T) {
+	transport := newStubTransport(map[string]string{"/e-sh/cetak/": "esh_short_ending.html"})
+	s := newTestScraper(models.ScraperConfig{DisablePrintFallback: true}, transport)
+
+	_, err := s.ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("ScrapeContent() error = %v, want ErrNotFound", err)
+	}
+	if got := transport.count("/cetak/"); got != 0 {
+		t.Errorf("print page requests = %d, want 0", got)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  string
+	}{
+		{"short", "Allah bekerja.", 40, "Allah bekerja."},
+		{"word boundary", "Allah bekerja melalui hal kecil", 16, "Allah bekerja…"},
+		{"cut at a space", "Allah bekerja melalui hal kecil", 13, "Allah bekerja…"},
+		{"trailing punctuation", "Allah bekerja, melalui hal kecil", 15, "Allah bekerja…"},
+		{"multibyte", "Kasih—yang sejati—tidak pernah gagal", 20, "Kasih—yang…"},
+		{"whitespace collapsed", "Allah\n\tbekerja   melalui", 40, "Allah bekerja melalui"},
+		{"single long word", "Mahakuasa", 4, "Maha…"},
+		{"disabled", "Allah bekerja.", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := excerpt(tt.text, tt.limit); got != tt.want {
+			t.Errorf("%s: excerpt(%q, %d) = %q, want %q", tt.name, tt.text, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestScrapeContentEdition(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    string
+	}{
+		{"esh_short_ending.html", "2 Sep 2025"},
+		{"esh_degraded.html", "0902"},
+	}
+	for _, tt := range tests {
+		s := newTestScraper(models.ScraperConfig{DisablePrintFallback: true}, newStubTransport(map[string]string{"/e-sh/2025/09/02": tt.fixture}))
+		result, err := s.ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+		if err != nil {
+			t.Fatalf("%s: ScrapeContent() error = %v", tt.fixture, err)
+		}
+		if got := result.Content.Edition; got != tt.want {
+			t.Errorf("%s: Edition = %q, want %q", tt.fixture, got, tt.want)
+		}
+	}
+}
+
+func TestScrapeContentFollowsOnPagePrintLink(t *testing.T) {
+	transport := newStubTransport(map[string]string{
+		"/e-sh/2025/09/02":             "esh_print_link.html",
+		"/versi-cetak/e-sh/2025-09-02": "esh_short_ending.html",
+	})
+	s := newTestScraper(models.ScraperConfig{FollowPrintLink: true}, transport)
+
+	result, err := s.ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	if !result.UsedFallback || !strings.Contains(result.URL, "/versi-cetak/e-sh/2025-09-02") {
+		t.Errorf("URL = %s, UsedFallback = %v; want the on-page print link", result.URL, result.UsedFallback)
+	}
+	if got := transport.count("alkitab.sabda.org"); got != 0 {
+		t.Errorf("off-host print link requested %d times, want 0", got)
+	}
+	if got := transport.count("/e-sh/cetak/"); got != 0 {
+		t.Errorf("guessed print URL requested %d times, want 0 once the link worked", got)
+	}
+	if got := result.Content.ScriptureReference; got != "Lukas 13:18-21" {
+		t.Errorf("ScriptureReference = %q, want the print page's Lukas 13:18-21", got)
+	}
+}
+
+func TestScrapeContentMergesShortParagraphs(t *testing.T) {
+	transport := newStubTransport(map[string]string{"/e-sh/2025/09/02": "esh_fragmented.html"})
+	unmerged, err := newTestScraper(models.ScraperConfig{DisablePrintFallback: true}, transport).ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	if got := len(unmerged.Content.DevotionalContent); got < 3 {
+		t.Fatalf("paragraphs without merging = %d, want the fixture's fragments kept: %q", got, unmerged.Content.DevotionalContent)
+	}
+
+	cfg := models.ScraperConfig{DisablePrintFallback: true, MergeShortParagraphs: true, ShortParagraphLength: 80, MergedParagraphMaxLength: 600}
+	merged, err := newTestScraper(cfg, transport).ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	paragraphs := merged.Content.DevotionalContent
+	want := unmerged.Content.DevotionalContent[0] + " " + unmerged.Content.DevotionalContent[1]
+	if len(paragraphs) != len(unmerged.Content.DevotionalContent)-1 || paragraphs[0] != want {
+		t.Fatalf("merged paragraphs = %q, want the two short openers joined", paragraphs)
+	}
+	if len(merged.Content.DevotionalHTML) != len(paragraphs) {
+		t.Errorf("devotional_html has %d entries for %d paragraphs", len(merged.Content.DevotionalHTML), len(paragraphs))
+	}
+	if merged.Content.ParagraphCount != len(paragraphs) || merged.Content.WordCount != unmerged.Content.WordCount {
+		t.Errorf("ParagraphCount = %d, WordCount = %d; want %d and %d", merged.Content.ParagraphCount, merged.Content.WordCount, len(paragraphs), unmerged.Content.WordCount)
+	}
+}
+
+func TestMergeShortParagraphsStopsAtMaxLength(t *testing.T) {
+	paragraphs := []string{"Pendek.", "Juga pendek.", "Kalimat ketiga yang cukup panjang."}
+	merged, _ := mergeShortParagraphs(paragraphs, nil, 20, 25)
+	want := []string{"Pendek. Juga pendek.", "Kalimat ketiga yang cukup panjang."}
+	if strings.Join(merged, "|") != strings.Join(want, "|") {
+		t.Errorf("mergeShortParagraphs() = %q, want %q", merged, want)
+	}
+}
+
+// redirectTransport answers URLs containing a key of hops with a 301 to its value and
+// hands every other request to pages
+type redirectTransport struct {
+	hops  map[string]string
+	pages http.RoundTripper
+}
+
+func (t redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	for fragment, location := range t.hops {
+		if strings.Contains(r.URL.String(), fragment) {
+			header := http.Header{"Location": {location}}
+			return &http.Response{StatusCode: http.StatusMovedPermanently, Header: header, Body: io.NopCloser(strings.NewReader("")), Request: r}, nil
+		}
+	}
+	return t.pages.RoundTrip(r)
+}
+
+func TestScrapeContentFollowsRedirects(t *testing.T) {
+	transport := redirectTransport{
+		hops: map[string]string{
+			"/e-sh/2025/09/02": "https://www.sabda.org/lama/1",
+			"/lama/1":          "https://www.sabda.org/lama/2",
+			"/lama/2":          "https://www.sabda.org/publikasi/esh/2025-09-02",
+		},
+		pages: newStubTransport(map[string]string{"/publikasi/esh/2025-09-02": "esh_short_ending.html"}),
+	}
+
+	s := newTestScraper(models.ScraperConfig{DisablePrintFallback: true, FollowRedirects: true, MaxRedirects: 5}, transport)
+	result, err := s.ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	if got := result.Content.FinalURL; got != "https://www.sabda.org/publikasi/esh/2025-09-02" {
+		t.Errorf("FinalURL = %q, want the last hop", got)
+	}
+
+	for _, cfg := range []models.ScraperConfig{
+		{DisablePrintFallback: true, FollowRedirects: true, MaxRedirects: 2},
+		{DisablePrintFallback: true, FollowRedirects: false, MaxRedirects: 5},
+	} {
+		if _, err := newTestScraper(cfg, transport).ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"}); err == nil {
+			t.Errorf("follow_redirects=%t max_redirects=%d: scraped through a chain of 3 redirects", cfg.FollowRedirects, cfg.MaxRedirects)
+		}
+	}
+}
+
+func TestScrapeContentUsesPublicationSelectors(t *testing.T) {
+	transport := newStubTransport(map[string]string{
+		"/e-konsel/412":    "ekonsel_isi.html",
+		"/e-sh/2025/09/02": "esh_short_ending.html",
+	})
+	cfg := models.ScraperConfig{DisablePrintFallback: true, ContentSelectors: map[string][]string{"e-konsel": {"div.isi", "td.wj"}}}
+	s := newTestScraper(cfg, transport)
+
+	result, err := s.ScrapeContent(context.Background(), Target{Publication: "e-konsel", Edition: "412"})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	content := result.Content
+	if content.ScriptureReference != "Mazmur 46:2-4" || content.DevotionalTitle != "Tempat Perlindungan di Tengah Kecemasan" {
+		t.Errorf("reference %q, title %q; want those of div.isi", content.ScriptureReference, content.DevotionalTitle)
+	}
+	if len(content.DevotionalContent) != 3 {
+		t.Errorf("paragraphs = %q, want the 3 in div.isi", content.DevotionalContent)
+	}
+	for _, paragraph := range content.DevotionalContent {
+		if strings.Contains(paragraph, "Berlangganan") {
+			t.Errorf("paragraph %q comes from outside the configured container", paragraph)
+		}
+	}
+
+	esh, err := s.ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+	if err != nil {
+		t.Fatalf("e-SH ScrapeContent() error = %v", err)
+	}
+	if got := esh.Content.ScriptureReference; got != "Lukas 13:18-21" {
+		t.Errorf("e-SH ScriptureReference = %q, want the default selectors' Lukas 13:18-21", got)
+	}
+}
+
+func TestScrapeContentCapturesContentImages(t *testing.T) {
+	transport := newStubTransport(map[string]string{"/e-sh/2025/09/02": "esh_images.html"})
+	result, err := newTestScraper(models.ScraperConfig{DisablePrintFallback: true, ExtractImages: true}, transport).ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	want := []string{
+		"https://www.sabda.org/images/esh/biji-sesawi.jpg",
+		"https://www.sabda.org/publikasi/e-sh/2025/09/gambar/pohon.jpg",
+	}
+	if strings.Join(result.Content.Images, "|") != strings.Join(want, "|") {
+		t.Errorf("Images = %q, want %q", result.Content.Images, want)
+	}
+
+	disabled, err := newTestScraper(models.ScraperConfig{DisablePrintFallback: true}, transport).ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	if len(disabled.Content.Images) != 0 {
+		t.Errorf("Images = %q with extraction off, want none", disabled.Content.Images)
+	}
+}
+
+func TestScrapeContentSplitsPrintReading(t *testing.T) {
+	target := Target{Year: 2025, Date: "0902"}
+	printPage := newStubTransport(map[string]string{"/e-sh/cetak/": "esh_print.html"})
+	result, err := newTestScraper(models.ScraperConfig{SplitPrintReading: true}, printPage).ScrapeContent(context.Background(), target)
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	content := result.Content
+	if !result.UsedFallback || len(content.ScriptureReading) != 2 || len(content.Reflection) != 2 {
+		t.Fatalf("UsedFallback = %v, reading %q, reflection %q; want two of each from the print page", result.UsedFallback, content.ScriptureReading, content.Reflection)
+	}
+	if !strings.HasPrefix(content.ScriptureReading[0], "Lalu kata Yesus") || !strings.HasPrefix(content.Reflection[0], "Ketika kita") {
+		t.Errorf("reading %q, reflection %q; want the blockquote and the body", content.ScriptureReading, content.Reflection)
+	}
+	if len(content.DevotionalContent) != 4 {
+		t.Errorf("paragraphs = %d, want the combined 4 kept", len(content.DevotionalContent))
+	}
+
+	tests := []struct {
+		name      string
+		cfg       models.ScraperConfig
+		transport *stubTransport
+	}{
+		{"direct page", models.ScraperConfig{SplitPrintReading: true, DisablePrintFallback: true}, newStubTransport(map[string]string{"/e-sh/2025/09/02": "esh_print.html"})},
+		{"setting off", models.ScraperConfig{}, printPage},
+	}
+	for _, tt := range tests {
+		result, err := newTestScraper(tt.cfg, tt.transport).ScrapeContent(context.Background(), target)
+		if err != nil {
+			t.Fatalf("%s: ScrapeContent() error = %v", tt.name, err)
+		}
+		if result.Content.ScriptureReading != nil || result.Content.Reflection != nil {
+			t.Errorf("%s: reading %q, reflection %q; want both empty", tt.name, result.Content.ScriptureReading, result.Content.Reflection)
+		}
+	}
+}
+
+func TestScrapeContentTrimsBoilerplateAtTheEnds(t *testing.T) {
+	transport := newStubTransport(map[string]string{"/e-sh/2025/09/02": "esh_boilerplate.html"})
+	cfg := models.ScraperConfig{
+		DisablePrintFallback: true,
+		LeadingTrimPatterns:  DefaultLeadingTrimPatterns,
+		TrailingTrimPatterns: DefaultTrailingTrimPatterns,
+	}
+	result, err := newTestScraper(cfg, transport).ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	paragraphs := result.Content.DevotionalContent
+	if len(paragraphs) != 3 || !strings.HasPrefix(paragraphs[0], "Ketika kita") || !strings.HasPrefix(paragraphs[1], "Baca juga") || !strings.HasPrefix(paragraphs[2], "Biji sesawi") {
+		t.Errorf("paragraphs = %q, want the date line and trailing teaser trimmed and the middle kept", paragraphs)
+	}
+	if filters := result.Content.FiltersApplied; filters == nil || filters.Boilerplate != 2 {
+		t.Errorf("FiltersApplied = %+v, want 2 boilerplate paragraphs", filters)
+	}
+
+	untrimmed, err := newTestScraper(models.ScraperConfig{DisablePrintFallback: true}, transport).ScrapeContent(context.Background(), Target{Year: 2025, Date: "0902"})
+	if err != nil {
+		t.Fatalf("ScrapeContent() error = %v", err)
+	}
+	if got := len(untrimmed.Content.DevotionalContent); got != 5 {
+		t.Errorf("paragraphs without patterns = %d, want all 5", got)
+	}
+}
@@ -0,0 +1,44 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// scrapeTokenHeader carries the token under which a fetch registered its caller's context.
+// contextTransport removes it before the request leaves.
+const scrapeTokenHeader = "X-Sabda-Scrape-Token"
+
+// contextTransport binds each upstream request to the context of the scrape that issued it,
+// so the caller's deadline or cancellation aborts the request in flight. colly itself only
+// supports one context for the whole collector.
+type contextTransport struct {
+	base     http.RoundTripper
+	next     atomic.Uint64
+	contexts sync.Map
+}
+
+// register makes ctx the context of requests sent with the returned header until release is called
+func (t *contextTransport) register(ctx context.Context) (http.Header, func()) {
+	token := strconv.FormatUint(t.next.Add(1), 10)
+	t.contexts.Store(token, ctx)
+	return http.Header{scrapeTokenHeader: []string{token}}, func() { t.contexts.Delete(token) }
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token := req.Header.Get(scrapeTokenHeader)
+	if token == "" {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	if registered, ok := t.contexts.Load(token); ok {
+		ctx = registered.(context.Context)
+	}
+	req = req.Clone(ctx)
+	req.Header.Del(scrapeTokenHeader)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,83 @@
+package scraper
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+)
+
+// DefaultLeadingTrimPatterns match boilerplate SABDA sometimes leaves as the first paragraph:
+// breadcrumb navigation and the issue's date line
+var DefaultLeadingTrimPatterns = []string{
+	`(?i)^(?:beranda|home)\s*[»›>|/]`,
+	`(?i)^(?:senin|selasa|rabu|kamis|jum'?at|sabtu|minggu),?\s+\d{1,2}\s+\p{L}+\s+\d{4}\b`,
+}
+
+// DefaultTrailingTrimPatterns match teasers SABDA sometimes leaves as the last paragraph,
+// pointing to the next reading or related pages
+var DefaultTrailingTrimPatterns = []string{
+	`(?i)^(?:bacaan|renungan|nas)\s+(?:untuk\s+)?(?:besok|hari berikutnya|selanjutnya)\b`,
+	`(?i)^(?:baca juga|edisi berikutnya|renungan lainnya)\b`,
+	`(?i)^(?:«|<<)?\s*(?:sebelumnya|edisi sebelumnya)\b.*\b(?:berikutnya|selanjutnya)\s*(?:»|>>)?$`,
+}
+
+// trimRules drop boilerplate paragraphs from the ends of a devotional, never its middle
+type trimRules struct {
+	leading  []*regexp.Regexp
+	trailing []*regexp.Regexp
+}
+
+// ValidateTrimPatterns reports the first pattern that is not a valid regular expression
+func ValidateTrimPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// newTrimRules compiles the leading and trailing patterns, skipping invalid ones
+func newTrimRules(leading, trailing []string) trimRules {
+	return trimRules{leading: compileTrimPatterns(leading), trailing: compileTrimPatterns(trailing)}
+}
+
+func compileTrimPatterns(patterns []string) []*regexp.Regexp {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			log.Printf("Ignoring trim pattern %q: %v", pattern, err)
+			continue
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled
+}
+
+// trim drops leading paragraphs matching a leading pattern and trailing ones matching a
+// trailing pattern, along with their HTML when it is aligned, and returns how many it
+// dropped. The last remaining paragraph is always kept.
+func (t trimRules) trim(paragraphs, html []string, leading, trailing bool) ([]string, []string, int) {
+	aligned := len(html) == len(paragraphs)
+	start, end := 0, len(paragraphs)
+	for leading && end-start > 1 && matchesAny(t.leading, paragraphs[start]) {
+		start++
+	}
+	for trailing && end-start > 1 && matchesAny(t.trailing, paragraphs[end-1]) {
+		end--
+	}
+	if aligned {
+		html = html[start:end]
+	}
+	return paragraphs[start:end], html, start + len(paragraphs) - end
+}
+
+func matchesAny(patterns []*regexp.Regexp, text string) bool {
+	for _, re := range patterns {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
@@ -19,10 +19,10 @@ func main() {
 	defer resp.Body.Close()
 
 	fmt.Printf("Health check status: %d\n", resp.StatusCode)
-	
+
 	if resp.StatusCode == 200 {
 		fmt.Println("✅ Health check passed!")
 	} else {
 		fmt.Println("❌ Health check failed!")
 	}
-}
\ No newline at end of file
+}